@@ -45,51 +45,84 @@ func main() {
 	applicationsRepo := repository.NewApplicationsRepository(db.DB)
 	offersRepo := repository.NewOffersRepository(db.DB)
 	bankSubmissionsRepo := repository.NewBankSubmissionsRepository(db.DB)
+	submissionJobsRepo := repository.NewSubmissionJobsRepository(db.DB)
+	applicationEventsRepo := repository.NewApplicationEventsRepository(db.DB)
+	idempotencyKeysRepo := repository.NewIdempotencyKeysRepository(db.DB)
+	webhooksRepo := repository.NewWebhooksRepository(db.DB)
+	transactor := repository.NewTransactor(db.DB)
 	logger.Info("Repositories initialized")
 
 	// Initialize bank services
-	var bankServices []services.BankService
-
-	if cfg.Banks.FastBank.BaseURL != "" {
-		fastBankService := services.NewFastBankService(cfg.Banks.FastBank, logger)
-		bankServices = append(bankServices, fastBankService)
-		logger.Info("FastBank service initialized")
-	} else {
-		logger.Warn("FastBank BaseURL not configured, skipping FastBank integration")
-	}
-
-	if cfg.Banks.SolidBank.BaseURL != "" {
-		solidBankService := services.NewSolidBankService(cfg.Banks.SolidBank, logger)
-		bankServices = append(bankServices, solidBankService)
-		logger.Info("SolidBank service initialized")
-	} else {
-		logger.Warn("SolidBank BaseURL not configured, skipping SolidBank integration")
+	bankServices, err := services.NewBankServices(cfg.Banks, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize bank services")
 	}
 
 	if len(bankServices) == 0 {
 		logger.Fatal("No bank services configured. Please configure at least one bank service.")
 	}
 
-	// Initialize application service with repositories
-	applicationService := services.NewApplicationService(
+	pollSchedules := services.NewPollSchedules(cfg.Banks)
+	webhookPublisher := services.NewWebhookPublisher(applicationsRepo, webhooksRepo)
+	applicationEvents := services.NewApplicationEventRecorder(applicationEventsRepo, webhookPublisher)
+	stateMachine := services.NewApplicationStateMachine(applicationsRepo, transactor, applicationEvents, logger)
+
+	// Initialize submission job workers
+	submissionJobService := services.NewSubmissionJobService(
 		applicationsRepo,
-		offersRepo,
 		bankSubmissionsRepo,
+		submissionJobsRepo,
+		transactor,
+		stateMachine,
+		applicationEvents,
 		bankServices,
+		pollSchedules,
+		services.NewEligibilityRules(cfg.Banks),
+		cfg.SubmissionWorkers,
 		logger,
 	)
-	logger.Info("Application service initialized")
+	submissionWorkerPool := services.NewSubmissionWorkerPool(
+		submissionJobService,
+		cfg.SubmissionWorkers,
+		logger,
+	)
+	logger.Info("Submission worker pool initialized")
 
 	// Initialize submission service
 	submissionService := services.NewSubmissionService(
 		applicationsRepo,
 		offersRepo,
 		bankSubmissionsRepo,
+		transactor,
+		stateMachine,
+		applicationEvents,
 		bankServices,
+		pollSchedules,
+		cfg.SubmissionProcessor,
+		cfg.OfferVerification,
+		cfg.Affordability,
 		logger,
 	)
 	logger.Info("Submission service initialized")
 
+	// Initialize application service with repositories
+	applicationService := services.NewApplicationService(
+		applicationsRepo,
+		submissionJobsRepo,
+		applicationEventsRepo,
+		offersRepo,
+		transactor,
+		stateMachine,
+		applicationEvents,
+		submissionService,
+		bankServices,
+		submissionWorkerPool,
+		cfg.Affordability,
+		cfg.Velocity,
+		logger,
+	)
+	logger.Info("Application service initialized")
+
 	// Initialize submission processor
 	submissionProcessor := services.NewSubmissionProcessor(
 		submissionService,
@@ -98,12 +131,22 @@ func main() {
 	)
 	logger.Info("Submission processor initialized")
 
+	// Initialize application update hub for live status streams
+	applicationUpdates := services.NewApplicationUpdateHub(repository.NewNotificationListener(cfg.Database), logger)
+
+	// Initialize webhook dispatcher
+	webhookDispatcher := services.NewWebhookDispatcher(webhooksRepo, cfg.Webhooks, logger)
+	logger.Info("Webhook dispatcher initialized")
+
 	// Initialize handlers
-	applicationHandler := handlers.NewApplicationHandler(applicationService, logger)
+	applicationHandler := handlers.NewApplicationHandler(applicationService, applicationUpdates, cfg.Stream, cfg.Phone, cfg.Webhooks, logger)
+	adminHandler := handlers.NewAdminHandler(bankServices, logger)
+	webhookHandler := handlers.NewWebhookHandler(services.NewWebhookService(applicationsRepo, webhooksRepo, logger), cfg.Webhooks, logger)
+	idempotencyService := services.NewIdempotencyService(idempotencyKeysRepo, cfg.Idempotency, logger)
 	logger.Info("HTTP handlers initialized")
 
 	// Setup router
-	router := handlers.SetupRouter(applicationHandler, cfg, logger)
+	router := handlers.SetupRouter(applicationHandler, adminHandler, webhookHandler, idempotencyService, cfg, logger)
 	logger.Info("HTTP router configured")
 
 	// Start server
@@ -127,11 +170,26 @@ func main() {
 		}
 	}()
 
+	// Start submission workers, resuming jobs left unfinished by a previous run
+	if err := submissionWorkerPool.Start(); err != nil {
+		logger.WithError(err).Fatal("Failed to start submission worker pool")
+	}
+
 	// Start submission processor
 	if err := submissionProcessor.Start(); err != nil {
 		logger.WithError(err).Fatal("Failed to start submission processor")
 	}
 
+	// Start listening for application updates
+	if err := applicationUpdates.Start(); err != nil {
+		logger.WithError(err).Fatal("Failed to start application update hub")
+	}
+
+	// Start webhook dispatcher
+	if err := webhookDispatcher.Start(); err != nil {
+		logger.WithError(err).Fatal("Failed to start webhook dispatcher")
+	}
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -144,6 +202,16 @@ func main() {
 		logger.WithError(err).Error("Failed to stop submission processor")
 	}
 
+	// Stop webhook dispatcher
+	if err := webhookDispatcher.Stop(); err != nil {
+		logger.WithError(err).Error("Failed to stop webhook dispatcher")
+	}
+
+	// Stop application update hub
+	if err := applicationUpdates.Stop(); err != nil {
+		logger.WithError(err).Error("Failed to stop application update hub")
+	}
+
 	// Give the server 30 seconds to shutdown gracefully
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -154,6 +222,11 @@ func main() {
 		logger.Info("Server shutdown completed")
 	}
 
+	// Stop submission workers
+	if err := submissionWorkerPool.Stop(); err != nil {
+		logger.WithError(err).Error("Failed to stop submission worker pool")
+	}
+
 	// Close database connection
 	if err := db.Close(); err != nil {
 		logger.WithError(err).Error("Failed to close database connection")
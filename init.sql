@@ -10,7 +10,17 @@ CREATE TABLE IF NOT EXISTS applications (
     agree_to_be_scored BOOLEAN NOT NULL,
     amount DECIMAL(12,2) NOT NULL,
     dependents INTEGER DEFAULT 0,
+    term_months INTEGER,
+    purpose VARCHAR(30),
+    currency VARCHAR(3) NOT NULL DEFAULT 'EUR',
+    preferred_payment_day INTEGER,
+    disposable_income DECIMAL(12,2),
+    subsistence_minimum DECIMAL(12,2),
     status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    client_id VARCHAR(100),
+    client_ip VARCHAR(45),
+    callback_url TEXT,
+    callback_secret VARCHAR(255),
     created_at TIMESTAMP DEFAULT NOW(),
     updated_at TIMESTAMP DEFAULT NOW()
 );
@@ -19,12 +29,19 @@ CREATE TABLE IF NOT EXISTS offers (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
     bank_name VARCHAR(100) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'EUR',
     monthly_payment_amount DECIMAL(12,2),
     total_repayment_amount DECIMAL(12,2),
     number_of_payments INTEGER,
     annual_percentage_rate DECIMAL(12,2),
     first_repayment_date VARCHAR(50),
+    verified_annual_percentage_rate DECIMAL(12,2),
+    discrepancies TEXT NOT NULL DEFAULT '',
+    debt_service_ratio DECIMAL(8,4),
+    affordability_issues TEXT NOT NULL DEFAULT '',
     status VARCHAR(20) NOT NULL,
+    acceptance_status VARCHAR(20),
+    acceptance_claimed_at TIMESTAMP,
     created_at TIMESTAMP DEFAULT NOW()
 );
 
@@ -38,8 +55,115 @@ CREATE TABLE IF NOT EXISTS bank_submissions (
     completed_at TIMESTAMP,
     error TEXT,
     error_message TEXT,
+    next_poll_at TIMESTAMP,
+    poll_attempts INTEGER NOT NULL DEFAULT 0,
+    locked_by VARCHAR(100),
+    locked_until TIMESTAMP,
+    withdrawn_at TIMESTAMP,
+    withdrawal_error TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS submission_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    bank_name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    run_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    locked_by VARCHAR(100),
+    locked_until TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (application_id, bank_name)
+);
+
+CREATE TABLE IF NOT EXISTS application_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    entity_type VARCHAR(20) NOT NULL,
+    submission_id UUID,
+    bank_name VARCHAR(100),
+    old_status VARCHAR(20),
+    new_status VARCHAR(20) NOT NULL,
+    actor VARCHAR(50) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key VARCHAR(255) PRIMARY KEY,
+    request_hash VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    response_status INTEGER,
+    response_body BYTEA,
+    locked_by VARCHAR(100),
+    locked_until TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    client_id VARCHAR(100) NOT NULL,
+    url TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    event_types TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID NOT NULL REFERENCES webhook_events(id) ON DELETE CASCADE,
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    subscription_id UUID REFERENCES webhook_subscriptions(id) ON DELETE SET NULL,
+    event_type VARCHAR(50) NOT NULL,
+    url TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP,
+    locked_by VARCHAR(100),
+    locked_until TIMESTAMP,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    delivery_id UUID NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    attempt INTEGER NOT NULL,
+    status_code INTEGER,
+    error TEXT,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
     created_at TIMESTAMP DEFAULT NOW()
 );
 
 CREATE INDEX IF NOT EXISTS idx_offers_application_id ON offers(application_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_offers_application_bank ON offers(application_id, bank_name);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_offers_accepted ON offers(application_id) WHERE acceptance_status = 'ACCEPTED';
 CREATE INDEX IF NOT EXISTS idx_bank_submissions_application_id ON bank_submissions(application_id);
+CREATE INDEX IF NOT EXISTS idx_bank_submissions_due ON bank_submissions(next_poll_at) WHERE status IN ('DRAFT', 'RETRY');
+CREATE INDEX IF NOT EXISTS idx_submission_jobs_due ON submission_jobs(run_at) WHERE status IN ('PENDING', 'RUNNING');
+CREATE INDEX IF NOT EXISTS idx_application_events_application_id ON application_events(application_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+CREATE INDEX IF NOT EXISTS idx_applications_created_at ON applications(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_applications_status_created_at ON applications(status, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_applications_email ON applications(lower(email));
+CREATE INDEX IF NOT EXISTS idx_applications_phone ON applications(phone);
+CREATE INDEX IF NOT EXISTS idx_applications_client_ip ON applications(client_ip, created_at);
+CREATE INDEX IF NOT EXISTS idx_bank_submissions_bank_status ON bank_submissions(bank_name, status);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_client_id ON webhook_subscriptions(client_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_application_id ON webhook_deliveries(application_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery_id ON webhook_delivery_attempts(delivery_id, attempt);
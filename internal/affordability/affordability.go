@@ -0,0 +1,122 @@
+// Package affordability assesses whether a household can carry a loan's
+// monthly payment, as responsible-lending rules require before an offer is
+// recommended.
+//
+// Disposable income is the monthly income left after the monthly expenses,
+// which are taken to be the applicant's existing obligations. The
+// subsistence minimum is what the household needs to live on: a base amount
+// for the applicant plus an allowance per dependent. The debt-service ratio
+// is the share of the income that goes to the expenses and the new payment
+// together.
+package affordability
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/lielamurs/aggregator/internal/money"
+)
+
+var ErrNoIncome = errors.New("household has no income")
+
+// Issue names the rule a payment breaks.
+type Issue string
+
+const (
+	IssueDebtServiceRatio   Issue = "DEBT_SERVICE_RATIO_EXCEEDED"
+	IssueSubsistenceMinimum Issue = "BELOW_SUBSISTENCE_MINIMUM"
+)
+
+// Policy is the lending policy payments are assessed against. Its amounts
+// must be in the household's currency.
+type Policy struct {
+	SubsistenceMinimum  money.Money
+	DependentAllowance  money.Money
+	MaxDebtServiceRatio float64
+}
+
+type Household struct {
+	MonthlyIncome   money.Money
+	MonthlyExpenses money.Money
+	Dependents      int
+}
+
+// Assessment holds the household's figures before any new payment.
+type Assessment struct {
+	DisposableIncome   money.Money
+	SubsistenceMinimum money.Money
+}
+
+// PaymentAssessment holds the household's figures with a new monthly
+// payment and the rules the payment breaks.
+type PaymentAssessment struct {
+	DebtServiceRatio float64
+	RemainingIncome  money.Money
+	Issues           []Issue
+}
+
+func (a *PaymentAssessment) Affordable() bool {
+	return len(a.Issues) == 0
+}
+
+// Assess computes the household's disposable income and subsistence
+// minimum.
+func Assess(household Household, policy Policy) (*Assessment, error) {
+	disposable, err := household.MonthlyIncome.Sub(household.MonthlyExpenses)
+	if err != nil {
+		return nil, err
+	}
+
+	allowances := policy.DependentAllowance.Mul(int64(max(household.Dependents, 0)))
+	subsistence, err := policy.SubsistenceMinimum.Add(allowances)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Assessment{
+		DisposableIncome:   disposable,
+		SubsistenceMinimum: subsistence,
+	}, nil
+}
+
+// AssessPayment checks a new monthly payment against the policy. A zero
+// MaxDebtServiceRatio disables the ratio check.
+func AssessPayment(household Household, payment money.Money, policy Policy) (*PaymentAssessment, error) {
+	if !household.MonthlyIncome.IsPositive() {
+		return nil, ErrNoIncome
+	}
+
+	assessment, err := Assess(household, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining, err := assessment.DisposableIncome.Sub(payment)
+	if err != nil {
+		return nil, fmt.Errorf("payment: %w", err)
+	}
+
+	obligations, err := household.MonthlyExpenses.Add(payment)
+	if err != nil {
+		return nil, fmt.Errorf("payment: %w", err)
+	}
+
+	ratio := float64(obligations.Cents()) / float64(household.MonthlyIncome.Cents())
+	result := &PaymentAssessment{
+		DebtServiceRatio: math.Round(ratio*10000) / 10000,
+		RemainingIncome:  remaining,
+		Issues:           []Issue{},
+	}
+	if policy.MaxDebtServiceRatio > 0 && ratio > policy.MaxDebtServiceRatio {
+		result.Issues = append(result.Issues, IssueDebtServiceRatio)
+	}
+	belowMinimum, err := remaining.Cmp(assessment.SubsistenceMinimum)
+	if err != nil {
+		return nil, fmt.Errorf("subsistence minimum: %w", err)
+	}
+	if belowMinimum < 0 {
+		result.Issues = append(result.Issues, IssueSubsistenceMinimum)
+	}
+	return result, nil
+}
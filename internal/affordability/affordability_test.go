@@ -0,0 +1,69 @@
+package affordability
+
+import (
+	"testing"
+
+	"github.com/lielamurs/aggregator/internal/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eur(value string) money.Money {
+	return money.MustParse(value, money.EUR)
+}
+
+var testPolicy = Policy{
+	SubsistenceMinimum:  eur("500"),
+	DependentAllowance:  eur("250"),
+	MaxDebtServiceRatio: 0.4,
+}
+
+func TestAssess(t *testing.T) {
+	assessment, err := Assess(Household{MonthlyIncome: eur("3000"), MonthlyExpenses: eur("600"), Dependents: 2}, testPolicy)
+	require.NoError(t, err)
+
+	assert.Equal(t, eur("2400"), assessment.DisposableIncome)
+	assert.Equal(t, eur("1000"), assessment.SubsistenceMinimum)
+}
+
+func TestAssessPayment(t *testing.T) {
+	household := Household{MonthlyIncome: eur("3000"), MonthlyExpenses: eur("600"), Dependents: 2}
+
+	tests := []struct {
+		name      string
+		household Household
+		payment   string
+		ratio     float64
+		remaining string
+		issues    []Issue
+	}{
+		{name: "payment within the ratio should be affordable", household: household, payment: "450", ratio: 0.35, remaining: "1950", issues: []Issue{}},
+		{name: "payment at the ratio should be affordable", household: household, payment: "600", ratio: 0.4, remaining: "1800", issues: []Issue{}},
+		{name: "payment above the ratio should be flagged", household: household, payment: "600.01", ratio: 0.4, remaining: "1799.99", issues: []Issue{IssueDebtServiceRatio}},
+		{
+			name:      "payment leaving less than the subsistence minimum should be flagged",
+			household: Household{MonthlyIncome: eur("1500"), MonthlyExpenses: eur("100"), Dependents: 3},
+			payment:   "400",
+			ratio:     0.3333,
+			remaining: "1000",
+			issues:    []Issue{IssueSubsistenceMinimum},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assessment, err := AssessPayment(tt.household, eur(tt.payment), testPolicy)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.ratio, assessment.DebtServiceRatio)
+			assert.Equal(t, eur(tt.remaining), assessment.RemainingIncome)
+			assert.Equal(t, tt.issues, assessment.Issues)
+			assert.Equal(t, len(tt.issues) == 0, assessment.Affordable())
+		})
+	}
+
+	t.Run("household without income should not be assessable", func(t *testing.T) {
+		_, err := AssessPayment(Household{MonthlyExpenses: eur("100")}, eur("100"), testPolicy)
+		assert.ErrorIs(t, err, ErrNoIncome)
+	})
+}
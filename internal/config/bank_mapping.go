@@ -0,0 +1,116 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// BankMapping describes how the generic JSON adapter talks to a bank: which
+// endpoints to call, how our application fields are named on the wire and
+// where the status and offer live in the bank's response. Paths are
+// dot-separated, e.g. "applicant.phoneNumber". AcceptPath and CancelPath are
+// optional; without them offer acceptances and cancellations are not
+// forwarded to the bank.
+type BankMapping struct {
+	SubmitPath string            `json:"submitPath"`
+	PollPath   string            `json:"pollPath"`
+	AcceptPath string            `json:"acceptPath"`
+	CancelPath string            `json:"cancelPath"`
+	Request    map[string]string `json:"request"`
+	Response   ResponseMapping   `json:"response"`
+}
+
+type ResponseMapping struct {
+	ID                string             `json:"id"`
+	Status            string             `json:"status"`
+	ProcessedStatuses []string           `json:"processedStatuses"`
+	Offer             string             `json:"offer"`
+	OfferFields       OfferFieldsMapping `json:"offerFields"`
+}
+
+// OfferFieldsMapping holds paths relative to the offer object.
+type OfferFieldsMapping struct {
+	MonthlyPaymentAmount string `json:"monthlyPaymentAmount"`
+	TotalRepaymentAmount string `json:"totalRepaymentAmount"`
+	NumberOfPayments     string `json:"numberOfPayments"`
+	AnnualPercentageRate string `json:"annualPercentageRate"`
+	FirstRepaymentDate   string `json:"firstRepaymentDate"`
+}
+
+func LoadBankMapping(path string) (*BankMapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bank mapping %s: %w", path, err)
+	}
+
+	return ParseBankMapping(data)
+}
+
+func ParseBankMapping(data []byte) (*BankMapping, error) {
+	mapping := defaultBankMapping()
+	if err := json.Unmarshal(data, mapping); err != nil {
+		return nil, fmt.Errorf("failed to parse bank mapping: %w", err)
+	}
+
+	if err := mapping.validate(); err != nil {
+		return nil, fmt.Errorf("invalid bank mapping: %w", err)
+	}
+
+	return mapping, nil
+}
+
+func defaultBankMapping() *BankMapping {
+	return &BankMapping{
+		SubmitPath: "/applications",
+		PollPath:   "/applications/{id}",
+		Response: ResponseMapping{
+			ID:                "id",
+			Status:            "status",
+			ProcessedStatuses: []string{"PROCESSED"},
+			Offer:             "offer",
+			OfferFields: OfferFieldsMapping{
+				MonthlyPaymentAmount: "monthlyPaymentAmount",
+				TotalRepaymentAmount: "totalRepaymentAmount",
+				NumberOfPayments:     "numberOfPayments",
+				AnnualPercentageRate: "annualPercentageRate",
+				FirstRepaymentDate:   "firstRepaymentDate",
+			},
+		},
+	}
+}
+
+func (m *BankMapping) validate() error {
+	if len(m.Request) == 0 {
+		return fmt.Errorf("request mapping is empty")
+	}
+
+	for target, source := range m.Request {
+		if target == "" || source == "" {
+			return fmt.Errorf("request mapping %q -> %q must name both fields", target, source)
+		}
+	}
+
+	if !strings.Contains(m.PollPath, "{id}") {
+		return fmt.Errorf("pollPath %q must contain the {id} placeholder", m.PollPath)
+	}
+
+	if m.AcceptPath != "" && !strings.Contains(m.AcceptPath, "{id}") {
+		return fmt.Errorf("acceptPath %q must contain the {id} placeholder", m.AcceptPath)
+	}
+
+	if m.CancelPath != "" && !strings.Contains(m.CancelPath, "{id}") {
+		return fmt.Errorf("cancelPath %q must contain the {id} placeholder", m.CancelPath)
+	}
+
+	if m.Response.ID == "" || m.Response.Status == "" || m.Response.Offer == "" {
+		return fmt.Errorf("response id, status and offer paths are required")
+	}
+
+	if len(m.Response.ProcessedStatuses) == 0 {
+		return fmt.Errorf("at least one processed status is required")
+	}
+
+	return nil
+}
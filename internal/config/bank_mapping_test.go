@@ -0,0 +1,182 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseBankMapping(t *testing.T) {
+	mapping, err := ParseBankMapping([]byte(`{
+		"request": {"applicant.phone": "phone", "loanAmount": "amount"}
+	}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mapping.SubmitPath != "/applications" {
+		t.Errorf("Expected default submit path /applications, got %s", mapping.SubmitPath)
+	}
+
+	if mapping.PollPath != "/applications/{id}" {
+		t.Errorf("Expected default poll path /applications/{id}, got %s", mapping.PollPath)
+	}
+
+	if mapping.Request["applicant.phone"] != "phone" {
+		t.Errorf("Expected applicant.phone to map from phone, got %s", mapping.Request["applicant.phone"])
+	}
+
+	if len(mapping.Response.ProcessedStatuses) != 1 || mapping.Response.ProcessedStatuses[0] != "PROCESSED" {
+		t.Errorf("Expected default processed statuses [PROCESSED], got %v", mapping.Response.ProcessedStatuses)
+	}
+
+	if mapping.Response.OfferFields.AnnualPercentageRate != "annualPercentageRate" {
+		t.Errorf("Expected default APR path annualPercentageRate, got %s", mapping.Response.OfferFields.AnnualPercentageRate)
+	}
+}
+
+func TestParseBankMappingOverrides(t *testing.T) {
+	mapping, err := ParseBankMapping([]byte(`{
+		"submitPath": "/v2/loans",
+		"pollPath": "/v2/loans/{id}/decision",
+		"request": {"mobile": "phone"},
+		"response": {
+			"id": "loanId",
+			"status": "decision.state",
+			"processedStatuses": ["APPROVED", "DECLINED"],
+			"offer": "decision.offer",
+			"offerFields": {"annualPercentageRate": "apr"}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mapping.PollPath != "/v2/loans/{id}/decision" {
+		t.Errorf("Expected poll path /v2/loans/{id}/decision, got %s", mapping.PollPath)
+	}
+
+	if mapping.Response.Status != "decision.state" {
+		t.Errorf("Expected status path decision.state, got %s", mapping.Response.Status)
+	}
+
+	if len(mapping.Response.ProcessedStatuses) != 2 {
+		t.Errorf("Expected 2 processed statuses, got %v", mapping.Response.ProcessedStatuses)
+	}
+
+	if mapping.Response.OfferFields.AnnualPercentageRate != "apr" {
+		t.Errorf("Expected APR path apr, got %s", mapping.Response.OfferFields.AnnualPercentageRate)
+	}
+}
+
+func TestParseBankMappingErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		expectedError string
+	}{
+		{
+			name:          "malformed json",
+			data:          `{"request":`,
+			expectedError: "failed to parse bank mapping",
+		},
+		{
+			name:          "empty request mapping",
+			data:          `{}`,
+			expectedError: "request mapping is empty",
+		},
+		{
+			name:          "empty source field",
+			data:          `{"request": {"phone": ""}}`,
+			expectedError: "must name both fields",
+		},
+		{
+			name:          "poll path without placeholder",
+			data:          `{"request": {"phone": "phone"}, "pollPath": "/applications"}`,
+			expectedError: "must contain the {id} placeholder",
+		},
+		{
+			name:          "accept path without placeholder",
+			data:          `{"request": {"phone": "phone"}, "acceptPath": "/applications/accept"}`,
+			expectedError: "must contain the {id} placeholder",
+		},
+		{
+			name:          "cancel path without placeholder",
+			data:          `{"request": {"phone": "phone"}, "cancelPath": "/applications/cancel"}`,
+			expectedError: "must contain the {id} placeholder",
+		},
+		{
+			name:          "missing response id path",
+			data:          `{"request": {"phone": "phone"}, "response": {"id": ""}}`,
+			expectedError: "response id, status and offer paths are required",
+		},
+		{
+			name:          "no processed statuses",
+			data:          `{"request": {"phone": "phone"}, "response": {"processedStatuses": []}}`,
+			expectedError: "at least one processed status is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBankMapping([]byte(tt.data))
+			if err == nil {
+				t.Fatalf("Expected error containing %q, got nil", tt.expectedError)
+			}
+
+			if !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestLoadBankMappingExamples(t *testing.T) {
+	files, err := filepath.Glob("../../mappings/*.json")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(files) == 0 {
+		t.Fatal("Expected example mapping files")
+	}
+
+	for _, file := range files {
+		if _, err := LoadBankMapping(file); err != nil {
+			t.Errorf("Expected %s to be a valid mapping, got %v", file, err)
+		}
+	}
+}
+
+func TestLoadBanksWithMappingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trustbank.json")
+	if err := os.WriteFile(path, []byte(`{"request": {"mobile": "phone"}}`), 0o600); err != nil {
+		t.Fatalf("Failed to write mapping file: %v", err)
+	}
+
+	os.Setenv("TRUSTBANK_ADAPTER", "json")
+	os.Setenv("TRUSTBANK_MAPPING_FILE", path)
+	defer func() {
+		os.Unsetenv("TRUSTBANK_ADAPTER")
+		os.Unsetenv("TRUSTBANK_MAPPING_FILE")
+	}()
+
+	banks, err := loadBanks("TrustBank")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if banks[0].Mapping == nil {
+		t.Fatal("Expected mapping to be loaded")
+	}
+
+	if banks[0].Mapping.Request["mobile"] != "phone" {
+		t.Errorf("Expected mobile to map from phone, got %s", banks[0].Mapping.Request["mobile"])
+	}
+
+	os.Setenv("TRUSTBANK_MAPPING_FILE", filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := loadBanks("TrustBank"); err == nil {
+		t.Error("Expected error for missing mapping file")
+	}
+}
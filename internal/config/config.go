@@ -1,26 +1,50 @@
 package config
 
 import (
+	"fmt"
+	"net"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
+	"github.com/lielamurs/aggregator/internal/money"
 	"github.com/sirupsen/logrus"
 )
 
 type Config struct {
 	Server              ServerConfig              `json:"server"`
 	Database            DatabaseConfig            `json:"database"`
-	Banks               BanksConfig               `json:"banks"`
+	Banks               []BankConfig              `json:"banks"`
 	Logging             LoggingConfig             `json:"logging"`
 	SubmissionProcessor SubmissionProcessorConfig `json:"submission_processor"`
+	SubmissionWorkers   SubmissionWorkersConfig   `json:"submission_workers"`
+	Idempotency         IdempotencyConfig         `json:"idempotency"`
+	Webhooks            WebhooksConfig            `json:"webhooks"`
+	Stream              StreamConfig              `json:"stream"`
+	OfferVerification   OfferVerificationConfig   `json:"offer_verification"`
+	Affordability       AffordabilityConfig       `json:"affordability"`
+	Velocity            VelocityConfig            `json:"velocity"`
+	Phone               PhoneConfig               `json:"phone"`
 }
 
+// ServerConfig holds the HTTP listener settings. The admin API is only
+// served when AdminToken is set, and then requires it as a bearer token.
+// ClientTokens maps the bearer token of each API client to its client ID;
+// webhook subscriptions are only served when it is set. X-Forwarded-For is
+// only honoured from TrustedProxies; without any, the client IP is the
+// address of the connection.
 type ServerConfig struct {
-	Port string `json:"port" env:"SERVER_PORT"`
-	Host string `json:"host" env:"SERVER_HOST"`
+	Port           string            `json:"port" env:"SERVER_PORT"`
+	Host           string            `json:"host" env:"SERVER_HOST"`
+	AdminToken     string            `json:"-" env:"ADMIN_API_TOKEN"`
+	ClientTokens   map[string]string `json:"-" env:"API_CLIENT_TOKENS"`
+	TrustedProxies []*net.IPNet      `json:"trusted_proxies" env:"TRUSTED_PROXIES"`
 }
 
+// maxClientIDLength matches the client_id columns.
+const maxClientIDLength = 100
+
 type DatabaseConfig struct {
 	Host         string `json:"host" env:"DB_HOST"`
 	Port         string `json:"port" env:"DB_PORT"`
@@ -33,19 +57,85 @@ type DatabaseConfig struct {
 	MaxLifetime  int    `json:"max_lifetime" env:"DB_MAX_LIFETIME"`
 }
 
-type BanksConfig struct {
-	FastBank  FastBankConfig  `json:"fastbank"`
-	SolidBank SolidBankConfig `json:"solidbank"`
+// BankConfig describes a single partner bank. Banks are listed in BANKS and
+// each one reads its settings from variables prefixed with its upper-cased
+// name, e.g. FASTBANK_BASE_URL for the bank named FastBank.
+type BankConfig struct {
+	Name           string               `json:"name"`
+	Adapter        string               `json:"adapter"`
+	BaseURL        string               `json:"base_url"`
+	Timeout        int                  `json:"timeout"`
+	Enabled        bool                 `json:"enabled"`
+	MappingFile    string               `json:"mapping_file"`
+	Mapping        *BankMapping         `json:"-"`
+	Currency       money.Currency       `json:"currency"`
+	Retry          RetryConfig          `json:"retry"`
+	CircuitBreaker CircuitBreakerConfig `json:"circuit_breaker"`
+	Poll           PollConfig           `json:"poll"`
+	Eligibility    EligibilityConfig    `json:"eligibility"`
+}
+
+// RetryConfig controls how outbound bank calls are retried. GET requests are
+// always retried; POST requests only when IdempotentSubmit is set or an
+// IdempotencyHeader is configured so the bank can deduplicate them.
+type RetryConfig struct {
+	MaxAttempts          int     `json:"max_attempts"`
+	BaseDelayMs          int     `json:"base_delay_ms"`
+	MaxDelayMs           int     `json:"max_delay_ms"`
+	Jitter               float64 `json:"jitter"`
+	RetryableStatusCodes []int   `json:"retryable_status_codes"`
+	RetryNetworkErrors   bool    `json:"retry_network_errors"`
+	IdempotentSubmit     bool    `json:"idempotent_submit"`
+	IdempotencyHeader    string  `json:"idempotency_header"`
+}
+
+// CircuitBreakerConfig opens the breaker once FailureRate of the last
+// WindowSize calls failed (with at least MinRequests calls seen) and lets
+// HalfOpenRequests probe calls through after CooldownSeconds.
+type CircuitBreakerConfig struct {
+	Enabled          bool    `json:"enabled"`
+	WindowSize       int     `json:"window_size"`
+	MinRequests      int     `json:"min_requests"`
+	FailureRate      float64 `json:"failure_rate"`
+	CooldownSeconds  int     `json:"cooldown_seconds"`
+	HalfOpenRequests int     `json:"half_open_requests"`
+}
+
+// PollConfig schedules offer polls for a submission: the first poll runs
+// InitialDelaySeconds after submission and every following wait grows by
+// BackoffMultiplier, up to MaxIntervalSeconds. A submission without a
+// decision DecisionDeadlineSeconds after it was created times out; zero
+// disables the deadline.
+type PollConfig struct {
+	InitialDelaySeconds     int     `json:"initial_delay_seconds"`
+	BackoffMultiplier       float64 `json:"backoff_multiplier"`
+	MaxIntervalSeconds      int     `json:"max_interval_seconds"`
+	DecisionDeadlineSeconds int     `json:"decision_deadline_seconds"`
 }
 
-type FastBankConfig struct {
-	BaseURL string `json:"base_url" env:"FASTBANK_BASE_URL"`
-	Timeout int    `json:"timeout" env:"FASTBANK_TIMEOUT"`
+// DefaultPollConfig is the poll schedule of banks that do not configure one.
+var DefaultPollConfig = PollConfig{
+	InitialDelaySeconds:     5,
+	BackoffMultiplier:       2,
+	MaxIntervalSeconds:      300,
+	DecisionDeadlineSeconds: 3600,
 }
 
-type SolidBankConfig struct {
-	BaseURL string `json:"base_url" env:"SOLIDBANK_BASE_URL"`
-	Timeout int    `json:"timeout" env:"SOLIDBANK_TIMEOUT"`
+// EligibilityConfig holds a bank's lending criteria. Applications outside
+// them are not sent to the bank. Amount and income limits are in Currency,
+// the bank's currency, and applications in any other currency are
+// ineligible. Zero amounts, ratios and terms, a negative MaxDependents and
+// an empty MaritalStatuses disable the respective check.
+type EligibilityConfig struct {
+	Currency        money.Currency `json:"currency"`
+	MinAmount       float64        `json:"min_amount"`
+	MaxAmount       float64        `json:"max_amount"`
+	MinIncome       float64        `json:"min_income"`
+	MaxExpenseRatio float64        `json:"max_expense_ratio"`
+	MaxDependents   int            `json:"max_dependents"`
+	MaritalStatuses []string       `json:"marital_statuses"`
+	MinTermMonths   int            `json:"min_term_months"`
+	MaxTermMonths   int            `json:"max_term_months"`
 }
 
 type LoggingConfig struct {
@@ -54,7 +144,96 @@ type LoggingConfig struct {
 }
 
 type SubmissionProcessorConfig struct {
-	IntervalSeconds int `json:"interval_seconds" env:"SUBMISSION_PROCESSOR_INTERVAL_SECONDS"`
+	IntervalSeconds        int `json:"interval_seconds" env:"SUBMISSION_PROCESSOR_INTERVAL_SECONDS"`
+	BatchSize              int `json:"batch_size" env:"SUBMISSION_PROCESSOR_BATCH_SIZE"`
+	LeaseSeconds           int `json:"lease_seconds" env:"SUBMISSION_PROCESSOR_LEASE_SECONDS"`
+	ApplicationExpiryHours int `json:"application_expiry_hours" env:"APPLICATION_EXPIRY_HOURS"`
+}
+
+type SubmissionWorkersConfig struct {
+	Workers             int `json:"workers" env:"SUBMISSION_WORKERS"`
+	PollIntervalSeconds int `json:"poll_interval_seconds" env:"SUBMISSION_WORKERS_POLL_INTERVAL_SECONDS"`
+	LeaseSeconds        int `json:"lease_seconds" env:"SUBMISSION_JOB_LEASE_SECONDS"`
+	MaxAttempts         int `json:"max_attempts" env:"SUBMISSION_JOB_MAX_ATTEMPTS"`
+}
+
+type IdempotencyConfig struct {
+	KeyTTLHours int `json:"key_ttl_hours" env:"IDEMPOTENCY_KEY_TTL_HOURS"`
+}
+
+// WebhooksConfig controls outbound webhook delivery. A failed delivery is
+// retried after BaseDelaySeconds, doubling per attempt up to
+// MaxDelaySeconds, and given up after MaxAttempts. AllowInsecureURLs lets
+// webhooks use http and private addresses, for development only.
+type WebhooksConfig struct {
+	PollIntervalSeconds int  `json:"poll_interval_seconds" env:"WEBHOOK_POLL_INTERVAL_SECONDS"`
+	BatchSize           int  `json:"batch_size" env:"WEBHOOK_BATCH_SIZE"`
+	LeaseSeconds        int  `json:"lease_seconds" env:"WEBHOOK_LEASE_SECONDS"`
+	TimeoutSeconds      int  `json:"timeout_seconds" env:"WEBHOOK_TIMEOUT_SECONDS"`
+	MaxAttempts         int  `json:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS"`
+	BaseDelaySeconds    int  `json:"base_delay_seconds" env:"WEBHOOK_BASE_DELAY_SECONDS"`
+	MaxDelaySeconds     int  `json:"max_delay_seconds" env:"WEBHOOK_MAX_DELAY_SECONDS"`
+	AllowInsecureURLs   bool `json:"allow_insecure_urls" env:"WEBHOOK_ALLOW_INSECURE_URLS"`
+}
+
+// StreamConfig controls live application status updates. A comment is sent
+// every HeartbeatSeconds to keep idle streams open, and streams are closed
+// after MaxDurationSeconds; clients reconnect if they still care. Long-poll
+// status requests wait at most LongPollMaxTimeoutSeconds.
+type StreamConfig struct {
+	HeartbeatSeconds          int `json:"heartbeat_seconds" env:"STREAM_HEARTBEAT_SECONDS"`
+	MaxDurationSeconds        int `json:"max_duration_seconds" env:"STREAM_MAX_DURATION_SECONDS"`
+	LongPollMaxTimeoutSeconds int `json:"long_poll_max_timeout_seconds" env:"LONG_POLL_MAX_TIMEOUT_SECONDS"`
+}
+
+// OfferVerificationConfig sets how far an offer's stated APR (percentage
+// points) and total repayment (currency units) may be from the recomputed
+// figures before the offer is flagged.
+type OfferVerificationConfig struct {
+	APRTolerance    float64 `json:"apr_tolerance" env:"OFFER_APR_TOLERANCE"`
+	AmountTolerance float64 `json:"amount_tolerance" env:"OFFER_AMOUNT_TOLERANCE"`
+}
+
+// AffordabilityConfig is the responsible-lending policy offers are assessed
+// against. A household needs SubsistenceMinimum a month to live on, plus
+// DependentAllowance per dependent. An offer is unaffordable when its payment
+// and the existing monthly expenses take more than MaxDebtServiceRatio of the
+// income, or leave less than the subsistence minimum.
+type AffordabilityConfig struct {
+	SubsistenceMinimum  float64 `json:"subsistence_minimum" env:"AFFORDABILITY_SUBSISTENCE_MINIMUM"`
+	DependentAllowance  float64 `json:"dependent_allowance" env:"AFFORDABILITY_DEPENDENT_ALLOWANCE"`
+	MaxDebtServiceRatio float64 `json:"max_debt_service_ratio" env:"AFFORDABILITY_MAX_DEBT_SERVICE_RATIO"`
+}
+
+const (
+	VelocityActionReject = "reject"
+	VelocityActionLink   = "link"
+	VelocityActionOff    = "off"
+)
+
+// VelocityConfig limits how often the same customer can apply. At most
+// MaxPerPhone, MaxPerEmail and MaxPerIP applications are accepted within
+// WindowMinutes; zero disables a limit. LimitAction applies to applications
+// over a limit and DuplicateAction to applications identical to one still in
+// progress: "reject" refuses them, "link" answers with the existing
+// application and "off" lets them through. Limits cannot be linked, as the
+// applications counted against them may belong to other customers.
+type VelocityConfig struct {
+	WindowMinutes   int    `json:"window_minutes" env:"VELOCITY_WINDOW_MINUTES"`
+	MaxPerPhone     int    `json:"max_per_phone" env:"VELOCITY_MAX_PER_PHONE"`
+	MaxPerEmail     int    `json:"max_per_email" env:"VELOCITY_MAX_PER_EMAIL"`
+	MaxPerIP        int    `json:"max_per_ip" env:"VELOCITY_MAX_PER_IP"`
+	LimitAction     string `json:"limit_action" env:"VELOCITY_LIMIT_ACTION"`
+	DuplicateAction string `json:"duplicate_action" env:"VELOCITY_DUPLICATE_ACTION"`
+}
+
+// PhoneConfig restricts customer phone numbers to AllowedCountryCodes.
+// National numbers without a country code get DefaultCountryCode in place of
+// a leading trunk prefix "0". Codes are digits only; a leading "+" is
+// dropped.
+type PhoneConfig struct {
+	AllowedCountryCodes []string `json:"allowed_country_codes" env:"PHONE_ALLOWED_COUNTRY_CODES"`
+	DefaultCountryCode  string   `json:"default_country_code" env:"PHONE_DEFAULT_COUNTRY_CODE"`
 }
 
 func Load() (*Config, error) {
@@ -64,8 +243,9 @@ func Load() (*Config, error) {
 
 	config := &Config{
 		Server: ServerConfig{
-			Port: getEnvOrDefault("SERVER_PORT", "8080"),
-			Host: getEnvOrDefault("SERVER_HOST", "localhost"),
+			Port:       getEnvOrDefault("SERVER_PORT", "8080"),
+			Host:       getEnvOrDefault("SERVER_HOST", "localhost"),
+			AdminToken: getEnvOrDefault("ADMIN_API_TOKEN", ""),
 		},
 		Database: DatabaseConfig{
 			Host:         getEnvOrDefault("DB_HOST", "localhost"),
@@ -78,28 +258,221 @@ func Load() (*Config, error) {
 			MaxOpenConns: getEnvIntOrDefault("DB_MAX_OPEN_CONNS", 100),
 			MaxLifetime:  getEnvIntOrDefault("DB_MAX_LIFETIME", 3600),
 		},
-		Banks: BanksConfig{
-			FastBank: FastBankConfig{
-				BaseURL: getEnvOrDefault("FASTBANK_BASE_URL", ""),
-				Timeout: getEnvIntOrDefault("FASTBANK_TIMEOUT", 30),
-			},
-			SolidBank: SolidBankConfig{
-				BaseURL: getEnvOrDefault("SOLIDBANK_BASE_URL", ""),
-				Timeout: getEnvIntOrDefault("SOLIDBANK_TIMEOUT", 30),
-			},
-		},
 		Logging: LoggingConfig{
 			Level:  getEnvOrDefault("LOG_LEVEL", "info"),
 			Format: getEnvOrDefault("LOG_FORMAT", "json"),
 		},
 		SubmissionProcessor: SubmissionProcessorConfig{
-			IntervalSeconds: getEnvIntOrDefault("SUBMISSION_PROCESSOR_INTERVAL_SECONDS", 300),
+			IntervalSeconds:        getEnvIntOrDefault("SUBMISSION_PROCESSOR_INTERVAL_SECONDS", 5),
+			BatchSize:              getEnvIntOrDefault("SUBMISSION_PROCESSOR_BATCH_SIZE", 100),
+			LeaseSeconds:           getEnvIntOrDefault("SUBMISSION_PROCESSOR_LEASE_SECONDS", 120),
+			ApplicationExpiryHours: getEnvIntOrDefault("APPLICATION_EXPIRY_HOURS", 24),
+		},
+		SubmissionWorkers: SubmissionWorkersConfig{
+			Workers:             getEnvIntOrDefault("SUBMISSION_WORKERS", 4),
+			PollIntervalSeconds: getEnvIntOrDefault("SUBMISSION_WORKERS_POLL_INTERVAL_SECONDS", 5),
+			LeaseSeconds:        getEnvIntOrDefault("SUBMISSION_JOB_LEASE_SECONDS", 300),
+			MaxAttempts:         getEnvIntOrDefault("SUBMISSION_JOB_MAX_ATTEMPTS", 5),
+		},
+		Idempotency: IdempotencyConfig{
+			KeyTTLHours: getEnvIntOrDefault("IDEMPOTENCY_KEY_TTL_HOURS", 24),
+		},
+		Webhooks: WebhooksConfig{
+			PollIntervalSeconds: getEnvIntOrDefault("WEBHOOK_POLL_INTERVAL_SECONDS", 2),
+			BatchSize:           getEnvIntOrDefault("WEBHOOK_BATCH_SIZE", 20),
+			LeaseSeconds:        getEnvIntOrDefault("WEBHOOK_LEASE_SECONDS", 60),
+			TimeoutSeconds:      getEnvIntOrDefault("WEBHOOK_TIMEOUT_SECONDS", 10),
+			MaxAttempts:         getEnvIntOrDefault("WEBHOOK_MAX_ATTEMPTS", 10),
+			BaseDelaySeconds:    getEnvIntOrDefault("WEBHOOK_BASE_DELAY_SECONDS", 10),
+			MaxDelaySeconds:     getEnvIntOrDefault("WEBHOOK_MAX_DELAY_SECONDS", 3600),
+			AllowInsecureURLs:   getEnvBoolOrDefault("WEBHOOK_ALLOW_INSECURE_URLS", false),
+		},
+		Stream: StreamConfig{
+			HeartbeatSeconds:          getEnvIntOrDefault("STREAM_HEARTBEAT_SECONDS", 15),
+			MaxDurationSeconds:        getEnvIntOrDefault("STREAM_MAX_DURATION_SECONDS", 300),
+			LongPollMaxTimeoutSeconds: getEnvIntOrDefault("LONG_POLL_MAX_TIMEOUT_SECONDS", 25),
+		},
+		OfferVerification: OfferVerificationConfig{
+			APRTolerance:    getEnvFloatOrDefault("OFFER_APR_TOLERANCE", 0.1),
+			AmountTolerance: getEnvFloatOrDefault("OFFER_AMOUNT_TOLERANCE", 1),
+		},
+		Affordability: AffordabilityConfig{
+			SubsistenceMinimum:  getEnvFloatOrDefault("AFFORDABILITY_SUBSISTENCE_MINIMUM", 500),
+			DependentAllowance:  getEnvFloatOrDefault("AFFORDABILITY_DEPENDENT_ALLOWANCE", 250),
+			MaxDebtServiceRatio: getEnvFloatOrDefault("AFFORDABILITY_MAX_DEBT_SERVICE_RATIO", 0.4),
+		},
+		Velocity: VelocityConfig{
+			WindowMinutes:   getEnvIntOrDefault("VELOCITY_WINDOW_MINUTES", 60),
+			MaxPerPhone:     getEnvIntOrDefault("VELOCITY_MAX_PER_PHONE", 5),
+			MaxPerEmail:     getEnvIntOrDefault("VELOCITY_MAX_PER_EMAIL", 5),
+			MaxPerIP:        getEnvIntOrDefault("VELOCITY_MAX_PER_IP", 20),
+			LimitAction:     strings.ToLower(getEnvOrDefault("VELOCITY_LIMIT_ACTION", VelocityActionReject)),
+			DuplicateAction: strings.ToLower(getEnvOrDefault("VELOCITY_DUPLICATE_ACTION", VelocityActionLink)),
+		},
+		Phone: PhoneConfig{
+			AllowedCountryCodes: getEnvListOrDefault("PHONE_ALLOWED_COUNTRY_CODES", []string{"371"}),
+			DefaultCountryCode:  strings.TrimPrefix(getEnvOrDefault("PHONE_DEFAULT_COUNTRY_CODE", "371"), "+"),
 		},
 	}
 
+	for i, code := range config.Phone.AllowedCountryCodes {
+		config.Phone.AllowedCountryCodes[i] = strings.TrimPrefix(code, "+")
+	}
+
+	clientTokens, err := parseClientTokens(getEnvListOrDefault("API_CLIENT_TOKENS", nil))
+	if err != nil {
+		return nil, err
+	}
+	config.Server.ClientTokens = clientTokens
+
+	trustedProxies, err := parseTrustedProxies(getEnvListOrDefault("TRUSTED_PROXIES", nil))
+	if err != nil {
+		return nil, err
+	}
+	config.Server.TrustedProxies = trustedProxies
+
+	if action := config.Velocity.LimitAction; action != VelocityActionReject && action != VelocityActionOff {
+		return nil, fmt.Errorf("invalid VELOCITY_LIMIT_ACTION %q: must be reject or off", action)
+	}
+	if action := config.Velocity.DuplicateAction; !isVelocityAction(action) {
+		return nil, fmt.Errorf("invalid VELOCITY_DUPLICATE_ACTION %q: must be reject, link or off", action)
+	}
+
+	banks, err := loadBanks(getEnvOrDefault("BANKS", "FastBank,SolidBank"))
+	if err != nil {
+		return nil, err
+	}
+	config.Banks = banks
+
 	return config, nil
 }
 
+// parseClientTokens reads API clients given as client-id:token pairs.
+func parseClientTokens(entries []string) (map[string]string, error) {
+	tokens := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		clientID, token, found := strings.Cut(entry, ":")
+		clientID, token = strings.TrimSpace(clientID), strings.TrimSpace(token)
+		if !found || clientID == "" || token == "" {
+			return nil, fmt.Errorf("invalid API_CLIENT_TOKENS entry %q: must be client-id:token", clientID)
+		}
+		if len(clientID) > maxClientIDLength {
+			return nil, fmt.Errorf("invalid API_CLIENT_TOKENS client ID %q: must be at most %d characters", clientID, maxClientIDLength)
+		}
+		if _, duplicate := tokens[token]; duplicate {
+			return nil, fmt.Errorf("invalid API_CLIENT_TOKENS: client %s reuses another client's token", clientID)
+		}
+		tokens[token] = clientID
+	}
+	return tokens, nil
+}
+
+// parseTrustedProxies reads proxy addresses given as CIDR ranges or single
+// IPs.
+func parseTrustedProxies(entries []string) ([]*net.IPNet, error) {
+	var proxies []*net.IPNet
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil {
+				bits := 8 * net.IPv6len
+				if ip.To4() != nil {
+					ip, bits = ip.To4(), 8*net.IPv4len
+				}
+				proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+				continue
+			}
+		}
+
+		_, proxy, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TRUSTED_PROXIES entry %q: must be an IP address or CIDR range", entry)
+		}
+		proxies = append(proxies, proxy)
+	}
+	return proxies, nil
+}
+
+func isVelocityAction(action string) bool {
+	switch action {
+	case VelocityActionReject, VelocityActionLink, VelocityActionOff:
+		return true
+	}
+	return false
+}
+
+func loadBanks(names string) ([]BankConfig, error) {
+	var banks []BankConfig
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		prefix := strings.ToUpper(name) + "_"
+		currency := money.Currency(strings.ToUpper(getEnvOrDefault(prefix+"CURRENCY", string(money.DefaultCurrency))))
+		bank := BankConfig{
+			Name:        name,
+			Adapter:     getEnvOrDefault(prefix+"ADAPTER", strings.ToLower(name)),
+			BaseURL:     getEnvOrDefault(prefix+"BASE_URL", ""),
+			Timeout:     getEnvIntOrDefault(prefix+"TIMEOUT", 30),
+			Enabled:     getEnvBoolOrDefault(prefix+"ENABLED", true),
+			MappingFile: getEnvOrDefault(prefix+"MAPPING_FILE", ""),
+			Currency:    currency,
+			Retry: RetryConfig{
+				MaxAttempts:          getEnvIntOrDefault(prefix+"RETRY_MAX_ATTEMPTS", 3),
+				BaseDelayMs:          getEnvIntOrDefault(prefix+"RETRY_BASE_DELAY_MS", 200),
+				MaxDelayMs:           getEnvIntOrDefault(prefix+"RETRY_MAX_DELAY_MS", 5000),
+				Jitter:               getEnvFloatOrDefault(prefix+"RETRY_JITTER", 0.2),
+				RetryableStatusCodes: getEnvIntListOrDefault(prefix+"RETRY_STATUS_CODES", []int{429, 502, 503, 504}),
+				RetryNetworkErrors:   getEnvBoolOrDefault(prefix+"RETRY_NETWORK_ERRORS", true),
+				IdempotentSubmit:     getEnvBoolOrDefault(prefix+"IDEMPOTENT_SUBMIT", false),
+				IdempotencyHeader:    getEnvOrDefault(prefix+"IDEMPOTENCY_HEADER", ""),
+			},
+			CircuitBreaker: CircuitBreakerConfig{
+				Enabled:          getEnvBoolOrDefault(prefix+"BREAKER_ENABLED", true),
+				WindowSize:       getEnvIntOrDefault(prefix+"BREAKER_WINDOW_SIZE", 20),
+				MinRequests:      getEnvIntOrDefault(prefix+"BREAKER_MIN_REQUESTS", 5),
+				FailureRate:      getEnvFloatOrDefault(prefix+"BREAKER_FAILURE_RATE", 0.5),
+				CooldownSeconds:  getEnvIntOrDefault(prefix+"BREAKER_COOLDOWN_SECONDS", 30),
+				HalfOpenRequests: getEnvIntOrDefault(prefix+"BREAKER_HALF_OPEN_REQUESTS", 1),
+			},
+			Poll: PollConfig{
+				InitialDelaySeconds:     getEnvIntOrDefault(prefix+"POLL_INITIAL_DELAY_SECONDS", DefaultPollConfig.InitialDelaySeconds),
+				BackoffMultiplier:       getEnvFloatOrDefault(prefix+"POLL_BACKOFF_MULTIPLIER", DefaultPollConfig.BackoffMultiplier),
+				MaxIntervalSeconds:      getEnvIntOrDefault(prefix+"POLL_MAX_INTERVAL_SECONDS", DefaultPollConfig.MaxIntervalSeconds),
+				DecisionDeadlineSeconds: getEnvIntOrDefault(prefix+"DECISION_DEADLINE_SECONDS", DefaultPollConfig.DecisionDeadlineSeconds),
+			},
+			Eligibility: EligibilityConfig{
+				Currency:        currency,
+				MinAmount:       getEnvFloatOrDefault(prefix+"ELIGIBLE_MIN_AMOUNT", 0),
+				MaxAmount:       getEnvFloatOrDefault(prefix+"ELIGIBLE_MAX_AMOUNT", 0),
+				MinIncome:       getEnvFloatOrDefault(prefix+"ELIGIBLE_MIN_INCOME", 0),
+				MaxExpenseRatio: getEnvFloatOrDefault(prefix+"ELIGIBLE_MAX_EXPENSE_RATIO", 0),
+				MaxDependents:   getEnvIntOrDefault(prefix+"ELIGIBLE_MAX_DEPENDENTS", -1),
+				MaritalStatuses: getEnvListOrDefault(prefix+"ELIGIBLE_MARITAL_STATUSES", nil),
+				MinTermMonths:   getEnvIntOrDefault(prefix+"ELIGIBLE_MIN_TERM_MONTHS", 0),
+				MaxTermMonths:   getEnvIntOrDefault(prefix+"ELIGIBLE_MAX_TERM_MONTHS", 0),
+			},
+		}
+
+		if len(bank.Currency) != 3 {
+			return nil, fmt.Errorf("bank %s: invalid currency %q", name, bank.Currency)
+		}
+
+		if bank.MappingFile != "" {
+			mapping, err := LoadBankMapping(bank.MappingFile)
+			if err != nil {
+				return nil, fmt.Errorf("bank %s: %w", name, err)
+			}
+			bank.Mapping = mapping
+		}
+
+		banks = append(banks, bank)
+	}
+	return banks, nil
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -115,3 +488,53 @@ func getEnvIntOrDefault(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvBoolOrDefault(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvFloatOrDefault(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvListOrDefault(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func getEnvIntListOrDefault(key string, defaultValue []int) []int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []int
+	for _, item := range strings.Split(value, ",") {
+		intValue, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil {
+			return defaultValue
+		}
+		result = append(result, intValue)
+	}
+	return result
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -19,6 +20,17 @@ func TestLoad(t *testing.T) {
 		t.Errorf("Expected default host localhost, got %s", config.Server.Host)
 	}
 
+	if config.Server.AdminToken != "" {
+		t.Errorf("Expected no admin token by default, got %s", config.Server.AdminToken)
+	}
+
+	if len(config.Server.ClientTokens) != 0 {
+		t.Errorf("Expected no client tokens by default, got %d", len(config.Server.ClientTokens))
+	}
+	if len(config.Server.TrustedProxies) != 0 {
+		t.Errorf("Expected no trusted proxies by default, got %d", len(config.Server.TrustedProxies))
+	}
+
 	if config.Logging.Level != "info" {
 		t.Errorf("Expected default log level info, got %s", config.Logging.Level)
 	}
@@ -27,12 +39,108 @@ func TestLoad(t *testing.T) {
 		t.Errorf("Expected default log format json, got %s", config.Logging.Format)
 	}
 
-	if config.Banks.FastBank.Timeout != 30 {
-		t.Errorf("Expected default FastBank timeout 30, got %d", config.Banks.FastBank.Timeout)
+	if config.SubmissionWorkers.Workers != 4 {
+		t.Errorf("Expected default submission workers 4, got %d", config.SubmissionWorkers.Workers)
+	}
+
+	if config.SubmissionWorkers.LeaseSeconds != 300 {
+		t.Errorf("Expected default submission job lease 300, got %d", config.SubmissionWorkers.LeaseSeconds)
+	}
+
+	if config.Idempotency.KeyTTLHours != 24 {
+		t.Errorf("Expected default idempotency key TTL 24, got %d", config.Idempotency.KeyTTLHours)
+	}
+
+	if config.Webhooks.MaxAttempts != 10 {
+		t.Errorf("Expected default webhook max attempts 10, got %d", config.Webhooks.MaxAttempts)
+	}
+
+	if config.Webhooks.AllowInsecureURLs {
+		t.Errorf("Expected insecure webhook URLs to be rejected by default")
+	}
+
+	if len(config.Banks) != 2 {
+		t.Fatalf("Expected 2 default banks, got %d", len(config.Banks))
+	}
+
+	fastBank := findBank(t, config, "FastBank")
+	if fastBank.Timeout != 30 {
+		t.Errorf("Expected default FastBank timeout 30, got %d", fastBank.Timeout)
+	}
+
+	if fastBank.Adapter != "fastbank" {
+		t.Errorf("Expected default FastBank adapter fastbank, got %s", fastBank.Adapter)
+	}
+
+	if !fastBank.Enabled {
+		t.Errorf("Expected FastBank to be enabled by default")
+	}
+
+	solidBank := findBank(t, config, "SolidBank")
+	if solidBank.Timeout != 30 {
+		t.Errorf("Expected default SolidBank timeout 30, got %d", solidBank.Timeout)
 	}
 
-	if config.Banks.SolidBank.Timeout != 30 {
-		t.Errorf("Expected default SolidBank timeout 30, got %d", config.Banks.SolidBank.Timeout)
+	if solidBank.Adapter != "solidbank" {
+		t.Errorf("Expected default SolidBank adapter solidbank, got %s", solidBank.Adapter)
+	}
+
+	if fastBank.Retry.MaxAttempts != 3 {
+		t.Errorf("Expected default retry max attempts 3, got %d", fastBank.Retry.MaxAttempts)
+	}
+
+	if !reflect.DeepEqual(fastBank.Retry.RetryableStatusCodes, []int{429, 502, 503, 504}) {
+		t.Errorf("Expected default retryable status codes [429 502 503 504], got %v", fastBank.Retry.RetryableStatusCodes)
+	}
+
+	if fastBank.Retry.IdempotentSubmit {
+		t.Errorf("Expected submissions not to be idempotent by default")
+	}
+
+	if !fastBank.CircuitBreaker.Enabled {
+		t.Errorf("Expected circuit breaker to be enabled by default")
+	}
+
+	if fastBank.CircuitBreaker.FailureRate != 0.5 {
+		t.Errorf("Expected default breaker failure rate 0.5, got %f", fastBank.CircuitBreaker.FailureRate)
+	}
+
+	if fastBank.CircuitBreaker.CooldownSeconds != 30 {
+		t.Errorf("Expected default breaker cooldown 30, got %d", fastBank.CircuitBreaker.CooldownSeconds)
+	}
+
+	if config.SubmissionProcessor.IntervalSeconds != 5 {
+		t.Errorf("Expected default submission processor interval 5, got %d", config.SubmissionProcessor.IntervalSeconds)
+	}
+
+	expectedVerification := OfferVerificationConfig{APRTolerance: 0.1, AmountTolerance: 1}
+	if config.OfferVerification != expectedVerification {
+		t.Errorf("Expected default offer verification %+v, got %+v", expectedVerification, config.OfferVerification)
+	}
+
+	expectedAffordability := AffordabilityConfig{SubsistenceMinimum: 500, DependentAllowance: 250, MaxDebtServiceRatio: 0.4}
+	if config.Affordability != expectedAffordability {
+		t.Errorf("Expected default affordability %+v, got %+v", expectedAffordability, config.Affordability)
+	}
+
+	expectedVelocity := VelocityConfig{WindowMinutes: 60, MaxPerPhone: 5, MaxPerEmail: 5, MaxPerIP: 20, LimitAction: "reject", DuplicateAction: "link"}
+	if config.Velocity != expectedVelocity {
+		t.Errorf("Expected default velocity %+v, got %+v", expectedVelocity, config.Velocity)
+	}
+
+	expectedPhone := PhoneConfig{AllowedCountryCodes: []string{"371"}, DefaultCountryCode: "371"}
+	if !reflect.DeepEqual(config.Phone, expectedPhone) {
+		t.Errorf("Expected default phone config %+v, got %+v", expectedPhone, config.Phone)
+	}
+
+	expectedPoll := PollConfig{InitialDelaySeconds: 5, BackoffMultiplier: 2, MaxIntervalSeconds: 300, DecisionDeadlineSeconds: 3600}
+	if fastBank.Poll != expectedPoll {
+		t.Errorf("Expected default poll config %+v, got %+v", expectedPoll, fastBank.Poll)
+	}
+
+	expectedEligibility := EligibilityConfig{Currency: "EUR", MaxDependents: -1}
+	if !reflect.DeepEqual(fastBank.Eligibility, expectedEligibility) {
+		t.Errorf("Expected no eligibility limits by default, got %+v", fastBank.Eligibility)
 	}
 }
 
@@ -70,20 +178,22 @@ func TestLoadWithEnvironmentVariables(t *testing.T) {
 		t.Errorf("Expected host 0.0.0.0, got %s", config.Server.Host)
 	}
 
-	if config.Banks.FastBank.BaseURL != "https://fastbank.example.com" {
-		t.Errorf("Expected FastBank URL https://fastbank.example.com, got %s", config.Banks.FastBank.BaseURL)
+	fastBank := findBank(t, config, "FastBank")
+	if fastBank.BaseURL != "https://fastbank.example.com" {
+		t.Errorf("Expected FastBank URL https://fastbank.example.com, got %s", fastBank.BaseURL)
 	}
 
-	if config.Banks.SolidBank.BaseURL != "https://solidbank.example.com" {
-		t.Errorf("Expected SolidBank URL https://solidbank.example.com, got %s", config.Banks.SolidBank.BaseURL)
+	solidBank := findBank(t, config, "SolidBank")
+	if solidBank.BaseURL != "https://solidbank.example.com" {
+		t.Errorf("Expected SolidBank URL https://solidbank.example.com, got %s", solidBank.BaseURL)
 	}
 
-	if config.Banks.FastBank.Timeout != 60 {
-		t.Errorf("Expected FastBank timeout 60, got %d", config.Banks.FastBank.Timeout)
+	if fastBank.Timeout != 60 {
+		t.Errorf("Expected FastBank timeout 60, got %d", fastBank.Timeout)
 	}
 
-	if config.Banks.SolidBank.Timeout != 45 {
-		t.Errorf("Expected SolidBank timeout 45, got %d", config.Banks.SolidBank.Timeout)
+	if solidBank.Timeout != 45 {
+		t.Errorf("Expected SolidBank timeout 45, got %d", solidBank.Timeout)
 	}
 
 	if config.Logging.Level != "debug" {
@@ -95,6 +205,91 @@ func TestLoadWithEnvironmentVariables(t *testing.T) {
 	}
 }
 
+func TestLoadBanks(t *testing.T) {
+	os.Setenv("BANKS", "FastBank, TrustBank ,")
+	os.Setenv("TRUSTBANK_ADAPTER", "fastbank")
+	os.Setenv("TRUSTBANK_BASE_URL", "https://trustbank.example.com")
+	os.Setenv("TRUSTBANK_TIMEOUT", "10")
+	os.Setenv("TRUSTBANK_ENABLED", "false")
+	os.Setenv("TRUSTBANK_RETRY_MAX_ATTEMPTS", "5")
+	os.Setenv("TRUSTBANK_RETRY_JITTER", "0.5")
+	os.Setenv("TRUSTBANK_RETRY_STATUS_CODES", "500, 503")
+	os.Setenv("TRUSTBANK_IDEMPOTENCY_HEADER", "Idempotency-Key")
+	os.Setenv("TRUSTBANK_CURRENCY", "sek")
+
+	defer func() {
+		os.Unsetenv("BANKS")
+		os.Unsetenv("TRUSTBANK_ADAPTER")
+		os.Unsetenv("TRUSTBANK_BASE_URL")
+		os.Unsetenv("TRUSTBANK_TIMEOUT")
+		os.Unsetenv("TRUSTBANK_ENABLED")
+		os.Unsetenv("TRUSTBANK_RETRY_MAX_ATTEMPTS")
+		os.Unsetenv("TRUSTBANK_RETRY_JITTER")
+		os.Unsetenv("TRUSTBANK_RETRY_STATUS_CODES")
+		os.Unsetenv("TRUSTBANK_IDEMPOTENCY_HEADER")
+		os.Unsetenv("TRUSTBANK_CURRENCY")
+	}()
+
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(config.Banks) != 2 {
+		t.Fatalf("Expected 2 banks, got %d", len(config.Banks))
+	}
+
+	trustBank := findBank(t, config, "TrustBank")
+	if trustBank.Adapter != "fastbank" {
+		t.Errorf("Expected TrustBank adapter fastbank, got %s", trustBank.Adapter)
+	}
+
+	if trustBank.BaseURL != "https://trustbank.example.com" {
+		t.Errorf("Expected TrustBank URL https://trustbank.example.com, got %s", trustBank.BaseURL)
+	}
+
+	if trustBank.Timeout != 10 {
+		t.Errorf("Expected TrustBank timeout 10, got %d", trustBank.Timeout)
+	}
+
+	if trustBank.Enabled {
+		t.Errorf("Expected TrustBank to be disabled")
+	}
+
+	if trustBank.Retry.MaxAttempts != 5 {
+		t.Errorf("Expected TrustBank retry max attempts 5, got %d", trustBank.Retry.MaxAttempts)
+	}
+
+	if trustBank.Retry.Jitter != 0.5 {
+		t.Errorf("Expected TrustBank retry jitter 0.5, got %f", trustBank.Retry.Jitter)
+	}
+
+	if !reflect.DeepEqual(trustBank.Retry.RetryableStatusCodes, []int{500, 503}) {
+		t.Errorf("Expected TrustBank retryable status codes [500 503], got %v", trustBank.Retry.RetryableStatusCodes)
+	}
+
+	if trustBank.Retry.IdempotencyHeader != "Idempotency-Key" {
+		t.Errorf("Expected TrustBank idempotency header Idempotency-Key, got %s", trustBank.Retry.IdempotencyHeader)
+	}
+
+	if trustBank.Currency != "SEK" {
+		t.Errorf("Expected TrustBank currency SEK, got %s", trustBank.Currency)
+	}
+
+	if trustBank.Eligibility.Currency != "SEK" {
+		t.Errorf("Expected TrustBank eligibility currency SEK, got %s", trustBank.Eligibility.Currency)
+	}
+
+	if fastBank := findBank(t, config, "FastBank"); fastBank.Currency != "EUR" {
+		t.Errorf("Expected FastBank currency EUR, got %s", fastBank.Currency)
+	}
+
+	os.Setenv("TRUSTBANK_CURRENCY", "euro")
+	if _, err := Load(); err == nil {
+		t.Errorf("Expected an error for an invalid currency")
+	}
+}
+
 func TestGetEnvOrDefault(t *testing.T) {
 	os.Setenv("TEST_VAR", "test_value")
 	defer os.Unsetenv("TEST_VAR")
@@ -156,3 +351,210 @@ func TestGetEnvIntOrDefault(t *testing.T) {
 		t.Errorf("Expected 0, got %d", result)
 	}
 }
+
+func TestGetEnvBoolOrDefault(t *testing.T) {
+	os.Setenv("TEST_BOOL_VAR", "false")
+	defer os.Unsetenv("TEST_BOOL_VAR")
+
+	result := getEnvBoolOrDefault("TEST_BOOL_VAR", true)
+	if result {
+		t.Errorf("Expected false, got %t", result)
+	}
+
+	result = getEnvBoolOrDefault("NON_EXISTING_BOOL_VAR", true)
+	if !result {
+		t.Errorf("Expected true, got %t", result)
+	}
+
+	os.Setenv("INVALID_BOOL_VAR", "maybe")
+	defer os.Unsetenv("INVALID_BOOL_VAR")
+
+	result = getEnvBoolOrDefault("INVALID_BOOL_VAR", true)
+	if !result {
+		t.Errorf("Expected true for invalid bool, got %t", result)
+	}
+}
+
+func findBank(t *testing.T, config *Config, name string) BankConfig {
+	t.Helper()
+
+	for _, bank := range config.Banks {
+		if bank.Name == name {
+			return bank
+		}
+	}
+
+	t.Fatalf("Expected bank %s to be configured", name)
+	return BankConfig{}
+}
+
+func TestGetEnvFloatOrDefault(t *testing.T) {
+	os.Setenv("TEST_FLOAT_VAR", "0.25")
+	defer os.Unsetenv("TEST_FLOAT_VAR")
+
+	result := getEnvFloatOrDefault("TEST_FLOAT_VAR", 1.5)
+	if result != 0.25 {
+		t.Errorf("Expected 0.25, got %f", result)
+	}
+
+	result = getEnvFloatOrDefault("NON_EXISTING_FLOAT_VAR", 1.5)
+	if result != 1.5 {
+		t.Errorf("Expected 1.5, got %f", result)
+	}
+
+	os.Setenv("INVALID_FLOAT_VAR", "not_a_number")
+	defer os.Unsetenv("INVALID_FLOAT_VAR")
+
+	result = getEnvFloatOrDefault("INVALID_FLOAT_VAR", 1.5)
+	if result != 1.5 {
+		t.Errorf("Expected 1.5 for invalid float, got %f", result)
+	}
+}
+
+func TestGetEnvIntListOrDefault(t *testing.T) {
+	os.Setenv("TEST_INT_LIST_VAR", "1, 2,3")
+	defer os.Unsetenv("TEST_INT_LIST_VAR")
+
+	result := getEnvIntListOrDefault("TEST_INT_LIST_VAR", []int{9})
+	if !reflect.DeepEqual(result, []int{1, 2, 3}) {
+		t.Errorf("Expected [1 2 3], got %v", result)
+	}
+
+	result = getEnvIntListOrDefault("NON_EXISTING_INT_LIST_VAR", []int{9})
+	if !reflect.DeepEqual(result, []int{9}) {
+		t.Errorf("Expected [9], got %v", result)
+	}
+
+	os.Setenv("INVALID_INT_LIST_VAR", "1,two")
+	defer os.Unsetenv("INVALID_INT_LIST_VAR")
+
+	result = getEnvIntListOrDefault("INVALID_INT_LIST_VAR", []int{9})
+	if !reflect.DeepEqual(result, []int{9}) {
+		t.Errorf("Expected [9] for invalid list, got %v", result)
+	}
+}
+
+func TestLoadBankEligibility(t *testing.T) {
+	os.Setenv("FASTBANK_ELIGIBLE_MIN_AMOUNT", "1000")
+	os.Setenv("FASTBANK_ELIGIBLE_MAX_AMOUNT", "15000")
+	os.Setenv("FASTBANK_ELIGIBLE_MAX_DEPENDENTS", "3")
+	os.Setenv("FASTBANK_ELIGIBLE_MARITAL_STATUSES", "MARRIED, SINGLE")
+
+	defer func() {
+		os.Unsetenv("FASTBANK_ELIGIBLE_MIN_AMOUNT")
+		os.Unsetenv("FASTBANK_ELIGIBLE_MAX_AMOUNT")
+		os.Unsetenv("FASTBANK_ELIGIBLE_MAX_DEPENDENTS")
+		os.Unsetenv("FASTBANK_ELIGIBLE_MARITAL_STATUSES")
+	}()
+
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := EligibilityConfig{Currency: "EUR", MinAmount: 1000, MaxAmount: 15000, MaxDependents: 3, MaritalStatuses: []string{"MARRIED", "SINGLE"}}
+	if eligibility := findBank(t, config, "FastBank").Eligibility; !reflect.DeepEqual(eligibility, expected) {
+		t.Errorf("Expected eligibility %+v, got %+v", expected, eligibility)
+	}
+}
+
+func TestLoadVelocityActions(t *testing.T) {
+	os.Setenv("VELOCITY_LIMIT_ACTION", "Off")
+	os.Setenv("VELOCITY_DUPLICATE_ACTION", "off")
+
+	defer func() {
+		os.Unsetenv("VELOCITY_LIMIT_ACTION")
+		os.Unsetenv("VELOCITY_DUPLICATE_ACTION")
+	}()
+
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if config.Velocity.LimitAction != VelocityActionOff {
+		t.Errorf("Expected limit action %q, got %q", VelocityActionOff, config.Velocity.LimitAction)
+	}
+	if config.Velocity.DuplicateAction != VelocityActionOff {
+		t.Errorf("Expected duplicate action %q, got %q", VelocityActionOff, config.Velocity.DuplicateAction)
+	}
+
+	os.Setenv("VELOCITY_DUPLICATE_ACTION", "ignore")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for an unknown duplicate action")
+	}
+
+	os.Setenv("VELOCITY_DUPLICATE_ACTION", "link")
+	os.Setenv("VELOCITY_LIMIT_ACTION", "link")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for a linked limit action")
+	}
+}
+
+func TestLoadPhone(t *testing.T) {
+	os.Setenv("PHONE_ALLOWED_COUNTRY_CODES", "+371, 370,372")
+	os.Setenv("PHONE_DEFAULT_COUNTRY_CODE", "+370")
+
+	defer func() {
+		os.Unsetenv("PHONE_ALLOWED_COUNTRY_CODES")
+		os.Unsetenv("PHONE_DEFAULT_COUNTRY_CODE")
+	}()
+
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := PhoneConfig{AllowedCountryCodes: []string{"371", "370", "372"}, DefaultCountryCode: "370"}
+	if !reflect.DeepEqual(config.Phone, expected) {
+		t.Errorf("Expected phone config %+v, got %+v", expected, config.Phone)
+	}
+}
+
+func TestLoadClientTokens(t *testing.T) {
+	os.Setenv("API_CLIENT_TOKENS", "portal:portal-token, partner : partner-token")
+	defer os.Unsetenv("API_CLIENT_TOKENS")
+
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string]string{"portal-token": "portal", "partner-token": "partner"}
+	if !reflect.DeepEqual(config.Server.ClientTokens, expected) {
+		t.Errorf("Expected client tokens %v, got %v", expected, config.Server.ClientTokens)
+	}
+
+	for _, value := range []string{"portal", "portal:", ":token", "portal:token,partner:token"} {
+		os.Setenv("API_CLIENT_TOKENS", value)
+		if _, err := Load(); err == nil {
+			t.Errorf("Expected error for API_CLIENT_TOKENS %q", value)
+		}
+	}
+}
+
+func TestLoadTrustedProxies(t *testing.T) {
+	os.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.0.2.10,2001:db8::1")
+	defer os.Unsetenv("TRUSTED_PROXIES")
+
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var proxies []string
+	for _, proxy := range config.Server.TrustedProxies {
+		proxies = append(proxies, proxy.String())
+	}
+	expected := []string{"10.0.0.0/8", "192.0.2.10/32", "2001:db8::1/128"}
+	if !reflect.DeepEqual(proxies, expected) {
+		t.Errorf("Expected trusted proxies %v, got %v", expected, proxies)
+	}
+
+	for _, value := range []string{"proxy.internal", "10.0.0.0/33"} {
+		os.Setenv("TRUSTED_PROXIES", value)
+		if _, err := Load(); err == nil {
+			t.Errorf("Expected error for TRUSTED_PROXIES %q", value)
+		}
+	}
+}
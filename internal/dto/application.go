@@ -4,22 +4,51 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/money"
 )
 
 type ApplicationRequest struct {
-	Phone           string  `json:"phone" validate:"required"`
-	Email           string  `json:"email" validate:"required,email"`
-	MonthlyIncome   float64 `json:"monthlyIncome" validate:"required,min=0"`
-	MonthlyExpenses float64 `json:"monthlyExpenses" validate:"required,min=0"`
-	MaritalStatus   string  `json:"maritalStatus" validate:"required,oneof=SINGLE MARRIED DIVORCED WIDOWED COHABITING"`
-	AgreeToBeScored bool    `json:"agreeToBeScored" validate:"required"`
-	Amount          float64 `json:"amount" validate:"required,min=0"`
-	Dependents      int     `json:"dependents" validate:"min=0"`
+	Phone           string           `json:"phone" validate:"required,phone"`
+	Email           string           `json:"email" validate:"required,email"`
+	MonthlyIncome   money.Money      `json:"monthlyIncome" validate:"required,min=0"`
+	MonthlyExpenses money.Money      `json:"monthlyExpenses" validate:"required,min=0"`
+	MaritalStatus   string           `json:"maritalStatus" validate:"required,oneof=SINGLE MARRIED DIVORCED WIDOWED COHABITING"`
+	AgreeToBeScored bool             `json:"agreeToBeScored" validate:"required"`
+	Amount          money.Money      `json:"amount" validate:"required,min=0"`
+	Dependents      int              `json:"dependents" validate:"min=0"`
+	Callback        *WebhookCallback `json:"callback,omitempty"`
+
+	TermMonths          *int           `json:"termMonths,omitempty" validate:"omitempty,min=3,max=120"`
+	Purpose             LoanPurpose    `json:"purpose,omitempty" validate:"omitempty,oneof=CAR HOME_RENOVATION DEBT_CONSOLIDATION EDUCATION TRAVEL OTHER"`
+	Currency            money.Currency `json:"currency,omitempty" validate:"omitempty,oneof=EUR"`
+	PreferredPaymentDay *int           `json:"preferredPaymentDay,omitempty" validate:"omitempty,min=1,max=28"`
 }
 
+type LoanPurpose string
+
+const (
+	LoanPurposeCar               LoanPurpose = "CAR"
+	LoanPurposeHomeRenovation    LoanPurpose = "HOME_RENOVATION"
+	LoanPurposeDebtConsolidation LoanPurpose = "DEBT_CONSOLIDATION"
+	LoanPurposeEducation         LoanPurpose = "EDUCATION"
+	LoanPurposeTravel            LoanPurpose = "TRAVEL"
+	LoanPurposeOther             LoanPurpose = "OTHER"
+)
+
+// Optional loan parameters, named as in the application request. Banks that
+// cannot take one of them get the application without it.
+const (
+	LoanFieldTermMonths          = "termMonths"
+	LoanFieldPurpose             = "purpose"
+	LoanFieldCurrency            = "currency"
+	LoanFieldPreferredPaymentDay = "preferredPaymentDay"
+)
+
 type CustomerApplication struct {
 	ID              uuid.UUID          `json:"id"`
 	CustomerData    ApplicationRequest `json:"customerData"`
+	ClientID        string             `json:"clientId,omitempty"`
+	ClientIP        string             `json:"clientIp,omitempty"`
 	Status          ApplicationStatus  `json:"status"`
 	Offers          []Offer            `json:"offers"`
 	BankSubmissions []BankSubmission   `json:"bankSubmissions"`
@@ -33,20 +62,46 @@ const (
 	StatusPending    ApplicationStatus = "PENDING"
 	StatusProcessing ApplicationStatus = "PROCESSING"
 	StatusCompleted  ApplicationStatus = "COMPLETED"
+	StatusFailed     ApplicationStatus = "FAILED"
+	StatusCancelled  ApplicationStatus = "CANCELLED"
+	StatusExpired    ApplicationStatus = "EXPIRED"
+	StatusAccepted   ApplicationStatus = "ACCEPTED"
 )
 
 type ApplicationResponse struct {
 	ID     uuid.UUID         `json:"id"`
 	Status ApplicationStatus `json:"status"`
+	// LinkedReason is set when the request was answered with an existing
+	// application instead of creating a new one.
+	LinkedReason ApplicationLinkReason `json:"linkedReason,omitempty"`
 }
 
+type ApplicationLinkReason string
+
+const (
+	LinkReasonDuplicate ApplicationLinkReason = "DUPLICATE_APPLICATION"
+)
+
 type ApplicationStatusResponse struct {
-	ID              uuid.UUID         `json:"id"`
-	Status          ApplicationStatus `json:"status"`
-	Offers          []Offer           `json:"offers"`
-	BankSubmissions []BankSubmission  `json:"bankSubmissions"`
-	CreatedAt       time.Time         `json:"createdAt"`
-	UpdatedAt       time.Time         `json:"updatedAt"`
+	ID                  uuid.UUID         `json:"id"`
+	Status              ApplicationStatus `json:"status"`
+	Currency            money.Currency    `json:"currency"`
+	TermMonths          *int              `json:"termMonths,omitempty"`
+	Purpose             LoanPurpose       `json:"purpose,omitempty"`
+	PreferredPaymentDay *int              `json:"preferredPaymentDay,omitempty"`
+	Affordability       *Affordability    `json:"affordability,omitempty"`
+	Offers              []Offer           `json:"offers"`
+	Comparison          *OfferComparison  `json:"comparison,omitempty"`
+	BankSubmissions     []BankSubmission  `json:"bankSubmissions"`
+	CreatedAt           time.Time         `json:"createdAt"`
+	UpdatedAt           time.Time         `json:"updatedAt"`
+}
+
+// Affordability is the applicant's household budget as assessed when the
+// application was submitted.
+type Affordability struct {
+	DisposableIncome   money.Money `json:"disposableIncome"`
+	SubsistenceMinimum money.Money `json:"subsistenceMinimum"`
 }
 
 type ErrorResponse struct {
@@ -54,10 +109,3 @@ type ErrorResponse struct {
 	Message string `json:"message,omitempty"`
 	Code    string `json:"code,omitempty"`
 }
-
-type BankResult struct {
-	BankName     string
-	SubmissionID string
-	Offer        *Offer
-	Err          error
-}
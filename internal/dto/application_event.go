@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ApplicationEvent struct {
+	ID           uuid.UUID       `json:"id"`
+	EntityType   EventEntityType `json:"entityType"`
+	SubmissionID *uuid.UUID      `json:"submissionId,omitempty"`
+	BankName     string          `json:"bankName,omitempty"`
+	OldStatus    string          `json:"oldStatus,omitempty"`
+	NewStatus    string          `json:"newStatus"`
+	Actor        EventActor      `json:"actor"`
+	Reason       string          `json:"reason,omitempty"`
+	CreatedAt    time.Time       `json:"createdAt"`
+}
+
+type ApplicationEventsResponse struct {
+	ApplicationID uuid.UUID          `json:"applicationId"`
+	Events        []ApplicationEvent `json:"events"`
+}
+
+type EventEntityType string
+
+const (
+	EventEntityApplication    EventEntityType = "APPLICATION"
+	EventEntityBankSubmission EventEntityType = "BANK_SUBMISSION"
+)
+
+type EventActor string
+
+const (
+	EventActorCustomer            EventActor = "customer"
+	EventActorSubmissionWorker    EventActor = "submission_worker"
+	EventActorSubmissionProcessor EventActor = "submission_processor"
+)
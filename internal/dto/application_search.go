@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/money"
+)
+
+type ApplicationSearchRequest struct {
+	Status           string `query:"status" validate:"omitempty,oneof=PENDING PROCESSING COMPLETED FAILED CANCELLED EXPIRED ACCEPTED"`
+	Email            string `query:"email" validate:"omitempty,email"`
+	Phone            string `query:"phone" validate:"omitempty,phone"`
+	CreatedFrom      string `query:"createdFrom" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	CreatedTo        string `query:"createdTo" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	BankName         string `query:"bankName"`
+	SubmissionStatus string `query:"submissionStatus" validate:"omitempty,oneof=DRAFT SUCCESS FAILED RETRY TIMED_OUT CANCELLED SKIPPED"`
+	Cursor           string `query:"cursor"`
+	Limit            int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type ApplicationSummary struct {
+	ID              uuid.UUID         `json:"id"`
+	Status          ApplicationStatus `json:"status"`
+	Email           string            `json:"email"`
+	Phone           string            `json:"phone"`
+	Amount          money.Money       `json:"amount"`
+	BankSubmissions []BankSubmission  `json:"bankSubmissions"`
+	CreatedAt       time.Time         `json:"createdAt"`
+	UpdatedAt       time.Time         `json:"updatedAt"`
+}
+
+type ApplicationListResponse struct {
+	Items      []ApplicationSummary `json:"items"`
+	NextCursor string               `json:"nextCursor,omitempty"`
+}
+
+// ApplicationCursor marks the last application of a page. Applications are
+// listed newest first, ordered by creation time and then ID.
+type ApplicationCursor struct {
+	CreatedAt time.Time `json:"createdAt"`
+	ID        uuid.UUID `json:"id"`
+}
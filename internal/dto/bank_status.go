@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+type BankStatus struct {
+	Name           string                `json:"name"`
+	CircuitBreaker *CircuitBreakerStatus `json:"circuitBreaker,omitempty"`
+}
+
+type CircuitBreakerStatus struct {
+	State       CircuitState `json:"state"`
+	Requests    int          `json:"requests"`
+	Failures    int          `json:"failures"`
+	FailureRate float64      `json:"failureRate"`
+	OpenedAt    *time.Time   `json:"openedAt,omitempty"`
+	RetryAt     *time.Time   `json:"retryAt,omitempty"`
+}
+
+type CircuitState string
+
+const (
+	CircuitStateClosed   CircuitState = "CLOSED"
+	CircuitStateOpen     CircuitState = "OPEN"
+	CircuitStateHalfOpen CircuitState = "HALF_OPEN"
+)
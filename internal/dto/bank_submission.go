@@ -7,23 +7,31 @@ import (
 )
 
 type BankSubmission struct {
-	ID           uuid.UUID            `json:"id"`
-	BankName     string               `json:"bankName"`
-	Status       BankSubmissionStatus `json:"status"`
-	BankID       string               `json:"bankId,omitempty"`
-	SubmittedAt  time.Time            `json:"submittedAt"`
-	CompletedAt  *time.Time           `json:"completedAt,omitempty"`
-	Error        string               `json:"error,omitempty"`
-	ErrorMessage *string              `json:"errorMessage,omitempty"`
-	CreatedAt    time.Time            `json:"createdAt"`
+	ID              uuid.UUID            `json:"id"`
+	BankName        string               `json:"bankName"`
+	Status          BankSubmissionStatus `json:"status"`
+	BankID          string               `json:"bankId,omitempty"`
+	SubmittedAt     time.Time            `json:"submittedAt"`
+	CompletedAt     *time.Time           `json:"completedAt,omitempty"`
+	Error           string               `json:"error,omitempty"`
+	ErrorMessage    *string              `json:"errorMessage,omitempty"`
+	NextPollAt      *time.Time           `json:"nextPollAt,omitempty"`
+	PollAttempts    int                  `json:"pollAttempts"`
+	WithdrawnAt     *time.Time           `json:"withdrawnAt,omitempty"`
+	WithdrawalError *string              `json:"withdrawalError,omitempty"`
+	CreatedAt       time.Time            `json:"createdAt"`
 }
 
 type BankSubmissionStatus string
 
 const (
-	SubmissionStatusDraft   BankSubmissionStatus = "DRAFT"
-	SubmissionStatusSuccess BankSubmissionStatus = "SUCCESS"
-	SubmissionStatusFailed  BankSubmissionStatus = "FAILED"
+	SubmissionStatusDraft     BankSubmissionStatus = "DRAFT"
+	SubmissionStatusSuccess   BankSubmissionStatus = "SUCCESS"
+	SubmissionStatusFailed    BankSubmissionStatus = "FAILED"
+	SubmissionStatusRetry     BankSubmissionStatus = "RETRY"
+	SubmissionStatusTimedOut  BankSubmissionStatus = "TIMED_OUT"
+	SubmissionStatusCancelled BankSubmissionStatus = "CANCELLED"
+	SubmissionStatusSkipped   BankSubmissionStatus = "SKIPPED"
 )
 
 type BankSubmissionResponse struct {
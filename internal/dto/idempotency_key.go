@@ -0,0 +1,8 @@
+package dto
+
+type IdempotencyKeyStatus string
+
+const (
+	IdempotencyKeyInProgress IdempotencyKeyStatus = "IN_PROGRESS"
+	IdempotencyKeyCompleted  IdempotencyKeyStatus = "COMPLETED"
+)
@@ -4,18 +4,26 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/money"
 )
 
 type Offer struct {
-	ID                   uuid.UUID   `json:"id"`
-	BankName             string      `json:"bankName"`
-	MonthlyPaymentAmount *float64    `json:"monthlyPaymentAmount,omitempty"`
-	TotalRepaymentAmount *float64    `json:"totalRepaymentAmount,omitempty"`
-	NumberOfPayments     *int        `json:"numberOfPayments,omitempty"`
-	AnnualPercentageRate *float64    `json:"annualPercentageRate,omitempty"`
-	FirstRepaymentDate   *string     `json:"firstRepaymentDate,omitempty"`
-	Status               OfferStatus `json:"status"`
-	CreatedAt            time.Time   `json:"createdAt"`
+	ID                           uuid.UUID              `json:"id"`
+	BankName                     string                 `json:"bankName"`
+	Currency                     money.Currency         `json:"currency"`
+	MonthlyPaymentAmount         *money.Money           `json:"monthlyPaymentAmount,omitempty"`
+	TotalRepaymentAmount         *money.Money           `json:"totalRepaymentAmount,omitempty"`
+	NumberOfPayments             *int                   `json:"numberOfPayments,omitempty"`
+	AnnualPercentageRate         *float64               `json:"annualPercentageRate,omitempty"`
+	FirstRepaymentDate           *string                `json:"firstRepaymentDate,omitempty"`
+	VerifiedAnnualPercentageRate *float64               `json:"verifiedAnnualPercentageRate,omitempty"`
+	Discrepancies                []OfferDiscrepancy     `json:"discrepancies,omitempty"`
+	DebtServiceRatio             *float64               `json:"debtServiceRatio,omitempty"`
+	Unaffordable                 bool                   `json:"unaffordable,omitempty"`
+	AffordabilityIssues          []AffordabilityIssue   `json:"affordabilityIssues,omitempty"`
+	Status                       OfferStatus            `json:"status"`
+	AcceptanceStatus             *OfferAcceptanceStatus `json:"acceptanceStatus,omitempty"`
+	CreatedAt                    time.Time              `json:"createdAt"`
 }
 
 type OfferStatus string
@@ -24,3 +32,34 @@ const (
 	OfferStatusApproved OfferStatus = "APPROVED"
 	OfferStatusRejected OfferStatus = "REJECTED"
 )
+
+// OfferAcceptanceStatus tracks the customer's decision on an approved offer:
+// PENDING while the acceptance is forwarded to the issuing bank, ACCEPTED or
+// DECLINED by the bank, or SUPERSEDED when the customer accepts a competing
+// offer instead.
+type OfferAcceptanceStatus string
+
+const (
+	OfferAcceptancePending    OfferAcceptanceStatus = "PENDING"
+	OfferAcceptanceAccepted   OfferAcceptanceStatus = "ACCEPTED"
+	OfferAcceptanceDeclined   OfferAcceptanceStatus = "DECLINED"
+	OfferAcceptanceSuperseded OfferAcceptanceStatus = "SUPERSEDED"
+)
+
+// OfferDiscrepancy names a stated offer figure that differs from the one
+// recomputed from the offer's payments by more than the configured tolerance.
+type OfferDiscrepancy string
+
+const (
+	OfferDiscrepancyAPR            OfferDiscrepancy = "APR_MISMATCH"
+	OfferDiscrepancyTotalRepayment OfferDiscrepancy = "TOTAL_REPAYMENT_MISMATCH"
+)
+
+// AffordabilityIssue names a responsible-lending rule an offer's monthly
+// payment breaks for the applicant's household.
+type AffordabilityIssue string
+
+const (
+	AffordabilityIssueDebtServiceRatio   AffordabilityIssue = "DEBT_SERVICE_RATIO_EXCEEDED"
+	AffordabilityIssueSubsistenceMinimum AffordabilityIssue = "BELOW_SUBSISTENCE_MINIMUM"
+)
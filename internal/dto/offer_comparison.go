@@ -0,0 +1,36 @@
+package dto
+
+import "github.com/lielamurs/aggregator/internal/money"
+
+// OfferRankBy selects the figure offers are compared by.
+type OfferRankBy string
+
+const (
+	OfferRankByAPR     OfferRankBy = "apr"
+	OfferRankByMonthly OfferRankBy = "monthly"
+	OfferRankByTotal   OfferRankBy = "total"
+)
+
+// OfferComparison ranks the offers a customer can still choose from, best
+// first. Offers the banks rejected or declined are listed separately.
+type OfferComparison struct {
+	RankBy   OfferRankBy   `json:"rankBy"`
+	Ranked   []RankedOffer `json:"ranked"`
+	Rejected []Offer       `json:"rejected"`
+}
+
+type RankedOffer struct {
+	Offer
+	Rank              int          `json:"rank"`
+	Recommended       bool         `json:"recommended"`
+	TotalCostOfCredit *money.Money `json:"totalCostOfCredit,omitempty"`
+	Deltas            OfferDeltas  `json:"deltas"`
+}
+
+// OfferDeltas are the differences to the best ranked offer. A delta is
+// omitted when either offer lacks the figure.
+type OfferDeltas struct {
+	AnnualPercentageRate *float64     `json:"annualPercentageRate,omitempty"`
+	MonthlyPaymentAmount *money.Money `json:"monthlyPaymentAmount,omitempty"`
+	TotalCostOfCredit    *money.Money `json:"totalCostOfCredit,omitempty"`
+}
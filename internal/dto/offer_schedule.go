@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/money"
+)
+
+// OfferSchedule is an offer's amortization schedule recomputed from its
+// payments. AnnualPercentageRate is the recomputed rate; the bank's own
+// figure is StatedAnnualPercentageRate.
+type OfferSchedule struct {
+	OfferID                    uuid.UUID             `json:"offerId"`
+	BankName                   string                `json:"bankName"`
+	Principal                  money.Money           `json:"principal"`
+	AnnualPercentageRate       float64               `json:"annualPercentageRate"`
+	StatedAnnualPercentageRate *float64              `json:"statedAnnualPercentageRate,omitempty"`
+	TotalInterest              money.Money           `json:"totalInterest"`
+	TotalRepayment             money.Money           `json:"totalRepayment"`
+	Discrepancies              []OfferDiscrepancy    `json:"discrepancies,omitempty"`
+	Installments               []ScheduleInstallment `json:"installments"`
+}
+
+type ScheduleInstallment struct {
+	Number    int         `json:"number"`
+	DueDate   string      `json:"dueDate"`
+	Payment   money.Money `json:"payment"`
+	Interest  money.Money `json:"interest"`
+	Principal money.Money `json:"principal"`
+	Balance   money.Money `json:"balance"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+type SubmissionJobStatus string
+
+const (
+	JobStatusPending SubmissionJobStatus = "PENDING"
+	JobStatusRunning SubmissionJobStatus = "RUNNING"
+	JobStatusDone    SubmissionJobStatus = "DONE"
+	JobStatusFailed  SubmissionJobStatus = "FAILED"
+)
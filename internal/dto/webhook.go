@@ -0,0 +1,88 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookEventType string
+
+const (
+	WebhookEventApplicationCompleted WebhookEventType = "application.completed"
+	WebhookEventApplicationFailed    WebhookEventType = "application.failed"
+	WebhookEventApplicationCancelled WebhookEventType = "application.cancelled"
+	WebhookEventApplicationExpired   WebhookEventType = "application.expired"
+	WebhookEventApplicationAccepted  WebhookEventType = "application.accepted"
+	WebhookEventOfferReceived        WebhookEventType = "offer.received"
+	WebhookEventSubmissionFailed     WebhookEventType = "submission.failed"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "DELIVERED"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+// WebhookCallback registers a callback URL for a single application. Events
+// sent to it are signed with Secret.
+type WebhookCallback struct {
+	URL    string `json:"url" validate:"required,url,startswith=http"`
+	Secret string `json:"secret" validate:"required,min=16"`
+}
+
+type WebhookSubscriptionRequest struct {
+	URL    string             `json:"url" validate:"required,url,startswith=http"`
+	Secret string             `json:"secret" validate:"omitempty,min=16"`
+	Events []WebhookEventType `json:"events" validate:"dive,oneof=application.completed application.failed application.cancelled application.expired application.accepted offer.received submission.failed"`
+}
+
+type WebhookSubscription struct {
+	ID        uuid.UUID          `json:"id"`
+	URL       string             `json:"url"`
+	Secret    string             `json:"secret,omitempty"`
+	Events    []WebhookEventType `json:"events"`
+	CreatedAt time.Time          `json:"createdAt"`
+}
+
+type WebhookSubscriptionsResponse struct {
+	Subscriptions []WebhookSubscription `json:"subscriptions"`
+}
+
+// WebhookEvent is the body POSTed to webhook endpoints.
+type WebhookEvent struct {
+	ID            uuid.UUID        `json:"id"`
+	Type          WebhookEventType `json:"type"`
+	ApplicationID uuid.UUID        `json:"applicationId"`
+	CreatedAt     time.Time        `json:"createdAt"`
+	Data          any              `json:"data"`
+}
+
+type WebhookDeliveryAttempt struct {
+	Attempt    int       `json:"attempt"`
+	StatusCode *int      `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"durationMs"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID                `json:"id"`
+	EventID        uuid.UUID                `json:"eventId"`
+	EventType      WebhookEventType         `json:"eventType"`
+	SubscriptionID *uuid.UUID               `json:"subscriptionId,omitempty"`
+	URL            string                   `json:"url"`
+	Status         WebhookDeliveryStatus    `json:"status"`
+	Attempts       int                      `json:"attempts"`
+	NextAttemptAt  *time.Time               `json:"nextAttemptAt,omitempty"`
+	DeliveredAt    *time.Time               `json:"deliveredAt,omitempty"`
+	AttemptLog     []WebhookDeliveryAttempt `json:"attemptLog"`
+	CreatedAt      time.Time                `json:"createdAt"`
+}
+
+type WebhookDeliveriesResponse struct {
+	ApplicationID uuid.UUID         `json:"applicationId"`
+	Deliveries    []WebhookDelivery `json:"deliveries"`
+}
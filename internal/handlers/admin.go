@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/services"
+	"github.com/sirupsen/logrus"
+)
+
+type AdminHandler struct {
+	bankServices []services.BankService
+	logger       *logrus.Logger
+}
+
+func NewAdminHandler(bankServices []services.BankService, logger *logrus.Logger) *AdminHandler {
+	return &AdminHandler{
+		bankServices: bankServices,
+		logger:       logger,
+	}
+}
+
+// AdminAuthMiddleware only lets requests through that carry token as a
+// bearer token in the Authorization header.
+func AdminAuthMiddleware(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			provided, found := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !found || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+					Error:   "Unauthorized",
+					Message: "A valid admin token is required",
+					Code:    "ADMIN_UNAUTHORIZED",
+				})
+			}
+			return next(c)
+		}
+	}
+}
+
+func (h *AdminHandler) GetBanks(c echo.Context) error {
+	banks := make([]dto.BankStatus, 0, len(h.bankServices))
+	for _, bankService := range h.bankServices {
+		bank := dto.BankStatus{
+			Name: bankService.GetBankName(),
+		}
+
+		if reporter, ok := bankService.(services.CircuitBreakerReporter); ok {
+			status := reporter.CircuitBreakerStatus()
+			bank.CircuitBreaker = &status
+		}
+
+		banks = append(banks, bank)
+	}
+
+	return c.JSON(http.StatusOK, banks)
+}
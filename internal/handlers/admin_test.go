@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminRoutes(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	newRouter := func(adminToken string) *echo.Echo {
+		e := echo.New()
+		handler := NewApplicationHandler(nil, nil, config.StreamConfig{}, testPhoneConfig, config.WebhooksConfig{}, logger)
+		setupRoutes(e, handler, NewAdminHandler(nil, logger), &WebhookHandler{}, func(next echo.HandlerFunc) echo.HandlerFunc { return next }, adminToken, nil)
+		return e
+	}
+
+	getBanks := func(e *echo.Echo, authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/banks", nil)
+		if authorization != "" {
+			req.Header.Set(echo.HeaderAuthorization, authorization)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("valid token should be let through", func(t *testing.T) {
+		rec := getBanks(newRouter("secret-token"), "Bearer secret-token")
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("missing or wrong token should be unauthorized", func(t *testing.T) {
+		e := newRouter("secret-token")
+		for _, authorization := range []string{"", "Bearer wrong-token", "secret-token"} {
+			rec := getBanks(e, authorization)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			assert.Contains(t, rec.Body.String(), "ADMIN_UNAUTHORIZED")
+		}
+	})
+
+	t.Run("application reads should require the token", func(t *testing.T) {
+		e := newRouter("secret-token")
+		for _, path := range []string{
+			"/api/v1/admin/applications",
+			"/api/v1/admin/applications/" + uuid.NewString() + "/events",
+			"/api/v1/admin/applications/" + uuid.NewString() + "/webhook-deliveries",
+		} {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code, path)
+		}
+	})
+
+	t.Run("applications should not be listed outside the admin API", func(t *testing.T) {
+		e := newRouter("secret-token")
+		for _, path := range []string{
+			"/api/v1/applications",
+			"/api/v1/applications/" + uuid.NewString() + "/events",
+			"/api/v1/applications/" + uuid.NewString() + "/webhook-deliveries",
+		} {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set(echo.HeaderAuthorization, "Bearer secret-token")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Contains(t, []int{http.StatusNotFound, http.StatusMethodNotAllowed}, rec.Code, path)
+		}
+	})
+
+	t.Run("admin API should not be served without a token", func(t *testing.T) {
+		rec := getBanks(newRouter(""), "Bearer ")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
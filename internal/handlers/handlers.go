@@ -1,37 +1,89 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/aggregator/internal/config"
 	"github.com/lielamurs/aggregator/internal/dto"
 	"github.com/lielamurs/aggregator/internal/mappers"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/money"
+	"github.com/lielamurs/aggregator/internal/phone"
+	"github.com/lielamurs/aggregator/internal/safeurl"
 	"github.com/lielamurs/aggregator/internal/services"
 	"github.com/sirupsen/logrus"
 )
 
 type ApplicationHandler struct {
 	applicationService services.ApplicationService
+	updates            *services.ApplicationUpdateHub
+	streamConfig       config.StreamConfig
+	phones             phone.Normalizer
+	webhookURLs        safeurl.Policy
 	validator          *validator.Validate
 	logger             *logrus.Logger
 }
 
-func NewApplicationHandler(applicationService services.ApplicationService, logger *logrus.Logger) *ApplicationHandler {
+func NewApplicationHandler(
+	applicationService services.ApplicationService,
+	updates *services.ApplicationUpdateHub,
+	streamConfig config.StreamConfig,
+	phoneConfig config.PhoneConfig,
+	webhookConfig config.WebhooksConfig,
+	logger *logrus.Logger,
+) *ApplicationHandler {
+	phones := phone.Normalizer{
+		AllowedCountryCodes: phoneConfig.AllowedCountryCodes,
+		DefaultCountryCode:  phoneConfig.DefaultCountryCode,
+	}
 	return &ApplicationHandler{
 		applicationService: applicationService,
-		validator:          validator.New(),
+		updates:            updates,
+		streamConfig:       streamConfig,
+		phones:             phones,
+		webhookURLs:        safeurl.Policy{AllowInsecure: webhookConfig.AllowInsecureURLs},
+		validator:          newValidator(phones),
 		logger:             logger,
 	}
 }
 
+// newValidator validates money fields by their amount, so numeric tags such
+// as min apply to them, and checks fields tagged phone with phones.
+func newValidator(phones phone.Normalizer) *validator.Validate {
+	validate := validator.New()
+	validate.RegisterValidation("phone", func(fl validator.FieldLevel) bool {
+		_, err := phones.Normalize(fl.Field().String())
+		return err == nil
+	})
+	validate.RegisterCustomTypeFunc(func(field reflect.Value) any {
+		if amount, ok := field.Interface().(money.Money); ok {
+			return amount.Float64()
+		}
+		return nil
+	}, money.Money{})
+	return validate
+}
+
 func (h *ApplicationHandler) SubmitApplication(c echo.Context) error {
 	var req dto.ApplicationRequest
 
 	if err := c.Bind(&req); err != nil {
 		h.logger.WithError(err).Error("Failed to bind application request")
+		if errors.Is(err, money.ErrInvalidAmount) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Bad Request",
+				Message: "Amounts must be numbers with at most 2 decimal places",
+				Code:    "INVALID_AMOUNT",
+			})
+		}
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error:   "Bad Request",
 			Message: "Invalid request format",
@@ -50,10 +102,40 @@ func (h *ApplicationHandler) SubmitApplication(c echo.Context) error {
 		})
 	}
 
+	if req.Callback != nil {
+		if errResponse := checkWebhookURL(c.Request().Context(), h.webhookURLs, req.Callback.URL); errResponse != nil {
+			h.logger.WithField("url", req.Callback.URL).Warn("Callback URL rejected")
+			return c.JSON(http.StatusBadRequest, errResponse)
+		}
+	}
+
+	req.Phone = h.normalizePhone(req.Phone)
 	app := mappers.ToCustomerApplicationFromRequest(&req)
+	app.ClientID = clientIDFromContext(c)
+	app.ClientIP = c.RealIP()
 	response, err := h.applicationService.SubmitApplication(c.Request().Context(), app)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to submit application")
+
+		var duplicate *services.DuplicateApplicationError
+		if errors.As(err, &duplicate) {
+			return c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Conflict",
+				Message: fmt.Sprintf("Application %s with the same details is still in progress", duplicate.ApplicationID),
+				Code:    "DUPLICATE_APPLICATION",
+			})
+		}
+
+		var velocity *services.VelocityError
+		if errors.As(err, &velocity) {
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(velocity.Window.Seconds())))
+			return c.JSON(http.StatusTooManyRequests, dto.ErrorResponse{
+				Error:   "Too Many Requests",
+				Message: fmt.Sprintf("At most %d applications per %s are accepted within %d minutes", velocity.Limit, velocityRuleSubject(velocity.Rule), int(velocity.Window.Minutes())),
+				Code:    "VELOCITY_LIMIT_EXCEEDED",
+			})
+		}
+
 		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Internal Server Error",
 			Message: "Failed to process application",
@@ -61,11 +143,37 @@ func (h *ApplicationHandler) SubmitApplication(c echo.Context) error {
 		})
 	}
 
+	if response.LinkedReason != "" {
+		return c.JSON(http.StatusOK, response)
+	}
+
 	h.logger.WithField("application_id", response.ID).Info("Application submitted successfully")
 
 	return c.JSON(http.StatusCreated, response)
 }
 
+// normalizePhone returns the E.164 form of a phone number that passed
+// validation.
+func (h *ApplicationHandler) normalizePhone(number string) string {
+	normalized, err := h.phones.Normalize(number)
+	if err != nil {
+		return number
+	}
+	return normalized
+}
+
+func velocityRuleSubject(rule string) string {
+	switch rule {
+	case services.VelocityRulePhone:
+		return "phone number"
+	case services.VelocityRuleEmail:
+		return "email address"
+	case services.VelocityRuleIP:
+		return "IP address"
+	}
+	return "customer"
+}
+
 func (h *ApplicationHandler) GetApplicationStatus(c echo.Context) error {
 	id := c.Param("id")
 	applicationID, err := uuid.Parse(id)
@@ -78,9 +186,24 @@ func (h *ApplicationHandler) GetApplicationStatus(c echo.Context) error {
 		})
 	}
 
+	rankBy, errResponse := h.parseRankBy(c)
+	if errResponse != nil {
+		return c.JSON(http.StatusBadRequest, errResponse)
+	}
+
+	poll, errResponse := h.parseLongPoll(c)
+	if errResponse != nil {
+		return c.JSON(http.StatusBadRequest, errResponse)
+	}
+
 	h.logger.WithField("application_id", applicationID).Info("Retrieving application status")
 
-	modelApp, err := h.applicationService.GetApplicationStatus(c.Request().Context(), applicationID)
+	var modelApp *models.Application
+	if poll != nil {
+		modelApp, err = h.waitForApplication(c, applicationID, poll)
+	} else {
+		modelApp, err = h.applicationService.GetApplicationStatus(c.Request().Context(), applicationID)
+	}
 	if err != nil {
 		h.logger.WithError(err).WithField("application_id", applicationID).Error("Failed to get application status")
 
@@ -105,10 +228,156 @@ func (h *ApplicationHandler) GetApplicationStatus(c echo.Context) error {
 		"offers_count":   len(modelApp.Offers),
 	}).Info("Application status retrieved successfully")
 
+	etag := applicationETag(modelApp)
+	c.Response().Header().Set("ETag", etag)
+	if ifNoneMatch := c.Request().Header.Get("If-None-Match"); ifNoneMatch != "" && etagMatches(ifNoneMatch, etag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
 	response := mappers.ToApplicationStatusResponseFromModel(modelApp)
+	if response.Comparison != nil && rankBy != response.Comparison.RankBy {
+		response.Comparison = mappers.ToOfferComparisonFromModels(modelApp.Offers, modelApp.Amount, rankBy)
+	}
 	return c.JSON(http.StatusOK, response)
 }
 
+func (h *ApplicationHandler) GetApplicationEvents(c echo.Context) error {
+	id := c.Param("id")
+	applicationID, err := uuid.Parse(id)
+	if err != nil {
+		h.logger.WithError(err).WithField("id", id).Error("Invalid application ID format")
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid application ID format",
+			Code:    "INVALID_APPLICATION_ID",
+		})
+	}
+
+	events, err := h.applicationService.GetApplicationEvents(c.Request().Context(), applicationID)
+	if err != nil {
+		h.logger.WithError(err).WithField("application_id", applicationID).Error("Failed to get application events")
+
+		if isNotFoundError(err) {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Application not found",
+				Code:    "APPLICATION_NOT_FOUND",
+			})
+		}
+
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve application events",
+			Code:    "APPLICATION_EVENTS_RETRIEVAL_FAILED",
+		})
+	}
+
+	return c.JSON(http.StatusOK, mappers.ToApplicationEventsResponseFromModels(applicationID, events))
+}
+
+func (h *ApplicationHandler) CancelApplication(c echo.Context) error {
+	id := c.Param("id")
+	applicationID, err := uuid.Parse(id)
+	if err != nil {
+		h.logger.WithError(err).WithField("id", id).Error("Invalid application ID format")
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid application ID format",
+			Code:    "INVALID_APPLICATION_ID",
+		})
+	}
+
+	application, err := h.applicationService.CancelApplication(c.Request().Context(), applicationID)
+	if err != nil {
+		h.logger.WithError(err).WithField("application_id", applicationID).Error("Failed to cancel application")
+
+		var transitionErr *services.InvalidTransitionError
+		switch {
+		case errors.As(err, &transitionErr):
+			return c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Conflict",
+				Message: "Application cannot be cancelled, application is " + string(transitionErr.From),
+				Code:    "INVALID_STATUS_TRANSITION",
+			})
+		case errors.Is(err, services.ErrApplicationStatusChanged):
+			return c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Conflict",
+				Message: "Application status changed while cancelling the application",
+				Code:    "APPLICATION_STATUS_CONFLICT",
+			})
+		case errors.Is(err, services.ErrAcceptanceInProgress):
+			return c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Conflict",
+				Message: "An offer of the application is being accepted, please try again later",
+				Code:    "OFFER_ACCEPTANCE_IN_PROGRESS",
+			})
+		case isNotFoundError(err):
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Application not found",
+				Code:    "APPLICATION_NOT_FOUND",
+			})
+		}
+
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to cancel application",
+			Code:    "APPLICATION_CANCELLATION_FAILED",
+		})
+	}
+
+	h.logger.WithField("application_id", applicationID).Info("Application cancelled successfully")
+
+	return c.JSON(http.StatusOK, mappers.ToApplicationStatusResponseFromModel(application))
+}
+
+func (h *ApplicationHandler) ListApplications(c echo.Context) error {
+	var req dto.ApplicationSearchRequest
+
+	if err := c.Bind(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to bind application search request")
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+			Code:    "INVALID_REQUEST_FORMAT",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.WithError(err).WithField("request", req).Error("Application search validation failed")
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Validation Failed",
+			Message: extractValidationErrors(err),
+			Code:    "VALIDATION_FAILED",
+		})
+	}
+
+	if req.Phone != "" {
+		req.Phone = h.normalizePhone(req.Phone)
+	}
+
+	applications, nextCursor, err := h.applicationService.SearchApplications(c.Request().Context(), &req)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to search applications")
+
+		if errors.Is(err, services.ErrInvalidCursor) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Bad Request",
+				Message: "Invalid cursor",
+				Code:    "INVALID_CURSOR",
+			})
+		}
+
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to search applications",
+			Code:    "APPLICATION_SEARCH_FAILED",
+		})
+	}
+
+	return c.JSON(http.StatusOK, mappers.ToApplicationListResponseFromModels(applications, nextCursor))
+}
+
 func (h *ApplicationHandler) HealthCheck(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]any{
 		"status":  "healthy",
@@ -127,12 +396,20 @@ func extractValidationErrors(err error) string {
 		switch validationError.Tag() {
 		case "required":
 			messages = append(messages, validationError.Field()+" is required")
+		case "url":
+			messages = append(messages, validationError.Field()+" must be a valid URL")
 		case "email":
 			messages = append(messages, validationError.Field()+" must be a valid email address")
 		case "min":
 			messages = append(messages, validationError.Field()+" must be greater than or equal to "+validationError.Param())
+		case "max":
+			messages = append(messages, validationError.Field()+" must be less than or equal to "+validationError.Param())
+		case "datetime":
+			messages = append(messages, validationError.Field()+" must be an RFC 3339 timestamp")
 		case "oneof":
 			messages = append(messages, validationError.Field()+" must be one of: "+validationError.Param())
+		case "phone":
+			messages = append(messages, validationError.Field()+" must be a phone number with an allowed country code, e.g. +37126000000")
 		default:
 			messages = append(messages, validationError.Field()+" is invalid")
 		}
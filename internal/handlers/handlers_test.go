@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/services"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPhoneConfig = config.PhoneConfig{AllowedCountryCodes: []string{"371"}, DefaultCountryCode: "371"}
+
+type fakeCancellationService struct {
+	services.ApplicationService
+	application *models.Application
+	err         error
+}
+
+func (f *fakeCancellationService) CancelApplication(ctx context.Context, applicationID uuid.UUID) (*models.Application, error) {
+	return f.application, f.err
+}
+
+func cancelApplication(t *testing.T, service services.ApplicationService, applicationID string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	handler := NewApplicationHandler(service, nil, config.StreamConfig{}, testPhoneConfig, config.WebhooksConfig{}, logger)
+	e := echo.New()
+	e.POST("/applications/:id/cancel", handler.CancelApplication)
+
+	req := httptest.NewRequest(http.MethodPost, "/applications/"+applicationID+"/cancel", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCancelApplication(t *testing.T) {
+	applicationID := uuid.New()
+
+	t.Run("cancelled application should be returned with withdrawal results", func(t *testing.T) {
+		withdrawalError := "SolidBank cancellation failed: HTTP 503"
+		service := &fakeCancellationService{application: &models.Application{
+			ID:     applicationID,
+			Status: string(dto.StatusCancelled),
+			BankSubmissions: []models.BankSubmission{
+				{BankName: "SolidBank", Status: string(dto.SubmissionStatusCancelled), WithdrawalError: &withdrawalError},
+			},
+		}}
+
+		rec := cancelApplication(t, service, applicationID.String())
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response dto.ApplicationStatusResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, dto.StatusCancelled, response.Status)
+		require.Len(t, response.BankSubmissions, 1)
+		assert.Equal(t, dto.SubmissionStatusCancelled, response.BankSubmissions[0].Status)
+		assert.Equal(t, withdrawalError, *response.BankSubmissions[0].WithdrawalError)
+	})
+
+	tests := []struct {
+		name          string
+		applicationID string
+		err           error
+		expectedCode  int
+		expectedBody  string
+	}{
+		{
+			name:          "invalid application id",
+			applicationID: "not-a-uuid",
+			expectedCode:  http.StatusBadRequest,
+			expectedBody:  "INVALID_APPLICATION_ID",
+		},
+		{
+			name:         "application not found",
+			err:          fmt.Errorf("application with ID %s not found", applicationID),
+			expectedCode: http.StatusNotFound,
+			expectedBody: "APPLICATION_NOT_FOUND",
+		},
+		{
+			name:         "application already finished",
+			err:          &services.InvalidTransitionError{From: dto.StatusFailed, To: dto.StatusCancelled},
+			expectedCode: http.StatusConflict,
+			expectedBody: "INVALID_STATUS_TRANSITION",
+		},
+		{
+			name:         "concurrent status change",
+			err:          services.ErrApplicationStatusChanged,
+			expectedCode: http.StatusConflict,
+			expectedBody: "APPLICATION_STATUS_CONFLICT",
+		},
+		{
+			name:         "internal error",
+			err:          fmt.Errorf("connection refused"),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "APPLICATION_CANCELLATION_FAILED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := tt.applicationID
+			if id == "" {
+				id = applicationID.String()
+			}
+
+			rec := cancelApplication(t, &fakeCancellationService{err: tt.err}, id)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+type fakeSubmitService struct {
+	services.ApplicationService
+	response  *dto.ApplicationResponse
+	err       error
+	submitted *dto.CustomerApplication
+}
+
+func (f *fakeSubmitService) SubmitApplication(ctx context.Context, app *dto.CustomerApplication) (*dto.ApplicationResponse, error) {
+	f.submitted = app
+	return f.response, f.err
+}
+
+func TestSubmitApplication_Velocity(t *testing.T) {
+	existingID := uuid.New()
+	body := `{"phone":"+37126000000","email":"john.doe@example.com","monthlyIncome":3000,"monthlyExpenses":1200,` +
+		`"maritalStatus":"MARRIED","agreeToBeScored":true,"amount":8000,"dependents":1}`
+
+	tests := []struct {
+		name         string
+		service      *fakeSubmitService
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "new application should be created",
+			service:      &fakeSubmitService{response: &dto.ApplicationResponse{ID: existingID, Status: dto.StatusPending}},
+			expectedCode: http.StatusCreated,
+			expectedBody: existingID.String(),
+		},
+		{
+			name: "linked application should be returned",
+			service: &fakeSubmitService{response: &dto.ApplicationResponse{
+				ID:           existingID,
+				Status:       dto.StatusProcessing,
+				LinkedReason: dto.LinkReasonDuplicate,
+			}},
+			expectedCode: http.StatusOK,
+			expectedBody: `"linkedReason":"DUPLICATE_APPLICATION"`,
+		},
+		{
+			name:         "duplicate application should conflict",
+			service:      &fakeSubmitService{err: &services.DuplicateApplicationError{ApplicationID: existingID}},
+			expectedCode: http.StatusConflict,
+			expectedBody: "DUPLICATE_APPLICATION",
+		},
+		{
+			name:         "application over a velocity limit should be throttled",
+			service:      &fakeSubmitService{err: &services.VelocityError{Rule: services.VelocityRuleIP, Limit: 20, Window: time.Hour}},
+			expectedCode: http.StatusTooManyRequests,
+			expectedBody: "At most 20 applications per IP address are accepted within 60 minutes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := logrus.New()
+			logger.SetLevel(logrus.FatalLevel)
+
+			handler := NewApplicationHandler(tt.service, nil, config.StreamConfig{}, testPhoneConfig, config.WebhooksConfig{}, logger)
+			e := echo.New()
+			e.POST("/applications", handler.SubmitApplication)
+
+			req := httptest.NewRequest(http.MethodPost, "/applications", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.RemoteAddr = "203.0.113.7:51234"
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			require.NotNil(t, tt.service.submitted)
+			assert.Equal(t, "203.0.113.7", tt.service.submitted.ClientIP)
+			if tt.expectedCode == http.StatusTooManyRequests {
+				assert.Equal(t, "3600", rec.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestSubmitApplication_Phone(t *testing.T) {
+	submit := func(t *testing.T, service services.ApplicationService, phone string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		logger := logrus.New()
+		logger.SetLevel(logrus.FatalLevel)
+
+		handler := NewApplicationHandler(service, nil, config.StreamConfig{}, testPhoneConfig, config.WebhooksConfig{}, logger)
+		e := echo.New()
+		e.POST("/applications", handler.SubmitApplication)
+
+		body := fmt.Sprintf(`{"phone":%q,"email":"john.doe@example.com","monthlyIncome":3000,"monthlyExpenses":1200,`+
+			`"maritalStatus":"MARRIED","agreeToBeScored":true,"amount":8000,"dependents":1}`, phone)
+		req := httptest.NewRequest(http.MethodPost, "/applications", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, number := range []string{"+37126000000", "+371 26 000 000", "0037126000000", "26000000"} {
+		t.Run(number+" should be normalized to E.164", func(t *testing.T) {
+			service := &fakeSubmitService{response: &dto.ApplicationResponse{ID: uuid.New(), Status: dto.StatusPending}}
+
+			rec := submit(t, service, number)
+
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			require.NotNil(t, service.submitted)
+			assert.Equal(t, "+37126000000", service.submitted.CustomerData.Phone)
+		})
+	}
+
+	for _, number := range []string{"invalid-phone", "+371 26", "+4915112345678"} {
+		t.Run(number+" should be rejected", func(t *testing.T) {
+			rec := submit(t, nil, number)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), "VALIDATION_FAILED")
+			assert.Contains(t, rec.Body.String(), "Phone must be a phone number with an allowed country code")
+		})
+	}
+}
+
+func TestSubmitApplication_InvalidAmount(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	handler := NewApplicationHandler(nil, nil, config.StreamConfig{}, testPhoneConfig, config.WebhooksConfig{}, logger)
+	e := echo.New()
+	e.POST("/applications", handler.SubmitApplication)
+
+	body := `{"phone":"+37126000000","email":"john.doe@example.com","monthlyIncome":3000,"monthlyExpenses":1200,` +
+		`"maritalStatus":"MARRIED","agreeToBeScored":true,"amount":8000.555,"dependents":1}`
+	req := httptest.NewRequest(http.MethodPost, "/applications", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "INVALID_AMOUNT")
+}
+
+func TestSubmitApplication_CallbackURL(t *testing.T) {
+	submit := func(t *testing.T, service services.ApplicationService, callbackURL string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		logger := logrus.New()
+		logger.SetLevel(logrus.FatalLevel)
+
+		handler := NewApplicationHandler(service, nil, config.StreamConfig{}, testPhoneConfig, config.WebhooksConfig{}, logger)
+		handler.webhookURLs.Resolver = testResolver
+		e := echo.New()
+		e.POST("/applications", handler.SubmitApplication)
+
+		body := fmt.Sprintf(`{"phone":"+37126000000","email":"john.doe@example.com","monthlyIncome":3000,"monthlyExpenses":1200,`+
+			`"maritalStatus":"MARRIED","agreeToBeScored":true,"amount":8000,"dependents":1,`+
+			`"callback":{"url":%q,"secret":"callback-secret-0123"}}`, callbackURL)
+		req := httptest.NewRequest(http.MethodPost, "/applications", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("public https callback should be accepted", func(t *testing.T) {
+		service := &fakeSubmitService{response: &dto.ApplicationResponse{ID: uuid.New(), Status: dto.StatusPending}}
+
+		rec := submit(t, service, "https://client.example.com/hooks")
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		require.NotNil(t, service.submitted)
+	})
+
+	for _, callbackURL := range []string{"http://client.example.com/hooks", "https://intranet.example.com/hooks", "https://127.0.0.1:8080/hooks"} {
+		t.Run(callbackURL+" should be rejected", func(t *testing.T) {
+			rec := submit(t, nil, callbackURL)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), "INVALID_WEBHOOK_URL")
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/services"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyMiddleware makes requests carrying an Idempotency-Key header
+// safe to retry: the first response is stored and replayed for identical
+// requests with the same key. Server errors are not stored so the request
+// can be retried.
+func IdempotencyMiddleware(idempotencyService services.IdempotencyService, logger *logrus.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(HeaderIdempotencyKey)
+			if key == "" {
+				return next(c)
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+					Error:   "Bad Request",
+					Message: "Idempotency-Key must be at most 255 characters",
+					Code:    "INVALID_IDEMPOTENCY_KEY",
+				})
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+					Error:   "Bad Request",
+					Message: "Invalid request format",
+					Code:    "INVALID_REQUEST_FORMAT",
+				})
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := c.Request().Context()
+			log := logger.WithField("idempotency_key", key)
+
+			stored, lockToken, err := idempotencyService.Begin(ctx, key, requestHash(body))
+			switch {
+			case errors.Is(err, services.ErrIdempotencyKeyReused):
+				log.Warn("Idempotency key reused with a different request")
+				return c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+					Error:   "Unprocessable Entity",
+					Message: "Idempotency-Key was already used with a different request",
+					Code:    "IDEMPOTENCY_KEY_REUSED",
+				})
+			case errors.Is(err, services.ErrIdempotencyKeyInProgress):
+				return c.JSON(http.StatusConflict, dto.ErrorResponse{
+					Error:   "Conflict",
+					Message: "A request with this Idempotency-Key is still being processed",
+					Code:    "IDEMPOTENCY_KEY_IN_PROGRESS",
+				})
+			case err != nil:
+				log.WithError(err).Error("Failed to check idempotency key")
+				return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+					Error:   "Internal Server Error",
+					Message: "Failed to process application",
+					Code:    "APPLICATION_PROCESSING_FAILED",
+				})
+			case stored != nil:
+				log.Info("Replaying stored response for idempotency key")
+				c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+				return c.Blob(stored.StatusCode, echo.MIMEApplicationJSONCharsetUTF8, stored.Body)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			handlerErr := next(c)
+
+			// The outcome must be recorded even when the client hung up
+			// mid-request; otherwise the key stays in progress until its
+			// lock expires and a retry repeats the work.
+			ctx = context.WithoutCancel(ctx)
+
+			status := c.Response().Status
+			if handlerErr != nil || status >= http.StatusInternalServerError {
+				if err := idempotencyService.Release(ctx, key, lockToken); err != nil {
+					log.WithError(err).Error("Failed to release idempotency key")
+				}
+				return handlerErr
+			}
+
+			response := services.IdempotentResponse{StatusCode: status, Body: recorder.body.Bytes()}
+			if err := idempotencyService.Complete(ctx, key, lockToken, response); err != nil {
+				log.WithError(err).Error("Failed to store idempotent response")
+			}
+
+			return nil
+		}
+	}
+}
+
+// requestHash fingerprints a JSON body independently of formatting and key
+// order, so a client re-serialising the same payload is not treated as a
+// different request.
+func requestHash(body []byte) string {
+	var payload any
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if canonical, err := json.Marshal(payload); err == nil {
+			body = canonical
+		}
+	}
+
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/aggregator/internal/repository"
+	"github.com/lielamurs/aggregator/internal/services"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeIdempotencyKey struct {
+	hash      string
+	lockToken string
+	response  *services.IdempotentResponse
+}
+
+type fakeIdempotencyService struct {
+	keys map[string]*fakeIdempotencyKey
+}
+
+func (f *fakeIdempotencyService) Begin(ctx context.Context, key, requestHash string) (*services.IdempotentResponse, string, error) {
+	existing, ok := f.keys[key]
+	if !ok {
+		lockToken := "lock-" + key
+		f.keys[key] = &fakeIdempotencyKey{hash: requestHash, lockToken: lockToken}
+		return nil, lockToken, nil
+	}
+	if existing.hash != requestHash {
+		return nil, "", services.ErrIdempotencyKeyReused
+	}
+	if existing.response == nil {
+		return nil, "", services.ErrIdempotencyKeyInProgress
+	}
+	return existing.response, "", nil
+}
+
+func (f *fakeIdempotencyService) Complete(ctx context.Context, key, lockToken string, response services.IdempotentResponse) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	existing, ok := f.keys[key]
+	if !ok || existing.lockToken != lockToken {
+		return repository.ErrLeaseLost
+	}
+	existing.response = &response
+	return nil
+}
+
+func (f *fakeIdempotencyService) Release(ctx context.Context, key, lockToken string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	existing, ok := f.keys[key]
+	if !ok || existing.lockToken != lockToken {
+		return repository.ErrLeaseLost
+	}
+	delete(f.keys, key)
+	return nil
+}
+
+func newIdempotentTestServer(status int) (*echo.Echo, *fakeIdempotencyService, *int) {
+	return newIdempotentTestServerWithHook(status, nil)
+}
+
+// newIdempotentTestServerWithHook runs hook inside the handler, before the
+// response is written.
+func newIdempotentTestServerWithHook(status int, hook func()) (*echo.Echo, *fakeIdempotencyService, *int) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	service := &fakeIdempotencyService{keys: make(map[string]*fakeIdempotencyKey)}
+	calls := 0
+
+	e := echo.New()
+	e.POST("/applications", func(c echo.Context) error {
+		calls++
+		if hook != nil {
+			hook()
+		}
+		return c.JSON(status, map[string]int{"call": calls})
+	}, IdempotencyMiddleware(service, logger))
+
+	return e, service, &calls
+}
+
+func postWithKey(e *echo.Echo, key, body string) *httptest.ResponseRecorder {
+	return postWithKeyContext(context.Background(), e, key, body)
+}
+
+func postWithKeyContext(ctx context.Context, e *echo.Echo, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/applications", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	t.Run("replay should return the stored response without calling the handler", func(t *testing.T) {
+		e, _, calls := newIdempotentTestServer(http.StatusCreated)
+
+		first := postWithKey(e, "key-1", `{"amount": 100, "email": "john@example.com"}`)
+		require.Equal(t, http.StatusCreated, first.Code)
+
+		replay := postWithKey(e, "key-1", `{"email":"john@example.com","amount":100}`)
+		assert.Equal(t, http.StatusCreated, replay.Code)
+		assert.Equal(t, first.Body.String(), replay.Body.String())
+		assert.Equal(t, "true", replay.Header().Get(HeaderIdempotentReplayed))
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("reused key with a different payload should be rejected", func(t *testing.T) {
+		e, _, calls := newIdempotentTestServer(http.StatusCreated)
+
+		postWithKey(e, "key-1", `{"amount": 100}`)
+		rec := postWithKey(e, "key-1", `{"amount": 200}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), "IDEMPOTENCY_KEY_REUSED")
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("key still in progress should conflict", func(t *testing.T) {
+		e, service, calls := newIdempotentTestServer(http.StatusCreated)
+		service.keys["key-1"] = &fakeIdempotencyKey{hash: requestHash([]byte(`{}`))}
+
+		rec := postWithKey(e, "key-1", `{}`)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, 0, *calls)
+	})
+
+	t.Run("server errors should release the key", func(t *testing.T) {
+		e, service, calls := newIdempotentTestServer(http.StatusInternalServerError)
+
+		postWithKey(e, "key-1", `{}`)
+		assert.NotContains(t, service.keys, "key-1")
+
+		postWithKey(e, "key-1", `{}`)
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("response should be stored after the client disconnects", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		e, service, calls := newIdempotentTestServerWithHook(http.StatusCreated, cancel)
+
+		postWithKeyContext(ctx, e, "key-1", `{}`)
+		require.Contains(t, service.keys, "key-1")
+		require.NotNil(t, service.keys["key-1"].response)
+
+		replay := postWithKey(e, "key-1", `{}`)
+		assert.Equal(t, http.StatusCreated, replay.Code)
+		assert.Equal(t, "true", replay.Header().Get(HeaderIdempotentReplayed))
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("server errors should release the key after the client disconnects", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		e, service, _ := newIdempotentTestServerWithHook(http.StatusInternalServerError, cancel)
+
+		postWithKeyContext(ctx, e, "key-1", `{}`)
+		assert.NotContains(t, service.keys, "key-1")
+	})
+
+	t.Run("key taken over by a retry should not be overwritten", func(t *testing.T) {
+		var service *fakeIdempotencyService
+		e, service, _ := newIdempotentTestServerWithHook(http.StatusCreated, func() {
+			service.keys["key-1"].lockToken = "retry"
+		})
+
+		postWithKey(e, "key-1", `{}`)
+		require.Contains(t, service.keys, "key-1")
+		assert.Nil(t, service.keys["key-1"].response)
+	})
+
+	t.Run("requests without a key should not be deduplicated", func(t *testing.T) {
+		e, service, calls := newIdempotentTestServer(http.StatusCreated)
+
+		postWithKey(e, "", `{}`)
+		postWithKey(e, "", `{}`)
+		assert.Equal(t, 2, *calls)
+		assert.Empty(t, service.keys)
+	})
+
+	t.Run("overlong key should be rejected", func(t *testing.T) {
+		e, _, calls := newIdempotentTestServer(http.StatusCreated)
+
+		rec := postWithKey(e, strings.Repeat("k", 256), `{}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, 0, *calls)
+	})
+}
+
+func TestRequestHash(t *testing.T) {
+	assert.Equal(t, requestHash([]byte(`{"a": 1, "b": [1, 2]}`)), requestHash([]byte(`{"b":[1,2],"a":1}`)))
+	assert.NotEqual(t, requestHash([]byte(`{"a": 1}`)), requestHash([]byte(`{"a": 2}`)))
+	assert.NotEqual(t, requestHash([]byte(`not json`)), requestHash([]byte(`not  json`)))
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/services"
+)
+
+const longPollWriteGrace = 10 * time.Second
+
+// longPoll describes a status request that waits for a change: either
+// until the application reaches WaitFor or, without WaitFor, until its ETag
+// differs from ETag.
+type longPoll struct {
+	waitFor dto.ApplicationStatus
+	etag    string
+	timeout time.Duration
+}
+
+// parseLongPoll reads the waitFor and timeout query parameters and the
+// If-None-Match header. It returns nil for ordinary status requests.
+func (h *ApplicationHandler) parseLongPoll(c echo.Context) (*longPoll, *dto.ErrorResponse) {
+	waitFor := c.QueryParam("waitFor")
+	timeout := c.QueryParam("timeout")
+	etag := c.Request().Header.Get("If-None-Match")
+
+	if waitFor == "" && timeout == "" && etag == "" {
+		return nil, nil
+	}
+
+	poll := &longPoll{
+		waitFor: dto.ApplicationStatus(waitFor),
+		etag:    etag,
+		timeout: h.maxLongPollTimeout(),
+	}
+
+	if waitFor != "" {
+		if err := h.validator.Var(waitFor, "oneof=PENDING PROCESSING COMPLETED FAILED CANCELLED EXPIRED ACCEPTED"); err != nil {
+			return nil, &dto.ErrorResponse{
+				Error:   "Bad Request",
+				Message: "waitFor must be one of: PENDING PROCESSING COMPLETED FAILED CANCELLED EXPIRED ACCEPTED",
+				Code:    "INVALID_WAIT_FOR",
+			}
+		}
+	}
+
+	if timeout != "" {
+		parsed, ok := parseTimeout(timeout)
+		if !ok {
+			return nil, &dto.ErrorResponse{
+				Error:   "Bad Request",
+				Message: "timeout must be a positive duration such as 25s or a number of seconds",
+				Code:    "INVALID_TIMEOUT",
+			}
+		}
+		poll.timeout = min(parsed, poll.timeout)
+	}
+
+	return poll, nil
+}
+
+func (h *ApplicationHandler) maxLongPollTimeout() time.Duration {
+	return time.Duration(max(h.streamConfig.LongPollMaxTimeoutSeconds, 1)) * time.Second
+}
+
+// parseTimeout accepts Go durations ("25s", "1m") and plain seconds ("25").
+func parseTimeout(value string) (time.Duration, bool) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, seconds > 0
+	}
+	duration, err := time.ParseDuration(value)
+	return duration, err == nil && duration > 0
+}
+
+// waitForApplication returns the application once the long poll is
+// satisfied or its timeout elapses, whichever comes first.
+func (h *ApplicationHandler) waitForApplication(c echo.Context, applicationID uuid.UUID, poll *longPoll) (*models.Application, error) {
+	ctx := c.Request().Context()
+
+	// The wait may exceed the server's WriteTimeout.
+	_ = http.NewResponseController(c.Response()).SetWriteDeadline(time.Now().Add(poll.timeout + longPollWriteGrace))
+
+	updates, unsubscribe := h.updates.Subscribe(applicationID)
+	defer unsubscribe()
+
+	timer := time.NewTimer(poll.timeout)
+	defer timer.Stop()
+
+	for {
+		application, err := h.applicationService.GetApplicationStatus(ctx, applicationID)
+		if err != nil {
+			return nil, err
+		}
+
+		if poll.etag == "" && poll.waitFor == "" {
+			poll.etag = applicationETag(application)
+		}
+
+		if poll.satisfiedBy(application) {
+			return application, nil
+		}
+
+		select {
+		case <-updates:
+		case <-timer.C:
+			return application, nil
+		case <-ctx.Done():
+			return application, nil
+		case <-h.updates.Done():
+			return application, nil
+		}
+	}
+}
+
+// satisfiedBy reports whether the long poll can return. A poll waiting for a
+// status also returns once processing finished, as the status may never be
+// reached.
+func (p *longPoll) satisfiedBy(application *models.Application) bool {
+	status := dto.ApplicationStatus(application.Status)
+	if p.waitFor != "" {
+		return status == p.waitFor || services.IsProcessingFinished(status)
+	}
+	return !etagMatches(p.etag, applicationETag(application))
+}
+
+// applicationETag identifies the state of an application as seen by the
+// status endpoint: it changes whenever the status, the set of offers, the
+// customer's decision on one of them or the progress of a bank submission
+// does.
+func applicationETag(application *models.Application) string {
+	offers := make([]string, 0, len(application.Offers))
+	for _, offer := range application.Offers {
+		entry := offer.ID.String() + ":" + offer.Status
+		if offer.AcceptanceStatus != nil {
+			entry += ":" + *offer.AcceptanceStatus
+		}
+		offers = append(offers, entry)
+	}
+	slices.Sort(offers)
+
+	submissions := make([]string, 0, len(application.BankSubmissions))
+	for _, submission := range application.BankSubmissions {
+		entry := submission.BankName + ":" + submission.Status
+		if submission.Error != nil {
+			entry += ":" + *submission.Error
+		}
+		submissions = append(submissions, entry)
+	}
+	slices.Sort(submissions)
+
+	state := application.Status + "|" + strings.Join(offers, ",") + "|" + strings.Join(submissions, ",")
+	hash := sha256.Sum256([]byte(state))
+	return `"` + hex.EncodeToString(hash[:8]) + `"`
+}
+
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// isLongLivedRequest reports whether the request may legitimately outlive
+// the request timeout: status streams and long-poll status requests, which
+// bound their own duration.
+func isLongLivedRequest(c echo.Context) bool {
+	if strings.HasSuffix(c.Path(), "/stream") {
+		return true
+	}
+	return c.Request().Method == http.MethodGet &&
+		(c.QueryParam("waitFor") != "" || c.QueryParam("timeout") != "" || c.Request().Header.Get("If-None-Match") != "")
+}
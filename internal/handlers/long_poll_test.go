@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/services"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLongPollTestServer(service services.ApplicationService, hub *services.ApplicationUpdateHub) *echo.Echo {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	handler := NewApplicationHandler(service, hub, config.StreamConfig{LongPollMaxTimeoutSeconds: 5}, testPhoneConfig, config.WebhooksConfig{}, logger)
+	e := echo.New()
+	e.GET("/applications/:id", handler.GetApplicationStatus)
+	return e
+}
+
+func getStatus(e *echo.Echo, applicationID uuid.UUID, query, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/applications/"+applicationID.String()+query, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestApplicationHandler_LongPoll(t *testing.T) {
+	applicationID := uuid.New()
+
+	t.Run("waitFor should block until the status is reached", func(t *testing.T) {
+		service := &fakeApplicationService{}
+		service.set(&models.Application{ID: applicationID, Status: "PROCESSING"})
+		hub := services.NewApplicationUpdateHub(nil, logrus.New())
+		e := newLongPollTestServer(service, hub)
+
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- getStatus(e, applicationID, "?waitFor=COMPLETED&timeout=5s", "") }()
+
+		select {
+		case <-done:
+			t.Fatal("long poll returned before the status was reached")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		service.set(&models.Application{ID: applicationID, Status: "COMPLETED"})
+		hub.Notify(applicationID)
+
+		var rec *httptest.ResponseRecorder
+		select {
+		case rec = <-done:
+		case <-time.After(time.Second):
+			t.Fatal("long poll did not return after the status changed")
+		}
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response dto.ApplicationStatusResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, dto.StatusCompleted, response.Status)
+		assert.NotEmpty(t, rec.Header().Get("ETag"))
+	})
+
+	t.Run("waitFor should return immediately once processing finished", func(t *testing.T) {
+		service := &fakeApplicationService{}
+		service.set(&models.Application{ID: applicationID, Status: "FAILED"})
+		e := newLongPollTestServer(service, services.NewApplicationUpdateHub(nil, logrus.New()))
+
+		started := time.Now()
+		rec := getStatus(e, applicationID, "?waitFor=COMPLETED&timeout=5s", "")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Less(t, time.Since(started), time.Second)
+	})
+
+	t.Run("unchanged ETag should return 304 after the timeout", func(t *testing.T) {
+		service := &fakeApplicationService{}
+		service.set(&models.Application{ID: applicationID, Status: "PROCESSING"})
+		e := newLongPollTestServer(service, services.NewApplicationUpdateHub(nil, logrus.New()))
+
+		etag := getStatus(e, applicationID, "", "").Header().Get("ETag")
+		require.NotEmpty(t, etag)
+
+		started := time.Now()
+		rec := getStatus(e, applicationID, "?timeout=200ms", etag)
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.GreaterOrEqual(t, time.Since(started), 200*time.Millisecond)
+	})
+
+	t.Run("stale ETag should return the current state immediately", func(t *testing.T) {
+		service := &fakeApplicationService{}
+		service.set(&models.Application{ID: applicationID, Status: "PROCESSING"})
+		e := newLongPollTestServer(service, services.NewApplicationUpdateHub(nil, logrus.New()))
+
+		etag := getStatus(e, applicationID, "", "").Header().Get("ETag")
+		service.set(&models.Application{
+			ID:     applicationID,
+			Status: "PROCESSING",
+			Offers: []models.Offer{{ID: uuid.New(), BankName: "FastBank", Status: "PROCESSED"}},
+		})
+
+		rec := getStatus(e, applicationID, "?timeout=5s", etag)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+	})
+
+	t.Run("submission progress should change the ETag", func(t *testing.T) {
+		submissionError := "timeout"
+		application := &models.Application{
+			ID:              applicationID,
+			Status:          "PROCESSING",
+			BankSubmissions: []models.BankSubmission{{BankName: "FastBank", Status: "DRAFT"}},
+		}
+		etag := applicationETag(application)
+
+		application.BankSubmissions[0].Status = "RETRY"
+		retried := applicationETag(application)
+		assert.NotEqual(t, etag, retried)
+
+		application.BankSubmissions[0].Error = &submissionError
+		assert.NotEqual(t, retried, applicationETag(application))
+	})
+
+	invalid := []struct {
+		name         string
+		query        string
+		expectedCode string
+	}{
+		{name: "unknown waitFor status", query: "?waitFor=DONE", expectedCode: "INVALID_WAIT_FOR"},
+		{name: "malformed timeout", query: "?timeout=soon", expectedCode: "INVALID_TIMEOUT"},
+		{name: "negative timeout", query: "?timeout=-5s", expectedCode: "INVALID_TIMEOUT"},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name+" should be rejected", func(t *testing.T) {
+			e := newLongPollTestServer(&fakeApplicationService{}, services.NewApplicationUpdateHub(nil, logrus.New()))
+
+			rec := getStatus(e, applicationID, tt.query, "")
+
+			require.Equal(t, http.StatusBadRequest, rec.Code)
+			var response dto.ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedCode, response.Code)
+		})
+	}
+}
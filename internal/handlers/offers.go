@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/mappers"
+	"github.com/lielamurs/aggregator/internal/services"
+	"github.com/sirupsen/logrus"
+)
+
+func (h *ApplicationHandler) AcceptOffer(c echo.Context) error {
+	id := c.Param("id")
+	applicationID, err := uuid.Parse(id)
+	if err != nil {
+		h.logger.WithError(err).WithField("id", id).Error("Invalid application ID format")
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid application ID format",
+			Code:    "INVALID_APPLICATION_ID",
+		})
+	}
+
+	offerParam := c.Param("offerId")
+	offerID, err := uuid.Parse(offerParam)
+	if err != nil {
+		h.logger.WithError(err).WithField("offer_id", offerParam).Error("Invalid offer ID format")
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid offer ID format",
+			Code:    "INVALID_OFFER_ID",
+		})
+	}
+
+	logger := h.logger.WithFields(logrus.Fields{
+		"application_id": applicationID,
+		"offer_id":       offerID,
+	})
+
+	application, err := h.applicationService.AcceptOffer(c.Request().Context(), applicationID, offerID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to accept offer")
+
+		var transitionErr *services.InvalidTransitionError
+		switch {
+		case errors.As(err, &transitionErr):
+			return c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Conflict",
+				Message: "Offers can only be accepted on completed applications, application is " + string(transitionErr.From),
+				Code:    "INVALID_STATUS_TRANSITION",
+			})
+		case errors.Is(err, services.ErrApplicationStatusChanged):
+			return c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Conflict",
+				Message: "Application status changed while accepting the offer",
+				Code:    "APPLICATION_STATUS_CONFLICT",
+			})
+		case errors.Is(err, services.ErrAcceptanceInProgress):
+			return c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Conflict",
+				Message: "An offer of the application is already being accepted",
+				Code:    "OFFER_ACCEPTANCE_IN_PROGRESS",
+			})
+		case errors.Is(err, services.ErrOfferNotFound):
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Offer not found",
+				Code:    "OFFER_NOT_FOUND",
+			})
+		case errors.Is(err, services.ErrOfferNotAcceptable):
+			return c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+				Error:   "Unprocessable Entity",
+				Message: "Only approved offers that were not declined can be accepted",
+				Code:    "OFFER_NOT_ACCEPTABLE",
+			})
+		case errors.Is(err, services.ErrOfferDeclined):
+			return c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+				Error:   "Unprocessable Entity",
+				Message: "The bank declined the offer acceptance",
+				Code:    "OFFER_DECLINED",
+			})
+		case errors.Is(err, services.ErrBankUnavailable):
+			return c.JSON(http.StatusBadGateway, dto.ErrorResponse{
+				Error:   "Bad Gateway",
+				Message: "The bank could not be reached, please try again later",
+				Code:    "BANK_UNAVAILABLE",
+			})
+		case isNotFoundError(err):
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Application not found",
+				Code:    "APPLICATION_NOT_FOUND",
+			})
+		}
+
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to accept offer",
+			Code:    "OFFER_ACCEPTANCE_FAILED",
+		})
+	}
+
+	logger.Info("Offer accepted successfully")
+
+	return c.JSON(http.StatusOK, mappers.ToApplicationStatusResponseFromModel(application))
+}
+
+// parseRankBy reads the rankBy query parameter, defaulting to APR.
+func (h *ApplicationHandler) parseRankBy(c echo.Context) (dto.OfferRankBy, *dto.ErrorResponse) {
+	rankBy := c.QueryParam("rankBy")
+	if rankBy == "" {
+		return dto.OfferRankByAPR, nil
+	}
+
+	if err := h.validator.Var(rankBy, "oneof=apr monthly total"); err != nil {
+		return "", &dto.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "rankBy must be one of: apr monthly total",
+			Code:    "INVALID_RANK_BY",
+		}
+	}
+	return dto.OfferRankBy(rankBy), nil
+}
+
+func (h *ApplicationHandler) GetOfferSchedule(c echo.Context) error {
+	id := c.Param("id")
+	applicationID, err := uuid.Parse(id)
+	if err != nil {
+		h.logger.WithError(err).WithField("id", id).Error("Invalid application ID format")
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid application ID format",
+			Code:    "INVALID_APPLICATION_ID",
+		})
+	}
+
+	offerParam := c.Param("offerId")
+	offerID, err := uuid.Parse(offerParam)
+	if err != nil {
+		h.logger.WithError(err).WithField("offer_id", offerParam).Error("Invalid offer ID format")
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid offer ID format",
+			Code:    "INVALID_OFFER_ID",
+		})
+	}
+
+	logger := h.logger.WithFields(logrus.Fields{
+		"application_id": applicationID,
+		"offer_id":       offerID,
+	})
+
+	offer, schedule, err := h.applicationService.GetOfferSchedule(c.Request().Context(), applicationID, offerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOfferNotFound):
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Offer not found",
+				Code:    "OFFER_NOT_FOUND",
+			})
+		case errors.Is(err, services.ErrOfferNotVerifiable):
+			logger.WithError(err).Warn("Offer schedule unavailable")
+			return c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+				Error:   "Unprocessable Entity",
+				Message: "A schedule can only be built for approved offers with a monthly payment, number of payments and first repayment date",
+				Code:    "OFFER_SCHEDULE_UNAVAILABLE",
+			})
+		case isNotFoundError(err):
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Application not found",
+				Code:    "APPLICATION_NOT_FOUND",
+			})
+		}
+
+		logger.WithError(err).Error("Failed to build offer schedule")
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to build offer schedule",
+			Code:    "OFFER_SCHEDULE_FAILED",
+		})
+	}
+
+	return c.JSON(http.StatusOK, mappers.ToOfferScheduleFromModel(offer, schedule))
+}
@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/money"
+	"github.com/lielamurs/aggregator/internal/offermath"
+	"github.com/lielamurs/aggregator/internal/services"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eur(value string) money.Money {
+	return money.MustParse(value, money.EUR)
+}
+
+type fakeOfferService struct {
+	services.ApplicationService
+	application *models.Application
+	schedule    *offermath.Schedule
+	err         error
+}
+
+func (f *fakeOfferService) GetOfferSchedule(ctx context.Context, applicationID, offerID uuid.UUID) (*models.Offer, *offermath.Schedule, error) {
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	return &f.application.Offers[0], f.schedule, nil
+}
+
+func (f *fakeOfferService) AcceptOffer(ctx context.Context, applicationID, offerID uuid.UUID) (*models.Application, error) {
+	return f.application, f.err
+}
+
+func acceptOffer(t *testing.T, service services.ApplicationService, applicationID, offerID string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	handler := NewApplicationHandler(service, nil, config.StreamConfig{}, testPhoneConfig, config.WebhooksConfig{}, logger)
+	e := echo.New()
+	e.POST("/applications/:id/offers/:offerId/accept", handler.AcceptOffer)
+
+	req := httptest.NewRequest(http.MethodPost, "/applications/"+applicationID+"/offers/"+offerID+"/accept", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAcceptOffer(t *testing.T) {
+	applicationID := uuid.New()
+	offerID := uuid.New()
+
+	t.Run("accepted offer should return the application", func(t *testing.T) {
+		accepted := string(dto.OfferAcceptanceAccepted)
+		service := &fakeOfferService{application: &models.Application{
+			ID:     applicationID,
+			Status: string(dto.StatusAccepted),
+			Offers: []models.Offer{{ID: offerID, Status: string(dto.OfferStatusApproved), AcceptanceStatus: &accepted}},
+		}}
+
+		rec := acceptOffer(t, service, applicationID.String(), offerID.String())
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response dto.ApplicationStatusResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, dto.StatusAccepted, response.Status)
+		require.Len(t, response.Offers, 1)
+		assert.Equal(t, dto.OfferAcceptanceAccepted, *response.Offers[0].AcceptanceStatus)
+	})
+
+	tests := []struct {
+		name         string
+		offerID      string
+		err          error
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "invalid offer id",
+			offerID:      "not-a-uuid",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "INVALID_OFFER_ID",
+		},
+		{
+			name:         "application not found",
+			err:          fmt.Errorf("application with ID %s not found", applicationID),
+			expectedCode: http.StatusNotFound,
+			expectedBody: "APPLICATION_NOT_FOUND",
+		},
+		{
+			name:         "offer not found",
+			err:          services.ErrOfferNotFound,
+			expectedCode: http.StatusNotFound,
+			expectedBody: "OFFER_NOT_FOUND",
+		},
+		{
+			name:         "application not completed",
+			err:          &services.InvalidTransitionError{From: dto.StatusProcessing, To: dto.StatusAccepted},
+			expectedCode: http.StatusConflict,
+			expectedBody: "INVALID_STATUS_TRANSITION",
+		},
+		{
+			name:         "offer rejected by bank",
+			err:          services.ErrOfferNotAcceptable,
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: "OFFER_NOT_ACCEPTABLE",
+		},
+		{
+			name:         "acceptance declined by bank",
+			err:          fmt.Errorf("%w: HTTP 409: offer expired", services.ErrOfferDeclined),
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: "OFFER_DECLINED",
+		},
+		{
+			name:         "acceptance already in progress",
+			err:          services.ErrAcceptanceInProgress,
+			expectedCode: http.StatusConflict,
+			expectedBody: "OFFER_ACCEPTANCE_IN_PROGRESS",
+		},
+		{
+			name:         "bank unavailable",
+			err:          fmt.Errorf("%w: HTTP 503", services.ErrBankUnavailable),
+			expectedCode: http.StatusBadGateway,
+			expectedBody: "BANK_UNAVAILABLE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := tt.offerID
+			if id == "" {
+				id = offerID.String()
+			}
+
+			rec := acceptOffer(t, &fakeOfferService{err: tt.err}, applicationID.String(), id)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestGetApplicationStatus_RankBy(t *testing.T) {
+	applicationID := uuid.New()
+	lowAPR, highAPR := 8.0, 12.0
+	lowPayment, highPayment := eur("150"), eur("300")
+
+	service := &fakeApplicationService{}
+	service.set(&models.Application{
+		ID:     applicationID,
+		Status: string(dto.StatusCompleted),
+		Amount: eur("5000"),
+		Offers: []models.Offer{
+			{ID: uuid.New(), BankName: "FastBank", Status: string(dto.OfferStatusApproved), AnnualPercentageRate: &lowAPR, MonthlyPaymentAmount: &highPayment},
+			{ID: uuid.New(), BankName: "SolidBank", Status: string(dto.OfferStatusApproved), AnnualPercentageRate: &highAPR, MonthlyPaymentAmount: &lowPayment},
+		},
+	})
+	e := newLongPollTestServer(service, services.NewApplicationUpdateHub(nil, logrus.New()))
+
+	t.Run("offers should be ranked by APR by default", func(t *testing.T) {
+		rec := getStatus(e, applicationID, "", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response dto.ApplicationStatusResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.NotNil(t, response.Comparison)
+		assert.Equal(t, dto.OfferRankByAPR, response.Comparison.RankBy)
+		assert.Equal(t, "FastBank", response.Comparison.Ranked[0].BankName)
+		assert.True(t, response.Comparison.Ranked[0].Recommended)
+	})
+
+	t.Run("rankBy should select the ranking", func(t *testing.T) {
+		rec := getStatus(e, applicationID, "?rankBy=monthly", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response dto.ApplicationStatusResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, dto.OfferRankByMonthly, response.Comparison.RankBy)
+		assert.Equal(t, "SolidBank", response.Comparison.Ranked[0].BankName)
+	})
+
+	t.Run("unknown ranking should be rejected", func(t *testing.T) {
+		rec := getStatus(e, applicationID, "?rankBy=cheapest", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_RANK_BY")
+	})
+}
+
+func getOfferSchedule(t *testing.T, service services.ApplicationService, applicationID, offerID string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	handler := NewApplicationHandler(service, nil, config.StreamConfig{}, testPhoneConfig, config.WebhooksConfig{}, logger)
+	e := echo.New()
+	e.GET("/applications/:id/offers/:offerId/schedule", handler.GetOfferSchedule)
+
+	req := httptest.NewRequest(http.MethodGet, "/applications/"+applicationID+"/offers/"+offerID+"/schedule", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGetOfferSchedule(t *testing.T) {
+	applicationID := uuid.New()
+	offerID := uuid.New()
+
+	t.Run("schedule should be returned with the stated APR", func(t *testing.T) {
+		statedAPR := 9.0
+		service := &fakeOfferService{
+			application: &models.Application{
+				ID:     applicationID,
+				Offers: []models.Offer{{ID: offerID, BankName: "FastBank", AnnualPercentageRate: &statedAPR, Discrepancies: "APR_MISMATCH"}},
+			},
+			schedule: &offermath.Schedule{
+				Principal:            eur("1000"),
+				AnnualPercentageRate: 10,
+				TotalInterest:        eur("100"),
+				TotalRepayment:       eur("1100"),
+				Installments: []offermath.Installment{
+					{Number: 1, Date: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Payment: eur("1100"), Interest: eur("100"), Principal: eur("1000")},
+				},
+			},
+		}
+
+		rec := getOfferSchedule(t, service, applicationID.String(), offerID.String())
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response dto.OfferSchedule
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, offerID, response.OfferID)
+		assert.Equal(t, 10.0, response.AnnualPercentageRate)
+		assert.Equal(t, statedAPR, *response.StatedAnnualPercentageRate)
+		assert.Equal(t, []dto.OfferDiscrepancy{dto.OfferDiscrepancyAPR}, response.Discrepancies)
+		require.Len(t, response.Installments, 1)
+		assert.Equal(t, "2026-01-01", response.Installments[0].DueDate)
+	})
+
+	tests := []struct {
+		name         string
+		offerID      string
+		err          error
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "invalid offer id",
+			offerID:      "not-a-uuid",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "INVALID_OFFER_ID",
+		},
+		{
+			name:         "application not found",
+			err:          fmt.Errorf("application with ID %s not found", applicationID),
+			expectedCode: http.StatusNotFound,
+			expectedBody: "APPLICATION_NOT_FOUND",
+		},
+		{
+			name:         "offer not found",
+			err:          services.ErrOfferNotFound,
+			expectedCode: http.StatusNotFound,
+			expectedBody: "OFFER_NOT_FOUND",
+		},
+		{
+			name:         "offer without a schedule",
+			err:          fmt.Errorf("%w: offer is REJECTED", services.ErrOfferNotVerifiable),
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: "OFFER_SCHEDULE_UNAVAILABLE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := tt.offerID
+			if id == "" {
+				id = offerID.String()
+			}
+
+			rec := getOfferSchedule(t, &fakeOfferService{err: tt.err}, applicationID.String(), id)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.expectedBody)
+		})
+	}
+}
@@ -1,31 +1,62 @@
 package handlers
 
 import (
+	"net"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/services"
 	"github.com/sirupsen/logrus"
 )
 
-func SetupRouter(handler *ApplicationHandler, cfg *config.Config, logger *logrus.Logger) *echo.Echo {
+func SetupRouter(
+	handler *ApplicationHandler,
+	adminHandler *AdminHandler,
+	webhookHandler *WebhookHandler,
+	idempotencyService services.IdempotencyService,
+	cfg *config.Config,
+	logger *logrus.Logger,
+) *echo.Echo {
 	e := echo.New()
 
 	e.HideBanner = true
+	e.IPExtractor = ipExtractor(cfg.Server.TrustedProxies)
 	setupMiddleware(e, logger)
-	setupRoutes(e, handler)
+	setupRoutes(e, handler, adminHandler, webhookHandler, IdempotencyMiddleware(idempotencyService, logger), cfg.Server.AdminToken, cfg.Server.ClientTokens)
 
 	return e
 }
 
+// ipExtractor decides where c.RealIP comes from. X-Forwarded-For is set by
+// the client unless a proxy overwrites it, so it is only read when the
+// request arrives through one of the trusted proxies; velocity limits per IP
+// would otherwise be trivial to evade.
+func ipExtractor(trustedProxies []*net.IPNet) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range trustedProxies {
+		options = append(options, echo.TrustIPRange(proxy))
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
 func setupMiddleware(e *echo.Echo, logger *logrus.Logger) {
 	e.Use(middleware.Recover())
 
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"*"},
-		AllowMethods: []string{echo.GET, echo.POST, echo.PUT, echo.DELETE, echo.OPTIONS},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		AllowOrigins:  []string{"*"},
+		AllowMethods:  []string{echo.GET, echo.POST, echo.PUT, echo.DELETE, echo.OPTIONS},
+		AllowHeaders:  []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, HeaderIdempotencyKey, "If-None-Match"},
+		ExposeHeaders: []string{HeaderIdempotentReplayed, "ETag"},
 	}))
 
 	e.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{
@@ -34,16 +65,37 @@ func setupMiddleware(e *echo.Echo, logger *logrus.Logger) {
 	}))
 
 	e.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{
+		Skipper: isLongLivedRequest,
 		Timeout: 30 * time.Second,
 	}))
 }
 
-func setupRoutes(e *echo.Echo, handler *ApplicationHandler) {
+func setupRoutes(e *echo.Echo, handler *ApplicationHandler, adminHandler *AdminHandler, webhookHandler *WebhookHandler, idempotency echo.MiddlewareFunc, adminToken string, clientTokens map[string]string) {
 	e.GET("/health", handler.HealthCheck)
 
 	v1 := e.Group("/api/v1")
 
 	applications := v1.Group("/applications")
-	applications.POST("", handler.SubmitApplication)
+	applications.POST("", handler.SubmitApplication, ClientAuthMiddleware(clientTokens, false), idempotency)
 	applications.GET("/:id", handler.GetApplicationStatus)
+	applications.GET("/:id/stream", handler.StreamApplication)
+	applications.POST("/:id/cancel", handler.CancelApplication)
+	applications.POST("/:id/offers/:offerId/accept", handler.AcceptOffer)
+	applications.GET("/:id/offers/:offerId/schedule", handler.GetOfferSchedule)
+
+	if len(clientTokens) > 0 {
+		webhooks := v1.Group("/webhooks", ClientAuthMiddleware(clientTokens, true))
+		webhooks.POST("", webhookHandler.CreateSubscription)
+		webhooks.GET("", webhookHandler.ListSubscriptions)
+		webhooks.DELETE("/:id", webhookHandler.DeleteSubscription)
+	}
+
+	if adminToken != "" {
+		admin := v1.Group("/admin", AdminAuthMiddleware(adminToken))
+		admin.GET("/banks", adminHandler.GetBanks)
+		// The listing and histories span every applicant's personal data.
+		admin.GET("/applications", handler.ListApplications)
+		admin.GET("/applications/:id/events", handler.GetApplicationEvents)
+		admin.GET("/applications/:id/webhook-deliveries", webhookHandler.GetDeliveries)
+	}
 }
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPExtractor(t *testing.T) {
+	_, proxies, _ := net.ParseCIDR("10.0.0.0/8")
+
+	tests := []struct {
+		name           string
+		trustedProxies []*net.IPNet
+		remoteAddr     string
+		forwardedFor   string
+		expected       string
+	}{
+		{name: "forwarded header without trusted proxies", remoteAddr: "198.51.100.1:4000", forwardedFor: "203.0.113.7", expected: "198.51.100.1"},
+		{name: "forwarded header from a trusted proxy", trustedProxies: []*net.IPNet{proxies}, remoteAddr: "10.0.0.5:4000", forwardedFor: "203.0.113.7", expected: "203.0.113.7"},
+		{name: "spoofed hop behind a trusted proxy", trustedProxies: []*net.IPNet{proxies}, remoteAddr: "10.0.0.5:4000", forwardedFor: "192.0.2.1, 203.0.113.7", expected: "203.0.113.7"},
+		{name: "forwarded header from an untrusted address", trustedProxies: []*net.IPNet{proxies}, remoteAddr: "198.51.100.1:4000", forwardedFor: "203.0.113.7", expected: "198.51.100.1"},
+		{name: "private address that is not a trusted proxy", trustedProxies: []*net.IPNet{proxies}, remoteAddr: "192.168.1.1:4000", forwardedFor: "203.0.113.7", expected: "192.168.1.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/applications", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set(echo.HeaderXForwardedFor, tt.forwardedFor)
+
+			assert.Equal(t, tt.expected, ipExtractor(tt.trustedProxies)(req))
+		})
+	}
+}
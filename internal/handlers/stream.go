@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/mappers"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/services"
+)
+
+const (
+	streamEventStatus = "status"
+	streamEventOffer  = "offer"
+	streamEventEnd    = "end"
+)
+
+// StreamApplication pushes the application's status and offers as
+// Server-Sent Events until the banks are done with it. The current state is
+// sent first, followed by every change.
+func (h *ApplicationHandler) StreamApplication(c echo.Context) error {
+	id := c.Param("id")
+	applicationID, err := uuid.Parse(id)
+	if err != nil {
+		h.logger.WithError(err).WithField("id", id).Error("Invalid application ID format")
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid application ID format",
+			Code:    "INVALID_APPLICATION_ID",
+		})
+	}
+
+	ctx := c.Request().Context()
+
+	// Subscribe before reading so no change between the read and the
+	// subscription is missed.
+	updates, unsubscribe := h.updates.Subscribe(applicationID)
+	defer unsubscribe()
+
+	application, err := h.applicationService.GetApplicationStatus(ctx, applicationID)
+	if err != nil {
+		h.logger.WithError(err).WithField("application_id", applicationID).Error("Failed to get application status")
+
+		if isNotFoundError(err) {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Application not found",
+				Code:    "APPLICATION_NOT_FOUND",
+			})
+		}
+
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve application status",
+			Code:    "APPLICATION_RETRIEVAL_FAILED",
+		})
+	}
+
+	stream := newApplicationStream(c.Response())
+	stream.disableWriteDeadline()
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, "text/event-stream")
+	header.Set(echo.HeaderCacheControl, "no-cache")
+	header.Set(echo.HeaderConnection, "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	c.Response().WriteHeader(http.StatusOK)
+
+	logger := h.logger.WithField("application_id", applicationID)
+	logger.Debug("Application stream opened")
+	defer logger.Debug("Application stream closed")
+
+	heartbeat := time.NewTicker(time.Duration(max(h.streamConfig.HeartbeatSeconds, 1)) * time.Second)
+	defer heartbeat.Stop()
+	maxDuration := time.NewTimer(time.Duration(max(h.streamConfig.MaxDurationSeconds, 1)) * time.Second)
+	defer maxDuration.Stop()
+
+	for {
+		if err := stream.send(application); err != nil {
+			return nil
+		}
+
+		if services.IsProcessingFinished(dto.ApplicationStatus(application.Status)) {
+			stream.writeEvent(streamEventEnd, "", struct{}{})
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-maxDuration.C:
+			return nil
+		case <-h.updates.Done():
+			return nil
+		case <-heartbeat.C:
+			if err := stream.writeComment("keepalive"); err != nil {
+				return nil
+			}
+			// Re-reading on heartbeats covers notifications lost while the
+			// listener was reconnecting.
+		case <-updates:
+		}
+
+		application, err = h.applicationService.GetApplicationStatus(ctx, applicationID)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.WithError(err).Error("Failed to refresh streamed application")
+			}
+			return nil
+		}
+	}
+}
+
+// applicationStream writes application changes as Server-Sent Events,
+// remembering what was already sent so only changes are pushed.
+type applicationStream struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	status     dto.ApplicationStatus
+	sentOffers map[uuid.UUID]bool
+}
+
+func newApplicationStream(w http.ResponseWriter) *applicationStream {
+	return &applicationStream{
+		w:          w,
+		controller: http.NewResponseController(w),
+		sentOffers: make(map[uuid.UUID]bool),
+	}
+}
+
+// disableWriteDeadline lets the stream outlive the server's WriteTimeout.
+func (s *applicationStream) disableWriteDeadline() {
+	_ = s.controller.SetWriteDeadline(time.Time{})
+}
+
+// send pushes offers that were not sent yet and then the status if it
+// changed, so the final status follows the offers it completes.
+func (s *applicationStream) send(application *models.Application) error {
+	for _, offer := range application.Offers {
+		if s.sentOffers[offer.ID] {
+			continue
+		}
+		if err := s.writeEvent(streamEventOffer, offer.ID.String(), mappers.ToOfferFromModel(&offer)); err != nil {
+			return err
+		}
+		s.sentOffers[offer.ID] = true
+	}
+
+	status := dto.ApplicationStatus(application.Status)
+	if status == s.status {
+		return nil
+	}
+
+	err := s.writeEvent(streamEventStatus, "", dto.ApplicationResponse{
+		ID:     application.ID,
+		Status: status,
+	})
+	if err != nil {
+		return err
+	}
+	s.status = status
+	return nil
+}
+
+func (s *applicationStream) writeEvent(event, id string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if id != "" {
+		if _, err := fmt.Fprintf(s.w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	return s.controller.Flush()
+}
+
+func (s *applicationStream) writeComment(comment string) error {
+	if _, err := fmt.Fprintf(s.w, ": %s\n\n", comment); err != nil {
+		return err
+	}
+	return s.controller.Flush()
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/services"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeApplicationService struct {
+	services.ApplicationService
+	mu          sync.Mutex
+	application *models.Application
+}
+
+func (f *fakeApplicationService) GetApplicationStatus(ctx context.Context, applicationID uuid.UUID) (*models.Application, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.application == nil || f.application.ID != applicationID {
+		return nil, fmt.Errorf("application with ID %s not found", applicationID)
+	}
+	application := *f.application
+	return &application, nil
+}
+
+func (f *fakeApplicationService) set(application *models.Application) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.application = application
+}
+
+func newStreamTestServer(service services.ApplicationService, hub *services.ApplicationUpdateHub) *httptest.Server {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	handler := NewApplicationHandler(service, hub, config.StreamConfig{HeartbeatSeconds: 60, MaxDurationSeconds: 60}, testPhoneConfig, config.WebhooksConfig{}, logger)
+	e := echo.New()
+	e.GET("/applications/:id/stream", handler.StreamApplication)
+	return httptest.NewServer(e)
+}
+
+func readStreamEvent(t *testing.T, reader *bufio.Reader) (string, string) {
+	t.Helper()
+
+	var event, data string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return event, data
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestApplicationHandler_StreamApplication(t *testing.T) {
+	t.Run("changes should be pushed until processing finishes", func(t *testing.T) {
+		applicationID := uuid.New()
+		service := &fakeApplicationService{}
+		service.set(&models.Application{ID: applicationID, Status: "PROCESSING"})
+		hub := services.NewApplicationUpdateHub(nil, logrus.New())
+
+		server := newStreamTestServer(service, hub)
+		defer server.Close()
+
+		resp, err := http.Get(server.URL + "/applications/" + applicationID.String() + "/stream")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get(echo.HeaderContentType))
+
+		reader := bufio.NewReader(resp.Body)
+		event, data := readStreamEvent(t, reader)
+		assert.Equal(t, "status", event)
+		assert.Contains(t, data, `"status":"PROCESSING"`)
+
+		offerID := uuid.New()
+		service.set(&models.Application{
+			ID:     applicationID,
+			Status: "COMPLETED",
+			Offers: []models.Offer{{ID: offerID, BankName: "FastBank", Status: "PROCESSED", CreatedAt: time.Now()}},
+		})
+		hub.Notify(applicationID)
+
+		event, data = readStreamEvent(t, reader)
+		assert.Equal(t, "offer", event)
+		assert.Contains(t, data, offerID.String())
+
+		event, data = readStreamEvent(t, reader)
+		assert.Equal(t, "status", event)
+		assert.Contains(t, data, `"status":"COMPLETED"`)
+
+		event, _ = readStreamEvent(t, reader)
+		assert.Equal(t, "end", event)
+
+		_, err = reader.ReadString('\n')
+		assert.Error(t, err, "stream should be closed")
+	})
+
+	t.Run("unknown application should return 404 before streaming", func(t *testing.T) {
+		server := newStreamTestServer(&fakeApplicationService{}, services.NewApplicationUpdateHub(nil, logrus.New()))
+		defer server.Close()
+
+		resp, err := http.Get(server.URL + "/applications/" + uuid.NewString() + "/stream")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
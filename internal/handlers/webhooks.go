@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/mappers"
+	"github.com/lielamurs/aggregator/internal/safeurl"
+	"github.com/lielamurs/aggregator/internal/services"
+	"github.com/sirupsen/logrus"
+)
+
+// clientIDKey is the echo context key of the authenticated API client.
+const clientIDKey = "client_id"
+
+type WebhookHandler struct {
+	webhookService services.WebhookService
+	webhookURLs    safeurl.Policy
+	validator      *validator.Validate
+	logger         *logrus.Logger
+}
+
+func NewWebhookHandler(webhookService services.WebhookService, webhookConfig config.WebhooksConfig, logger *logrus.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		webhookURLs:    safeurl.Policy{AllowInsecure: webhookConfig.AllowInsecureURLs},
+		validator:      validator.New(),
+		logger:         logger,
+	}
+}
+
+func (h *WebhookHandler) CreateSubscription(c echo.Context) error {
+	clientID, errResponse := requireClientID(c)
+	if errResponse != nil {
+		return c.JSON(http.StatusUnauthorized, errResponse)
+	}
+
+	var req dto.WebhookSubscriptionRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to bind webhook subscription request")
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid request format",
+			Code:    "INVALID_REQUEST_FORMAT",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.WithError(err).Error("Webhook subscription validation failed")
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Validation Failed",
+			Message: extractValidationErrors(err),
+			Code:    "VALIDATION_FAILED",
+		})
+	}
+
+	if errResponse := checkWebhookURL(c.Request().Context(), h.webhookURLs, req.URL); errResponse != nil {
+		h.logger.WithField("url", req.URL).Warn("Webhook URL rejected")
+		return c.JSON(http.StatusBadRequest, errResponse)
+	}
+
+	subscription, err := h.webhookService.CreateSubscription(c.Request().Context(), clientID, &req)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to create webhook subscription",
+			Code:    "WEBHOOK_SUBSCRIPTION_FAILED",
+		})
+	}
+
+	response := mappers.ToWebhookSubscriptionFromModel(subscription)
+	response.Secret = subscription.Secret
+	return c.JSON(http.StatusCreated, response)
+}
+
+func (h *WebhookHandler) ListSubscriptions(c echo.Context) error {
+	clientID, errResponse := requireClientID(c)
+	if errResponse != nil {
+		return c.JSON(http.StatusUnauthorized, errResponse)
+	}
+
+	subscriptions, err := h.webhookService.ListSubscriptions(c.Request().Context(), clientID)
+	if err != nil {
+		h.logger.WithError(err).WithField("client_id", clientID).Error("Failed to list webhook subscriptions")
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve webhook subscriptions",
+			Code:    "WEBHOOK_SUBSCRIPTIONS_RETRIEVAL_FAILED",
+		})
+	}
+
+	return c.JSON(http.StatusOK, mappers.ToWebhookSubscriptionsResponseFromModels(subscriptions))
+}
+
+func (h *WebhookHandler) DeleteSubscription(c echo.Context) error {
+	clientID, errResponse := requireClientID(c)
+	if errResponse != nil {
+		return c.JSON(http.StatusUnauthorized, errResponse)
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid webhook subscription ID format",
+			Code:    "INVALID_WEBHOOK_SUBSCRIPTION_ID",
+		})
+	}
+
+	err = h.webhookService.DeleteSubscription(c.Request().Context(), clientID, id)
+	if errors.Is(err, services.ErrWebhookSubscriptionNotFound) {
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "Not Found",
+			Message: "Webhook subscription not found",
+			Code:    "WEBHOOK_SUBSCRIPTION_NOT_FOUND",
+		})
+	}
+	if err != nil {
+		h.logger.WithError(err).WithField("subscription_id", id).Error("Failed to delete webhook subscription")
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to delete webhook subscription",
+			Code:    "WEBHOOK_SUBSCRIPTION_DELETION_FAILED",
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) GetDeliveries(c echo.Context) error {
+	id := c.Param("id")
+	applicationID, err := uuid.Parse(id)
+	if err != nil {
+		h.logger.WithError(err).WithField("id", id).Error("Invalid application ID format")
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid application ID format",
+			Code:    "INVALID_APPLICATION_ID",
+		})
+	}
+
+	deliveries, err := h.webhookService.GetDeliveries(c.Request().Context(), applicationID)
+	if err != nil {
+		h.logger.WithError(err).WithField("application_id", applicationID).Error("Failed to get webhook deliveries")
+
+		if isNotFoundError(err) {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Application not found",
+				Code:    "APPLICATION_NOT_FOUND",
+			})
+		}
+
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve webhook deliveries",
+			Code:    "WEBHOOK_DELIVERIES_RETRIEVAL_FAILED",
+		})
+	}
+
+	return c.JSON(http.StatusOK, mappers.ToWebhookDeliveriesResponseFromModels(applicationID, deliveries))
+}
+
+// ClientAuthMiddleware identifies the API client by the bearer token in the
+// Authorization header, looking up its client ID in tokens. Unknown tokens
+// are rejected. Requests without a token are rejected when required and let
+// through anonymously otherwise.
+func ClientAuthMiddleware(tokens map[string]string, required bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			authorization := c.Request().Header.Get(echo.HeaderAuthorization)
+			if authorization == "" && !required {
+				return next(c)
+			}
+
+			var clientID string
+			if provided, found := strings.CutPrefix(authorization, "Bearer "); found {
+				clientID = lookupClientID(tokens, provided)
+			}
+			if clientID == "" {
+				return c.JSON(http.StatusUnauthorized, unauthorizedClientResponse())
+			}
+
+			c.Set(clientIDKey, clientID)
+			return next(c)
+		}
+	}
+}
+
+// lookupClientID compares provided with every token in constant time and
+// returns the matching client ID, or an empty string.
+func lookupClientID(tokens map[string]string, provided string) string {
+	var clientID string
+	for token, id := range tokens {
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1 {
+			clientID = id
+		}
+	}
+	return clientID
+}
+
+// clientIDFromContext returns the API client authenticated by
+// ClientAuthMiddleware, or an empty string for anonymous requests.
+func clientIDFromContext(c echo.Context) string {
+	clientID, _ := c.Get(clientIDKey).(string)
+	return clientID
+}
+
+func requireClientID(c echo.Context) (string, *dto.ErrorResponse) {
+	clientID := clientIDFromContext(c)
+	if clientID == "" {
+		response := unauthorizedClientResponse()
+		return "", &response
+	}
+	return clientID, nil
+}
+
+func unauthorizedClientResponse() dto.ErrorResponse {
+	return dto.ErrorResponse{
+		Error:   "Unauthorized",
+		Message: "A valid API client token is required",
+		Code:    "CLIENT_UNAUTHORIZED",
+	}
+}
+
+// checkWebhookURL returns the response rejecting a webhook URL that is not
+// https or points into a private network, or nil if the URL is allowed.
+func checkWebhookURL(ctx context.Context, urls safeurl.Policy, rawURL string) *dto.ErrorResponse {
+	if err := urls.Check(ctx, rawURL); err != nil {
+		return &dto.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Webhook " + err.Error(),
+			Code:    "INVALID_WEBHOOK_URL",
+		}
+	}
+	return nil
+}
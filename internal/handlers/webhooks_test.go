@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/services"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWebhookService struct {
+	services.WebhookService
+	clientID string
+	request  *dto.WebhookSubscriptionRequest
+}
+
+func (f *fakeWebhookService) CreateSubscription(ctx context.Context, clientID string, req *dto.WebhookSubscriptionRequest) (*models.WebhookSubscription, error) {
+	f.clientID = clientID
+	f.request = req
+	return &models.WebhookSubscription{
+		ID:         uuid.New(),
+		ClientID:   clientID,
+		URL:        req.URL,
+		Secret:     "whsec_generated",
+		EventTypes: "offer.received",
+	}, nil
+}
+
+// fakeResolver resolves hosts from a fixed table instead of DNS.
+type fakeResolver map[string]string
+
+func (r fakeResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	if addr, ok := r[host]; ok {
+		return []netip.Addr{netip.MustParseAddr(addr)}, nil
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return []netip.Addr{addr}, nil
+	}
+	return nil, fmt.Errorf("no such host %s", host)
+}
+
+var testResolver = fakeResolver{"client.example.com": "93.184.216.34", "intranet.example.com": "10.0.0.5"}
+
+var testClientTokens = map[string]string{"portal-token": "portal"}
+
+func TestClientAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		name             string
+		required         bool
+		authorization    string
+		expectedStatus   int
+		expectedClientID string
+	}{
+		{name: "valid token should identify the client", required: true, authorization: "Bearer portal-token", expectedStatus: http.StatusOK, expectedClientID: "portal"},
+		{name: "missing token should be rejected when required", required: true, expectedStatus: http.StatusUnauthorized},
+		{name: "missing token should be anonymous when optional", expectedStatus: http.StatusOK},
+		{name: "unknown token should be rejected", authorization: "Bearer other-token", expectedStatus: http.StatusUnauthorized},
+		{name: "token without the Bearer scheme should be rejected", authorization: "portal-token", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var clientID string
+			e := echo.New()
+			e.GET("/", func(c echo.Context) error {
+				clientID = clientIDFromContext(c)
+				return c.NoContent(http.StatusOK)
+			}, ClientAuthMiddleware(testClientTokens, tt.required))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			req.Header.Set("X-Client-ID", "someone-else")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedClientID, clientID)
+			if tt.expectedStatus == http.StatusUnauthorized {
+				assert.Contains(t, rec.Body.String(), "CLIENT_UNAUTHORIZED")
+			}
+		})
+	}
+}
+
+func TestWebhookHandler_CreateSubscription(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	tests := []struct {
+		name           string
+		token          string
+		body           string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "missing token should be rejected",
+			body:           `{"url":"https://client.example.com/hooks"}`,
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   "CLIENT_UNAUTHORIZED",
+		},
+		{
+			name:           "unknown token should be rejected",
+			token:          "other-token",
+			body:           `{"url":"https://client.example.com/hooks"}`,
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   "CLIENT_UNAUTHORIZED",
+		},
+		{
+			name:           "unknown event type should fail validation",
+			token:          "portal-token",
+			body:           `{"url":"https://client.example.com/hooks","events":["offer.deleted"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "VALIDATION_FAILED",
+		},
+		{
+			name:           "non-HTTP URL should fail validation",
+			token:          "portal-token",
+			body:           `{"url":"ftp://client.example.com/hooks"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "VALIDATION_FAILED",
+		},
+		{
+			name:           "http URL should be rejected",
+			token:          "portal-token",
+			body:           `{"url":"http://client.example.com/hooks"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_WEBHOOK_URL",
+		},
+		{
+			name:           "host resolving to a private address should be rejected",
+			token:          "portal-token",
+			body:           `{"url":"https://intranet.example.com/hooks"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_WEBHOOK_URL",
+		},
+		{
+			name:           "cloud metadata address should be rejected",
+			token:          "portal-token",
+			body:           `{"url":"https://169.254.169.254/latest/meta-data"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_WEBHOOK_URL",
+		},
+		{
+			name:           "valid subscription should be created",
+			token:          "portal-token",
+			body:           `{"url":"https://client.example.com/hooks","events":["offer.received"]}`,
+			expectedStatus: http.StatusCreated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeWebhookService{}
+			e := echo.New()
+			handler := NewWebhookHandler(service, config.WebhooksConfig{}, logger)
+			handler.webhookURLs.Resolver = testResolver
+			e.POST("/webhooks", handler.CreateSubscription, ClientAuthMiddleware(testClientTokens, true))
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if tt.token != "" {
+				req.Header.Set(echo.HeaderAuthorization, "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedCode != "" {
+				var response dto.ErrorResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedCode, response.Code)
+				assert.Nil(t, service.request)
+				return
+			}
+
+			var response dto.WebhookSubscription
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, "portal", service.clientID)
+			assert.Equal(t, "https://client.example.com/hooks", response.URL)
+			assert.Equal(t, "whsec_generated", response.Secret)
+			assert.Equal(t, []dto.WebhookEventType{dto.WebhookEventOfferReceived}, response.Events)
+		})
+	}
+}
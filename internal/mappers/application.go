@@ -1,11 +1,13 @@
 package mappers
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/lielamurs/aggregator/internal/dto"
 	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/money"
 )
 
 func ToApplicationModel(customerApp *dto.CustomerApplication) *models.Application {
@@ -13,19 +15,44 @@ func ToApplicationModel(customerApp *dto.CustomerApplication) *models.Applicatio
 		return nil
 	}
 
+	currency := customerApp.CustomerData.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+
 	app := &models.Application{
-		ID:              customerApp.ID,
-		Phone:           customerApp.CustomerData.Phone,
-		Email:           customerApp.CustomerData.Email,
-		MonthlyIncome:   customerApp.CustomerData.MonthlyIncome,
-		MonthlyExpenses: customerApp.CustomerData.MonthlyExpenses,
-		MaritalStatus:   customerApp.CustomerData.MaritalStatus,
-		AgreeToBeScored: customerApp.CustomerData.AgreeToBeScored,
-		Amount:          customerApp.CustomerData.Amount,
-		Dependents:      customerApp.CustomerData.Dependents,
-		Status:          string(customerApp.Status),
-		CreatedAt:       customerApp.CreatedAt,
-		UpdatedAt:       customerApp.UpdatedAt,
+		ID:                  customerApp.ID,
+		Phone:               customerApp.CustomerData.Phone,
+		Email:               customerApp.CustomerData.Email,
+		MonthlyIncome:       customerApp.CustomerData.MonthlyIncome.WithCurrency(currency),
+		MonthlyExpenses:     customerApp.CustomerData.MonthlyExpenses.WithCurrency(currency),
+		MaritalStatus:       customerApp.CustomerData.MaritalStatus,
+		AgreeToBeScored:     customerApp.CustomerData.AgreeToBeScored,
+		Amount:              customerApp.CustomerData.Amount.WithCurrency(currency),
+		Dependents:          customerApp.CustomerData.Dependents,
+		Status:              string(customerApp.Status),
+		CreatedAt:           customerApp.CreatedAt,
+		UpdatedAt:           customerApp.UpdatedAt,
+		TermMonths:          customerApp.CustomerData.TermMonths,
+		Currency:            string(currency),
+		PreferredPaymentDay: customerApp.CustomerData.PreferredPaymentDay,
+	}
+
+	if purpose := customerApp.CustomerData.Purpose; purpose != "" {
+		app.Purpose = (*string)(&purpose)
+	}
+
+	if customerApp.ClientID != "" {
+		app.ClientID = &customerApp.ClientID
+	}
+
+	if customerApp.ClientIP != "" {
+		app.ClientIP = &customerApp.ClientIP
+	}
+
+	if callback := customerApp.CustomerData.Callback; callback != nil {
+		app.CallbackURL = &callback.URL
+		app.CallbackSecret = &callback.Secret
 	}
 
 	if len(customerApp.Offers) > 0 {
@@ -71,10 +98,24 @@ func ToApplicationStatusResponseFromModel(application *models.Application) *dto.
 	}
 
 	response := &dto.ApplicationStatusResponse{
-		ID:        application.ID,
-		Status:    dto.ApplicationStatus(application.Status),
-		CreatedAt: application.CreatedAt,
-		UpdatedAt: application.UpdatedAt,
+		ID:                  application.ID,
+		Status:              dto.ApplicationStatus(application.Status),
+		Currency:            ApplicationCurrency(application),
+		TermMonths:          application.TermMonths,
+		PreferredPaymentDay: application.PreferredPaymentDay,
+		CreatedAt:           application.CreatedAt,
+		UpdatedAt:           application.UpdatedAt,
+	}
+
+	if application.Purpose != nil {
+		response.Purpose = dto.LoanPurpose(*application.Purpose)
+	}
+
+	if application.DisposableIncome != nil && application.SubsistenceMinimum != nil {
+		response.Affordability = &dto.Affordability{
+			DisposableIncome:   *application.DisposableIncome,
+			SubsistenceMinimum: *application.SubsistenceMinimum,
+		}
 	}
 
 	if len(application.Offers) > 0 {
@@ -84,6 +125,7 @@ func ToApplicationStatusResponseFromModel(application *models.Application) *dto.
 				response.Offers[i] = *offerDTO
 			}
 		}
+		response.Comparison = ToOfferComparisonFromModels(application.Offers, application.Amount, dto.OfferRankByAPR)
 	}
 
 	if len(application.BankSubmissions) > 0 {
@@ -97,3 +139,67 @@ func ToApplicationStatusResponseFromModel(application *models.Application) *dto.
 
 	return response
 }
+
+func ToApplicationRequestFromModel(application *models.Application) dto.ApplicationRequest {
+	currency := ApplicationCurrency(application)
+	req := dto.ApplicationRequest{
+		Phone:               application.Phone,
+		Email:               application.Email,
+		MonthlyIncome:       application.MonthlyIncome.WithCurrency(currency),
+		MonthlyExpenses:     application.MonthlyExpenses.WithCurrency(currency),
+		MaritalStatus:       application.MaritalStatus,
+		AgreeToBeScored:     application.AgreeToBeScored,
+		Amount:              application.Amount.WithCurrency(currency),
+		Dependents:          application.Dependents,
+		TermMonths:          application.TermMonths,
+		Currency:            currency,
+		PreferredPaymentDay: application.PreferredPaymentDay,
+	}
+
+	if application.Purpose != nil {
+		req.Purpose = dto.LoanPurpose(*application.Purpose)
+	}
+
+	return req
+}
+
+// ApplicationCurrency returns the currency of the application's amounts.
+// Applications stored before currencies were recorded are in the default
+// currency.
+func ApplicationCurrency(application *models.Application) money.Currency {
+	if application.Currency == "" {
+		return money.DefaultCurrency
+	}
+	return money.Currency(application.Currency)
+}
+
+// LoanFields returns the optional loan parameters set on the request. The
+// default currency is left out, as every bank assumes it.
+func LoanFields(req dto.ApplicationRequest) []string {
+	var fields []string
+	if req.TermMonths != nil {
+		fields = append(fields, dto.LoanFieldTermMonths)
+	}
+	if req.Purpose != "" {
+		fields = append(fields, dto.LoanFieldPurpose)
+	}
+	if req.Currency != "" && req.Currency != money.DefaultCurrency {
+		fields = append(fields, dto.LoanFieldCurrency)
+	}
+	if req.PreferredPaymentDay != nil {
+		fields = append(fields, dto.LoanFieldPreferredPaymentDay)
+	}
+	return fields
+}
+
+// DroppedLoanFields returns the loan parameters set on the request that a
+// bank taking only the supported ones cannot receive.
+func DroppedLoanFields(req dto.ApplicationRequest, supported ...string) []string {
+	var dropped []string
+	for _, field := range LoanFields(req) {
+		if !slices.Contains(supported, field) {
+			dropped = append(dropped, field)
+		}
+	}
+	return dropped
+}
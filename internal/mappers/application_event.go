@@ -0,0 +1,51 @@
+package mappers
+
+import (
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+)
+
+func ToApplicationEventFromModel(event *models.ApplicationEvent) *dto.ApplicationEvent {
+	if event == nil {
+		return nil
+	}
+
+	var bankName, oldStatus, reason string
+	if event.BankName != nil {
+		bankName = *event.BankName
+	}
+	if event.OldStatus != nil {
+		oldStatus = *event.OldStatus
+	}
+	if event.Reason != nil {
+		reason = *event.Reason
+	}
+
+	return &dto.ApplicationEvent{
+		ID:           event.ID,
+		EntityType:   dto.EventEntityType(event.EntityType),
+		SubmissionID: event.SubmissionID,
+		BankName:     bankName,
+		OldStatus:    oldStatus,
+		NewStatus:    event.NewStatus,
+		Actor:        dto.EventActor(event.Actor),
+		Reason:       reason,
+		CreatedAt:    event.CreatedAt,
+	}
+}
+
+func ToApplicationEventsResponseFromModels(applicationID uuid.UUID, events []models.ApplicationEvent) *dto.ApplicationEventsResponse {
+	response := &dto.ApplicationEventsResponse{
+		ApplicationID: applicationID,
+		Events:        make([]dto.ApplicationEvent, 0, len(events)),
+	}
+
+	for _, event := range events {
+		if mapped := ToApplicationEventFromModel(&event); mapped != nil {
+			response.Events = append(response.Events, *mapped)
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,104 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToApplicationEventFromModel(t *testing.T) {
+	now := time.Now()
+	eventID := uuid.New()
+	submissionID := uuid.New()
+
+	tests := []struct {
+		name     string
+		input    *models.ApplicationEvent
+		expected *dto.ApplicationEvent
+	}{
+		{
+			name:     "nil input should return nil",
+			input:    nil,
+			expected: nil,
+		},
+		{
+			name: "bank submission event should map correctly",
+			input: &models.ApplicationEvent{
+				ID:           eventID,
+				EntityType:   "BANK_SUBMISSION",
+				SubmissionID: &submissionID,
+				BankName:     &[]string{"SolidBank"}[0],
+				OldStatus:    &[]string{"DRAFT"}[0],
+				NewStatus:    "FAILED",
+				Actor:        "submission_processor",
+				Reason:       &[]string{"HTTP 500"}[0],
+				CreatedAt:    now,
+			},
+			expected: &dto.ApplicationEvent{
+				ID:           eventID,
+				EntityType:   dto.EventEntityBankSubmission,
+				SubmissionID: &submissionID,
+				BankName:     "SolidBank",
+				OldStatus:    "DRAFT",
+				NewStatus:    "FAILED",
+				Actor:        dto.EventActorSubmissionProcessor,
+				Reason:       "HTTP 500",
+				CreatedAt:    now,
+			},
+		},
+		{
+			name: "application creation event should have empty optional fields",
+			input: &models.ApplicationEvent{
+				ID:         eventID,
+				EntityType: "APPLICATION",
+				NewStatus:  "PENDING",
+				Actor:      "customer",
+				CreatedAt:  now,
+			},
+			expected: &dto.ApplicationEvent{
+				ID:         eventID,
+				EntityType: dto.EventEntityApplication,
+				NewStatus:  "PENDING",
+				Actor:      dto.EventActorCustomer,
+				CreatedAt:  now,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ToApplicationEventFromModel(tt.input)
+
+			if tt.expected == nil {
+				assert.Nil(t, result)
+				return
+			}
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestToApplicationEventsResponseFromModels(t *testing.T) {
+	applicationID := uuid.New()
+
+	response := ToApplicationEventsResponseFromModels(applicationID, nil)
+	require.NotNil(t, response)
+	assert.Equal(t, applicationID, response.ApplicationID)
+	assert.NotNil(t, response.Events)
+	assert.Empty(t, response.Events)
+
+	response = ToApplicationEventsResponseFromModels(applicationID, []models.ApplicationEvent{
+		{NewStatus: "PENDING"},
+		{NewStatus: "PROCESSING"},
+	})
+	require.Len(t, response.Events, 2)
+	assert.Equal(t, "PENDING", response.Events[0].NewStatus)
+	assert.Equal(t, "PROCESSING", response.Events[1].NewStatus)
+}
@@ -0,0 +1,72 @@
+package mappers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+)
+
+func ToApplicationSummaryFromModel(application *models.Application) *dto.ApplicationSummary {
+	if application == nil {
+		return nil
+	}
+
+	summary := &dto.ApplicationSummary{
+		ID:              application.ID,
+		Status:          dto.ApplicationStatus(application.Status),
+		Email:           application.Email,
+		Phone:           application.Phone,
+		Amount:          application.Amount,
+		BankSubmissions: make([]dto.BankSubmission, 0, len(application.BankSubmissions)),
+		CreatedAt:       application.CreatedAt,
+		UpdatedAt:       application.UpdatedAt,
+	}
+
+	for _, submission := range application.BankSubmissions {
+		if submissionDTO := ToBankSubmissionFromModel(&submission); submissionDTO != nil {
+			summary.BankSubmissions = append(summary.BankSubmissions, *submissionDTO)
+		}
+	}
+
+	return summary
+}
+
+func ToApplicationListResponseFromModels(applications []models.Application, nextCursor *dto.ApplicationCursor) *dto.ApplicationListResponse {
+	response := &dto.ApplicationListResponse{
+		Items: make([]dto.ApplicationSummary, 0, len(applications)),
+	}
+
+	for _, application := range applications {
+		if summary := ToApplicationSummaryFromModel(&application); summary != nil {
+			response.Items = append(response.Items, *summary)
+		}
+	}
+
+	if nextCursor != nil {
+		response.NextCursor = EncodeApplicationCursor(*nextCursor)
+	}
+
+	return response
+}
+
+func EncodeApplicationCursor(cursor dto.ApplicationCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeApplicationCursor(encoded string) (*dto.ApplicationCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor encoding: %w", err)
+	}
+
+	var cursor dto.ApplicationCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	return &cursor, nil
+}
@@ -0,0 +1,94 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplicationCursor(t *testing.T) {
+	t.Run("encoded cursor should decode to the same position", func(t *testing.T) {
+		cursor := dto.ApplicationCursor{
+			CreatedAt: time.Date(2025, 7, 6, 12, 30, 0, 123456000, time.UTC),
+			ID:        uuid.New(),
+		}
+
+		decoded, err := DecodeApplicationCursor(EncodeApplicationCursor(cursor))
+
+		require.NoError(t, err)
+		assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+		assert.Equal(t, cursor.ID, decoded.ID)
+	})
+
+	invalid := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "not a cursor!"},
+		{name: "not JSON", cursor: "bm90LWpzb24"},
+		{name: "wrong field types", cursor: "eyJpZCI6MX0"},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name+" should fail to decode", func(t *testing.T) {
+			_, err := DecodeApplicationCursor(tt.cursor)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestToApplicationListResponseFromModels(t *testing.T) {
+	now := time.Now()
+	appID := uuid.New()
+	submissionID := uuid.New()
+
+	applications := []models.Application{
+		{
+			ID:     appID,
+			Email:  "john@example.com",
+			Phone:  "+37120000000",
+			Amount: eur("5000"),
+			Status: "PROCESSING",
+			BankSubmissions: []models.BankSubmission{
+				{ID: submissionID, BankName: "SolidBank", Status: "DRAFT"},
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+	}
+
+	t.Run("last page should have no cursor", func(t *testing.T) {
+		response := ToApplicationListResponseFromModels(applications, nil)
+
+		require.Len(t, response.Items, 1)
+		item := response.Items[0]
+		assert.Equal(t, appID, item.ID)
+		assert.Equal(t, dto.StatusProcessing, item.Status)
+		assert.Equal(t, "john@example.com", item.Email)
+		assert.Equal(t, eur("5000"), item.Amount)
+		require.Len(t, item.BankSubmissions, 1)
+		assert.Equal(t, submissionID, item.BankSubmissions[0].ID)
+		assert.Equal(t, dto.SubmissionStatusDraft, item.BankSubmissions[0].Status)
+		assert.Empty(t, response.NextCursor)
+	})
+
+	t.Run("next cursor should be encoded", func(t *testing.T) {
+		cursor := dto.ApplicationCursor{CreatedAt: now, ID: appID}
+
+		response := ToApplicationListResponseFromModels(applications, &cursor)
+
+		assert.Equal(t, EncodeApplicationCursor(cursor), response.NextCursor)
+	})
+
+	t.Run("empty page should have empty items", func(t *testing.T) {
+		response := ToApplicationListResponseFromModels(nil, nil)
+
+		assert.NotNil(t, response.Items)
+		assert.Empty(t, response.Items)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/lielamurs/aggregator/internal/dto"
 	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -34,11 +35,11 @@ func TestToApplicationModel(t *testing.T) {
 				CustomerData: dto.ApplicationRequest{
 					Phone:           "+1234567890",
 					Email:           "test@example.com",
-					MonthlyIncome:   5000.0,
-					MonthlyExpenses: 2000.0,
+					MonthlyIncome:   eur("5000.0"),
+					MonthlyExpenses: eur("2000.0"),
 					MaritalStatus:   "single",
 					AgreeToBeScored: true,
-					Amount:          10000.0,
+					Amount:          eur("10000.0"),
 					Dependents:      2,
 				},
 				Status: dto.StatusPending,
@@ -46,8 +47,8 @@ func TestToApplicationModel(t *testing.T) {
 					{
 						ID:                   offerID,
 						BankName:             "TestBank",
-						MonthlyPaymentAmount: &[]float64{500.0}[0],
-						TotalRepaymentAmount: &[]float64{6000.0}[0],
+						MonthlyPaymentAmount: &[]money.Money{eur("500.0")}[0],
+						TotalRepaymentAmount: &[]money.Money{eur("6000.0")}[0],
 						NumberOfPayments:     &[]int{12}[0],
 						AnnualPercentageRate: &[]float64{12.5}[0],
 						FirstRepaymentDate:   &[]string{"2024-01-01"}[0],
@@ -75,13 +76,14 @@ func TestToApplicationModel(t *testing.T) {
 				ID:              customerAppID,
 				Phone:           "+1234567890",
 				Email:           "test@example.com",
-				MonthlyIncome:   5000.0,
-				MonthlyExpenses: 2000.0,
+				MonthlyIncome:   eur("5000.0"),
+				MonthlyExpenses: eur("2000.0"),
 				MaritalStatus:   "single",
 				AgreeToBeScored: true,
-				Amount:          10000.0,
+				Amount:          eur("10000.0"),
 				Dependents:      2,
 				Status:          "PENDING",
+				Currency:        "EUR",
 				CreatedAt:       now,
 				UpdatedAt:       now,
 			},
@@ -93,11 +95,11 @@ func TestToApplicationModel(t *testing.T) {
 				CustomerData: dto.ApplicationRequest{
 					Phone:           "+1234567890",
 					Email:           "test@example.com",
-					MonthlyIncome:   3000.0,
-					MonthlyExpenses: 1500.0,
+					MonthlyIncome:   eur("3000.0"),
+					MonthlyExpenses: eur("1500.0"),
 					MaritalStatus:   "married",
 					AgreeToBeScored: false,
-					Amount:          5000.0,
+					Amount:          eur("5000.0"),
 					Dependents:      1,
 				},
 				Status:          dto.StatusCompleted,
@@ -110,17 +112,99 @@ func TestToApplicationModel(t *testing.T) {
 				ID:              customerAppID,
 				Phone:           "+1234567890",
 				Email:           "test@example.com",
-				MonthlyIncome:   3000.0,
-				MonthlyExpenses: 1500.0,
+				MonthlyIncome:   eur("3000.0"),
+				MonthlyExpenses: eur("1500.0"),
 				MaritalStatus:   "married",
 				AgreeToBeScored: false,
-				Amount:          5000.0,
+				Amount:          eur("5000.0"),
 				Dependents:      1,
 				Status:          "COMPLETED",
+				Currency:        "EUR",
 				CreatedAt:       now,
 				UpdatedAt:       now,
 			},
 		},
+		{
+			name: "client ID and callback should map correctly",
+			input: &dto.CustomerApplication{
+				ID: customerAppID,
+				CustomerData: dto.ApplicationRequest{
+					Phone:           "+1234567890",
+					Email:           "test@example.com",
+					MonthlyIncome:   eur("3000.0"),
+					MonthlyExpenses: eur("1500.0"),
+					MaritalStatus:   "married",
+					AgreeToBeScored: true,
+					Amount:          eur("5000.0"),
+					Callback: &dto.WebhookCallback{
+						URL:    "https://client.example.com/hooks",
+						Secret: "0123456789abcdef",
+					},
+				},
+				ClientID:  "partner-portal",
+				ClientIP:  "203.0.113.7",
+				Status:    dto.StatusPending,
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			expected: &models.Application{
+				ID:              customerAppID,
+				Phone:           "+1234567890",
+				Email:           "test@example.com",
+				MonthlyIncome:   eur("3000.0"),
+				MonthlyExpenses: eur("1500.0"),
+				MaritalStatus:   "married",
+				AgreeToBeScored: true,
+				Amount:          eur("5000.0"),
+				Status:          "PENDING",
+				Currency:        "EUR",
+				ClientID:        &[]string{"partner-portal"}[0],
+				ClientIP:        &[]string{"203.0.113.7"}[0],
+				CallbackURL:     &[]string{"https://client.example.com/hooks"}[0],
+				CallbackSecret:  &[]string{"0123456789abcdef"}[0],
+				CreatedAt:       now,
+				UpdatedAt:       now,
+			},
+		},
+		{
+			name: "loan parameters should map correctly",
+			input: &dto.CustomerApplication{
+				ID: customerAppID,
+				CustomerData: dto.ApplicationRequest{
+					Phone:               "+1234567890",
+					Email:               "test@example.com",
+					MonthlyIncome:       eur("3000.0"),
+					MonthlyExpenses:     eur("1500.0"),
+					MaritalStatus:       "married",
+					AgreeToBeScored:     true,
+					Amount:              eur("5000.0"),
+					TermMonths:          &[]int{24}[0],
+					Purpose:             dto.LoanPurposeCar,
+					Currency:            money.EUR,
+					PreferredPaymentDay: &[]int{15}[0],
+				},
+				Status:    dto.StatusPending,
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			expected: &models.Application{
+				ID:                  customerAppID,
+				Phone:               "+1234567890",
+				Email:               "test@example.com",
+				MonthlyIncome:       eur("3000.0"),
+				MonthlyExpenses:     eur("1500.0"),
+				MaritalStatus:       "married",
+				AgreeToBeScored:     true,
+				Amount:              eur("5000.0"),
+				Status:              "PENDING",
+				TermMonths:          &[]int{24}[0],
+				Purpose:             &[]string{"CAR"}[0],
+				Currency:            "EUR",
+				PreferredPaymentDay: &[]int{15}[0],
+				CreatedAt:           now,
+				UpdatedAt:           now,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -142,6 +226,14 @@ func TestToApplicationModel(t *testing.T) {
 			assert.Equal(t, tt.expected.Amount, result.Amount)
 			assert.Equal(t, tt.expected.Dependents, result.Dependents)
 			assert.Equal(t, tt.expected.Status, result.Status)
+			assert.Equal(t, tt.expected.ClientID, result.ClientID)
+			assert.Equal(t, tt.expected.ClientIP, result.ClientIP)
+			assert.Equal(t, tt.expected.CallbackURL, result.CallbackURL)
+			assert.Equal(t, tt.expected.CallbackSecret, result.CallbackSecret)
+			assert.Equal(t, tt.expected.TermMonths, result.TermMonths)
+			assert.Equal(t, tt.expected.Purpose, result.Purpose)
+			assert.Equal(t, tt.expected.Currency, result.Currency)
+			assert.Equal(t, tt.expected.PreferredPaymentDay, result.PreferredPaymentDay)
 			assert.Equal(t, tt.expected.CreatedAt, result.CreatedAt)
 			assert.Equal(t, tt.expected.UpdatedAt, result.UpdatedAt)
 		})
@@ -162,11 +254,11 @@ func TestToCustomerApplicationFromRequest(t *testing.T) {
 			input: &dto.ApplicationRequest{
 				Phone:           "+1234567890",
 				Email:           "test@example.com",
-				MonthlyIncome:   5000.0,
-				MonthlyExpenses: 2000.0,
+				MonthlyIncome:   eur("5000.0"),
+				MonthlyExpenses: eur("2000.0"),
 				MaritalStatus:   "single",
 				AgreeToBeScored: true,
-				Amount:          10000.0,
+				Amount:          eur("10000.0"),
 				Dependents:      2,
 			},
 		},
@@ -175,11 +267,11 @@ func TestToCustomerApplicationFromRequest(t *testing.T) {
 			input: &dto.ApplicationRequest{
 				Phone:           "+0000000000",
 				Email:           "min@example.com",
-				MonthlyIncome:   1000.0,
-				MonthlyExpenses: 500.0,
+				MonthlyIncome:   eur("1000.0"),
+				MonthlyExpenses: eur("500.0"),
 				MaritalStatus:   "married",
 				AgreeToBeScored: false,
-				Amount:          1000.0,
+				Amount:          eur("1000.0"),
 				Dependents:      0,
 			},
 		},
@@ -228,19 +320,19 @@ func TestToApplicationStatusResponseFromModel(t *testing.T) {
 				ID:              appID,
 				Phone:           "+1234567890",
 				Email:           "test@example.com",
-				MonthlyIncome:   5000.0,
-				MonthlyExpenses: 2000.0,
+				MonthlyIncome:   eur("5000.0"),
+				MonthlyExpenses: eur("2000.0"),
 				MaritalStatus:   "single",
 				AgreeToBeScored: true,
-				Amount:          10000.0,
+				Amount:          eur("10000.0"),
 				Dependents:      2,
 				Status:          "PENDING",
 				Offers: []models.Offer{
 					{
 						ID:                   offerID,
 						BankName:             "TestBank",
-						MonthlyPaymentAmount: &[]float64{500.0}[0],
-						TotalRepaymentAmount: &[]float64{6000.0}[0],
+						MonthlyPaymentAmount: &[]money.Money{eur("500.0")}[0],
+						TotalRepaymentAmount: &[]money.Money{eur("6000.0")}[0],
 						NumberOfPayments:     &[]int{12}[0],
 						AnnualPercentageRate: &[]float64{12.5}[0],
 						FirstRepaymentDate:   &[]string{"2024-01-01"}[0],
@@ -267,6 +359,7 @@ func TestToApplicationStatusResponseFromModel(t *testing.T) {
 			expected: &dto.ApplicationStatusResponse{
 				ID:        appID,
 				Status:    dto.StatusPending,
+				Currency:  money.EUR,
 				CreatedAt: now,
 				UpdatedAt: now,
 			},
@@ -277,11 +370,11 @@ func TestToApplicationStatusResponseFromModel(t *testing.T) {
 				ID:              appID,
 				Phone:           "+1234567890",
 				Email:           "test@example.com",
-				MonthlyIncome:   3000.0,
-				MonthlyExpenses: 1500.0,
+				MonthlyIncome:   eur("3000.0"),
+				MonthlyExpenses: eur("1500.0"),
 				MaritalStatus:   "married",
 				AgreeToBeScored: false,
-				Amount:          5000.0,
+				Amount:          eur("5000.0"),
 				Dependents:      1,
 				Status:          "COMPLETED",
 				Offers:          []models.Offer{},
@@ -292,6 +385,54 @@ func TestToApplicationStatusResponseFromModel(t *testing.T) {
 			expected: &dto.ApplicationStatusResponse{
 				ID:        appID,
 				Status:    dto.StatusCompleted,
+				Currency:  money.EUR,
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+		{
+			name: "loan parameters should be echoed",
+			input: &models.Application{
+				ID:                  appID,
+				Amount:              eur("5000.0"),
+				Status:              "PENDING",
+				TermMonths:          &[]int{36}[0],
+				Purpose:             &[]string{"EDUCATION"}[0],
+				Currency:            "EUR",
+				PreferredPaymentDay: &[]int{5}[0],
+				CreatedAt:           now,
+				UpdatedAt:           now,
+			},
+			expected: &dto.ApplicationStatusResponse{
+				ID:                  appID,
+				Status:              dto.StatusPending,
+				Currency:            money.EUR,
+				TermMonths:          &[]int{36}[0],
+				Purpose:             dto.LoanPurposeEducation,
+				PreferredPaymentDay: &[]int{5}[0],
+				CreatedAt:           now,
+				UpdatedAt:           now,
+			},
+		},
+		{
+			name: "affordability should be returned when assessed",
+			input: &models.Application{
+				ID:                 appID,
+				Amount:             eur("5000.0"),
+				Status:             "PENDING",
+				DisposableIncome:   &[]money.Money{eur("2100")}[0],
+				SubsistenceMinimum: &[]money.Money{eur("750")}[0],
+				CreatedAt:          now,
+				UpdatedAt:          now,
+			},
+			expected: &dto.ApplicationStatusResponse{
+				ID:       appID,
+				Status:   dto.StatusPending,
+				Currency: money.EUR,
+				Affordability: &dto.Affordability{
+					DisposableIncome:   eur("2100"),
+					SubsistenceMinimum: eur("750"),
+				},
 				CreatedAt: now,
 				UpdatedAt: now,
 			},
@@ -310,6 +451,11 @@ func TestToApplicationStatusResponseFromModel(t *testing.T) {
 			require.NotNil(t, result)
 			assert.Equal(t, tt.expected.ID, result.ID)
 			assert.Equal(t, tt.expected.Status, result.Status)
+			assert.Equal(t, tt.expected.Currency, result.Currency)
+			assert.Equal(t, tt.expected.TermMonths, result.TermMonths)
+			assert.Equal(t, tt.expected.Purpose, result.Purpose)
+			assert.Equal(t, tt.expected.PreferredPaymentDay, result.PreferredPaymentDay)
+			assert.Equal(t, tt.expected.Affordability, result.Affordability)
 			assert.Equal(t, tt.expected.CreatedAt, result.CreatedAt)
 			assert.Equal(t, tt.expected.UpdatedAt, result.UpdatedAt)
 		})
@@ -321,11 +467,11 @@ func TestToCustomerApplicationFromRequest_EdgeCases(t *testing.T) {
 		input := &dto.ApplicationRequest{
 			Phone:           "+1234567890",
 			Email:           "test@example.com",
-			MonthlyIncome:   5000.0,
-			MonthlyExpenses: 2000.0,
+			MonthlyIncome:   eur("5000.0"),
+			MonthlyExpenses: eur("2000.0"),
 			MaritalStatus:   "single",
 			AgreeToBeScored: true,
-			Amount:          10000.0,
+			Amount:          eur("10000.0"),
 			Dependents:      2,
 		}
 
@@ -337,3 +483,57 @@ func TestToCustomerApplicationFromRequest_EdgeCases(t *testing.T) {
 		assert.NotEqual(t, result1.ID, result2.ID)
 	})
 }
+
+func TestToApplicationRequestFromModel(t *testing.T) {
+	application := &models.Application{
+		ID:              uuid.New(),
+		Phone:           "+1234567890",
+		Email:           "test@example.com",
+		MonthlyIncome:   eur("5000.0"),
+		MonthlyExpenses: eur("2000.0"),
+		MaritalStatus:   "MARRIED",
+		AgreeToBeScored: true,
+		Amount:          eur("10000.0"),
+		Dependents:      2,
+		Status:          string(dto.StatusProcessing),
+		TermMonths:      &[]int{60}[0],
+		Purpose:         &[]string{"HOME_RENOVATION"}[0],
+	}
+
+	result := ToApplicationRequestFromModel(application)
+
+	assert.Equal(t, dto.ApplicationRequest{
+		Phone:           "+1234567890",
+		Email:           "test@example.com",
+		MonthlyIncome:   eur("5000.0"),
+		MonthlyExpenses: eur("2000.0"),
+		MaritalStatus:   "MARRIED",
+		AgreeToBeScored: true,
+		Amount:          eur("10000.0"),
+		Dependents:      2,
+		TermMonths:      &[]int{60}[0],
+		Purpose:         dto.LoanPurposeHomeRenovation,
+		Currency:        money.EUR,
+	}, result)
+}
+
+func TestDroppedLoanFields(t *testing.T) {
+	req := dto.ApplicationRequest{
+		TermMonths:          &[]int{24}[0],
+		Purpose:             dto.LoanPurposeCar,
+		Currency:            money.EUR,
+		PreferredPaymentDay: &[]int{10}[0],
+	}
+
+	t.Run("unsupported loan parameters should be dropped", func(t *testing.T) {
+		assert.Equal(t, []string{dto.LoanFieldPurpose, dto.LoanFieldPreferredPaymentDay}, DroppedLoanFields(req, dto.LoanFieldTermMonths))
+	})
+
+	t.Run("default currency should not be reported", func(t *testing.T) {
+		assert.NotContains(t, DroppedLoanFields(req), dto.LoanFieldCurrency)
+	})
+
+	t.Run("request without loan parameters should drop nothing", func(t *testing.T) {
+		assert.Empty(t, DroppedLoanFields(dto.ApplicationRequest{}))
+	})
+}
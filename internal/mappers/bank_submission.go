@@ -22,16 +22,25 @@ func ToBankSubmissionModel(bankSubmission *dto.BankSubmission) *models.BankSubmi
 		error = &bankSubmission.Error
 	}
 
+	var submittedAt *time.Time
+	if !bankSubmission.SubmittedAt.IsZero() {
+		submittedAt = &bankSubmission.SubmittedAt
+	}
+
 	return &models.BankSubmission{
-		ID:           bankSubmission.ID,
-		BankName:     bankSubmission.BankName,
-		Status:       string(bankSubmission.Status),
-		BankID:       bankID,
-		SubmittedAt:  &bankSubmission.SubmittedAt,
-		CompletedAt:  bankSubmission.CompletedAt,
-		Error:        error,
-		ErrorMessage: bankSubmission.ErrorMessage,
-		CreatedAt:    bankSubmission.CreatedAt,
+		ID:              bankSubmission.ID,
+		BankName:        bankSubmission.BankName,
+		Status:          string(bankSubmission.Status),
+		BankID:          bankID,
+		SubmittedAt:     submittedAt,
+		CompletedAt:     bankSubmission.CompletedAt,
+		Error:           error,
+		ErrorMessage:    bankSubmission.ErrorMessage,
+		NextPollAt:      bankSubmission.NextPollAt,
+		PollAttempts:    bankSubmission.PollAttempts,
+		WithdrawnAt:     bankSubmission.WithdrawnAt,
+		WithdrawalError: bankSubmission.WithdrawalError,
+		CreatedAt:       bankSubmission.CreatedAt,
 	}
 }
 
@@ -56,14 +65,18 @@ func ToBankSubmissionFromModel(bankSubmission *models.BankSubmission) *dto.BankS
 	}
 
 	return &dto.BankSubmission{
-		ID:           bankSubmission.ID,
-		BankName:     bankSubmission.BankName,
-		Status:       dto.BankSubmissionStatus(bankSubmission.Status),
-		BankID:       bankID,
-		SubmittedAt:  submittedAt,
-		CompletedAt:  bankSubmission.CompletedAt,
-		Error:        error,
-		ErrorMessage: bankSubmission.ErrorMessage,
-		CreatedAt:    bankSubmission.CreatedAt,
+		ID:              bankSubmission.ID,
+		BankName:        bankSubmission.BankName,
+		Status:          dto.BankSubmissionStatus(bankSubmission.Status),
+		BankID:          bankID,
+		SubmittedAt:     submittedAt,
+		CompletedAt:     bankSubmission.CompletedAt,
+		Error:           error,
+		ErrorMessage:    bankSubmission.ErrorMessage,
+		NextPollAt:      bankSubmission.NextPollAt,
+		PollAttempts:    bankSubmission.PollAttempts,
+		WithdrawnAt:     bankSubmission.WithdrawnAt,
+		WithdrawalError: bankSubmission.WithdrawalError,
+		CreatedAt:       bankSubmission.CreatedAt,
 	}
 }
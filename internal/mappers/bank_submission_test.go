@@ -37,6 +37,8 @@ func TestToBankSubmissionModel(t *testing.T) {
 				CompletedAt:  &now,
 				Error:        "test-error",
 				ErrorMessage: &testMessage,
+				NextPollAt:   &now,
+				PollAttempts: 3,
 				CreatedAt:    now,
 			},
 			expected: &models.BankSubmission{
@@ -48,6 +50,8 @@ func TestToBankSubmissionModel(t *testing.T) {
 				CompletedAt:  &now,
 				Error:        &[]string{"test-error"}[0],
 				ErrorMessage: &testMessage,
+				NextPollAt:   &now,
+				PollAttempts: 3,
 				CreatedAt:    now,
 			},
 		},
@@ -76,6 +80,21 @@ func TestToBankSubmissionModel(t *testing.T) {
 				CreatedAt:    now,
 			},
 		},
+		{
+			name: "bank submission not yet submitted should have a nil submitted at",
+			input: &dto.BankSubmission{
+				ID:        submissionID,
+				BankName:  "TestBank",
+				Status:    dto.SubmissionStatusRetry,
+				CreatedAt: now,
+			},
+			expected: &models.BankSubmission{
+				ID:        submissionID,
+				BankName:  "TestBank",
+				Status:    "RETRY",
+				CreatedAt: now,
+			},
+		},
 		{
 			name: "bank submission with failed status",
 			input: &dto.BankSubmission{
@@ -121,6 +140,8 @@ func TestToBankSubmissionModel(t *testing.T) {
 			assert.Equal(t, tt.expected.CompletedAt, result.CompletedAt)
 			assert.Equal(t, tt.expected.Error, result.Error)
 			assert.Equal(t, tt.expected.ErrorMessage, result.ErrorMessage)
+			assert.Equal(t, tt.expected.NextPollAt, result.NextPollAt)
+			assert.Equal(t, tt.expected.PollAttempts, result.PollAttempts)
 			assert.Equal(t, tt.expected.CreatedAt, result.CreatedAt)
 		})
 	}
@@ -152,6 +173,8 @@ func TestToBankSubmissionFromModel(t *testing.T) {
 				CompletedAt:  &now,
 				Error:        &[]string{"test-error"}[0],
 				ErrorMessage: &testMessage,
+				NextPollAt:   &now,
+				PollAttempts: 3,
 				CreatedAt:    now,
 			},
 			expected: &dto.BankSubmission{
@@ -163,6 +186,8 @@ func TestToBankSubmissionFromModel(t *testing.T) {
 				CompletedAt:  &now,
 				Error:        "test-error",
 				ErrorMessage: &testMessage,
+				NextPollAt:   &now,
+				PollAttempts: 3,
 				CreatedAt:    now,
 			},
 		},
@@ -236,6 +261,8 @@ func TestToBankSubmissionFromModel(t *testing.T) {
 			assert.Equal(t, tt.expected.CompletedAt, result.CompletedAt)
 			assert.Equal(t, tt.expected.Error, result.Error)
 			assert.Equal(t, tt.expected.ErrorMessage, result.ErrorMessage)
+			assert.Equal(t, tt.expected.NextPollAt, result.NextPollAt)
+			assert.Equal(t, tt.expected.PollAttempts, result.PollAttempts)
 			assert.Equal(t, tt.expected.CreatedAt, result.CreatedAt)
 		})
 	}
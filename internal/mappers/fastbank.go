@@ -5,21 +5,29 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/money"
+)
+
+// FastBank takes amounts with two decimal places. Its offers are rounded to
+// cents half up.
+const (
+	fastBankDecimals = 2
+	fastBankRounding = money.RoundHalfUp
 )
 
 func ToFastBankRequestFromApplicationRequest(req dto.ApplicationRequest) *dto.FastBankApplicationRequest {
 	return &dto.FastBankApplicationRequest{
 		PhoneNumber:              req.Phone,
 		Email:                    req.Email,
-		MonthlyIncomeAmount:      req.MonthlyIncome,
-		MonthlyCreditLiabilities: req.MonthlyExpenses,
+		MonthlyIncomeAmount:      toBankAmount(req.MonthlyIncome, fastBankDecimals, fastBankRounding),
+		MonthlyCreditLiabilities: toBankAmount(req.MonthlyExpenses, fastBankDecimals, fastBankRounding),
 		Dependents:               req.Dependents,
 		AgreeToDataSharing:       req.AgreeToBeScored,
-		Amount:                   req.Amount,
+		Amount:                   toBankAmount(req.Amount, fastBankDecimals, fastBankRounding),
 	}
 }
 
-func ToOfferFromFastBankApplication(app dto.FastBankApplication, bankName string) *dto.Offer {
+func ToOfferFromFastBankApplication(app dto.FastBankApplication, bankName string, currency money.Currency) *dto.Offer {
 	if app.Status != "PROCESSED" {
 		return nil
 	}
@@ -27,13 +35,14 @@ func ToOfferFromFastBankApplication(app dto.FastBankApplication, bankName string
 	offer := &dto.Offer{
 		ID:        uuid.New(),
 		BankName:  bankName,
+		Currency:  currency,
 		CreatedAt: time.Now(),
 	}
 
 	if app.Offer != nil {
 		offer.Status = dto.OfferStatusApproved
-		offer.MonthlyPaymentAmount = &app.Offer.MonthlyPaymentAmount
-		offer.TotalRepaymentAmount = &app.Offer.TotalRepaymentAmount
+		offer.MonthlyPaymentAmount = bankAmount(app.Offer.MonthlyPaymentAmount, currency, fastBankRounding)
+		offer.TotalRepaymentAmount = bankAmount(app.Offer.TotalRepaymentAmount, currency, fastBankRounding)
 		offer.NumberOfPayments = &app.Offer.NumberOfPayments
 		offer.AnnualPercentageRate = &app.Offer.AnnualPercentageRate
 		offer.FirstRepaymentDate = &app.Offer.FirstRepaymentDate
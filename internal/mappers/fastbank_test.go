@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -20,11 +21,11 @@ func TestToFastBankRequestFromApplicationRequest(t *testing.T) {
 			input: dto.ApplicationRequest{
 				Phone:           "+1234567890",
 				Email:           "test@example.com",
-				MonthlyIncome:   5000.0,
-				MonthlyExpenses: 2000.0,
+				MonthlyIncome:   eur("5000.0"),
+				MonthlyExpenses: eur("2000.0"),
 				MaritalStatus:   "single",
 				AgreeToBeScored: true,
-				Amount:          10000.0,
+				Amount:          eur("10000.0"),
 				Dependents:      2,
 			},
 			expected: &dto.FastBankApplicationRequest{
@@ -42,11 +43,11 @@ func TestToFastBankRequestFromApplicationRequest(t *testing.T) {
 			input: dto.ApplicationRequest{
 				Phone:           "+1234567890",
 				Email:           "test@example.com",
-				MonthlyIncome:   999999.99,
-				MonthlyExpenses: 888888.88,
+				MonthlyIncome:   eur("999999.99"),
+				MonthlyExpenses: eur("888888.88"),
 				MaritalStatus:   "married",
 				AgreeToBeScored: false,
-				Amount:          1000000.0,
+				Amount:          eur("1000000.0"),
 				Dependents:      10,
 			},
 			expected: &dto.FastBankApplicationRequest{
@@ -166,7 +167,7 @@ func TestToOfferFromFastBankApplication(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := ToOfferFromFastBankApplication(tt.application, tt.bankName)
+			result := ToOfferFromFastBankApplication(tt.application, tt.bankName, money.EUR)
 
 			if tt.shouldReturnNil {
 				assert.Nil(t, result)
@@ -186,8 +187,8 @@ func TestToOfferFromFastBankApplication(t *testing.T) {
 				require.NotNil(t, result.AnnualPercentageRate)
 				require.NotNil(t, result.FirstRepaymentDate)
 
-				assert.Equal(t, tt.application.Offer.MonthlyPaymentAmount, *result.MonthlyPaymentAmount)
-				assert.Equal(t, tt.application.Offer.TotalRepaymentAmount, *result.TotalRepaymentAmount)
+				assert.Equal(t, tt.application.Offer.MonthlyPaymentAmount, result.MonthlyPaymentAmount.Float64())
+				assert.Equal(t, tt.application.Offer.TotalRepaymentAmount, result.TotalRepaymentAmount.Float64())
 				assert.Equal(t, tt.application.Offer.NumberOfPayments, *result.NumberOfPayments)
 				assert.Equal(t, tt.application.Offer.AnnualPercentageRate, *result.AnnualPercentageRate)
 				assert.Equal(t, tt.application.Offer.FirstRepaymentDate, *result.FirstRepaymentDate)
@@ -222,7 +223,7 @@ func TestToOfferFromFastBankApplication_EdgeCases(t *testing.T) {
 					},
 				}
 
-				result := ToOfferFromFastBankApplication(app, "FastBank")
+				result := ToOfferFromFastBankApplication(app, "FastBank", money.EUR)
 				assert.Nil(t, result)
 			})
 		}
@@ -241,12 +242,12 @@ func TestToOfferFromFastBankApplication_EdgeCases(t *testing.T) {
 			},
 		}
 
-		result := ToOfferFromFastBankApplication(app, "FastBank")
+		result := ToOfferFromFastBankApplication(app, "FastBank", money.EUR)
 		require.NotNil(t, result)
 		assert.Equal(t, dto.OfferStatusApproved, result.Status)
 
-		assert.Equal(t, 99999.99, *result.MonthlyPaymentAmount)
-		assert.Equal(t, 1199999.88, *result.TotalRepaymentAmount)
+		assert.Equal(t, eur("99999.99"), *result.MonthlyPaymentAmount)
+		assert.Equal(t, eur("1199999.88"), *result.TotalRepaymentAmount)
 		assert.Equal(t, 240, *result.NumberOfPayments)
 		assert.Equal(t, 99.99, *result.AnnualPercentageRate)
 		assert.Equal(t, "2024-12-31", *result.FirstRepaymentDate)
@@ -259,8 +260,8 @@ func TestToOfferFromFastBankApplication_EdgeCases(t *testing.T) {
 			Offer:  nil,
 		}
 
-		result1 := ToOfferFromFastBankApplication(app, "FastBank")
-		result2 := ToOfferFromFastBankApplication(app, "FastBank")
+		result1 := ToOfferFromFastBankApplication(app, "FastBank", money.EUR)
+		result2 := ToOfferFromFastBankApplication(app, "FastBank", money.EUR)
 
 		require.NotNil(t, result1)
 		require.NotNil(t, result2)
@@ -274,9 +275,9 @@ func TestToOfferFromFastBankApplication_EdgeCases(t *testing.T) {
 			Offer:  nil,
 		}
 
-		result1 := ToOfferFromFastBankApplication(app, "FastBank")
+		result1 := ToOfferFromFastBankApplication(app, "FastBank", money.EUR)
 		time.Sleep(1 * time.Millisecond)
-		result2 := ToOfferFromFastBankApplication(app, "FastBank")
+		result2 := ToOfferFromFastBankApplication(app, "FastBank", money.EUR)
 
 		require.NotNil(t, result1)
 		require.NotNil(t, result2)
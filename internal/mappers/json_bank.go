@@ -0,0 +1,206 @@
+package mappers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/money"
+)
+
+const jsonBankRounding = money.RoundHalfUp
+
+func ToJSONBankRequestFromApplicationRequest(req dto.ApplicationRequest, requestMapping map[string]string) (map[string]any, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode application request: %w", err)
+	}
+
+	// Numbers are kept as their JSON text so amounts reach the bank exactly
+	// as they were validated.
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var source map[string]any
+	if err := decoder.Decode(&source); err != nil {
+		return nil, fmt.Errorf("failed to decode application request: %w", err)
+	}
+
+	bankReq := make(map[string]any, len(requestMapping))
+	for target, field := range requestMapping {
+		value, ok := source[field]
+		if !ok && isLoanField(field) {
+			continue
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown application field %q", field)
+		}
+		setJSONPath(bankReq, target, value)
+	}
+
+	return bankReq, nil
+}
+
+// isLoanField reports whether field is an optional loan parameter, which is
+// left out of the bank request when the customer did not give it.
+func isLoanField(field string) bool {
+	switch field {
+	case dto.LoanFieldTermMonths, dto.LoanFieldPurpose, dto.LoanFieldCurrency, dto.LoanFieldPreferredPaymentDay:
+		return true
+	}
+	return false
+}
+
+func ToBankSubmissionResponseFromJSONBankApplication(app map[string]any, mapping config.ResponseMapping) *dto.BankSubmissionResponse {
+	id := jsonString(app, mapping.ID)
+	if id == nil {
+		return nil
+	}
+
+	response := &dto.BankSubmissionResponse{
+		ID: *id,
+	}
+
+	if status := jsonString(app, mapping.Status); status != nil {
+		response.Status = *status
+	}
+
+	return response
+}
+
+func ToOfferFromJSONBankApplication(app map[string]any, mapping config.ResponseMapping, bankName string, currency money.Currency) *dto.Offer {
+	status := jsonString(app, mapping.Status)
+	if status == nil || !slices.Contains(mapping.ProcessedStatuses, *status) {
+		return nil
+	}
+
+	offer := &dto.Offer{
+		ID:        uuid.New(),
+		BankName:  bankName,
+		Currency:  currency,
+		CreatedAt: time.Now(),
+	}
+
+	bankOffer, ok := lookupJSONPath(app, mapping.Offer)
+	offerFields, isObject := bankOffer.(map[string]any)
+	if !ok || !isObject {
+		offer.Status = dto.OfferStatusRejected
+		return offer
+	}
+
+	fields := mapping.OfferFields
+	offer.Status = dto.OfferStatusApproved
+	offer.MonthlyPaymentAmount = jsonMoney(offerFields, fields.MonthlyPaymentAmount, currency)
+	offer.TotalRepaymentAmount = jsonMoney(offerFields, fields.TotalRepaymentAmount, currency)
+	offer.NumberOfPayments = jsonInt(offerFields, fields.NumberOfPayments)
+	offer.AnnualPercentageRate = jsonFloat(offerFields, fields.AnnualPercentageRate)
+	offer.FirstRepaymentDate = jsonString(offerFields, fields.FirstRepaymentDate)
+
+	return offer
+}
+
+func lookupJSONPath(doc map[string]any, path string) (any, bool) {
+	if path == "" {
+		return nil, false
+	}
+
+	var current any = doc
+	for _, key := range strings.Split(path, ".") {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		current, ok = object[key]
+		if !ok || current == nil {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+func setJSONPath(doc map[string]any, path string, value any) {
+	keys := strings.Split(path, ".")
+	current := doc
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			current[key] = next
+		}
+		current = next
+	}
+	current[keys[len(keys)-1]] = value
+}
+
+func jsonString(doc map[string]any, path string) *string {
+	value, ok := lookupJSONPath(doc, path)
+	if !ok {
+		return nil
+	}
+
+	var result string
+	switch v := value.(type) {
+	case string:
+		result = v
+	case float64:
+		result = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		result = fmt.Sprint(v)
+	}
+	return &result
+}
+
+func jsonFloat(doc map[string]any, path string) *float64 {
+	value, ok := lookupJSONPath(doc, path)
+	if !ok {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case float64:
+		return &v
+	case string:
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+			return &parsed
+		}
+	}
+	return nil
+}
+
+// jsonMoney reads an amount in currency from a number or numeric string,
+// rounding it to cents half up.
+func jsonMoney(doc map[string]any, path string, currency money.Currency) *money.Money {
+	value, ok := lookupJSONPath(doc, path)
+	if !ok {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case float64:
+		return bankAmount(v, currency, jsonBankRounding)
+	case string:
+		if amount, err := money.ParseRounded(v, currency, jsonBankRounding); err == nil {
+			return &amount
+		}
+	}
+	return nil
+}
+
+func jsonInt(doc map[string]any, path string) *int {
+	number := jsonFloat(doc, path)
+	if number == nil {
+		return nil
+	}
+
+	result := int(*number)
+	return &result
+}
@@ -0,0 +1,226 @@
+package mappers
+
+import (
+	"encoding/json"
+
+	"testing"
+
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testResponseMapping() config.ResponseMapping {
+	return config.ResponseMapping{
+		ID:                "loanId",
+		Status:            "decision.state",
+		ProcessedStatuses: []string{"APPROVED", "DECLINED"},
+		Offer:             "decision.offer",
+		OfferFields: config.OfferFieldsMapping{
+			MonthlyPaymentAmount: "monthly",
+			TotalRepaymentAmount: "total",
+			NumberOfPayments:     "terms.count",
+			AnnualPercentageRate: "apr",
+			FirstRepaymentDate:   "terms.firstDate",
+		},
+	}
+}
+
+func TestToJSONBankRequestFromApplicationRequest(t *testing.T) {
+	req := dto.ApplicationRequest{
+		Phone:           "+37126000000",
+		Email:           "john.doe@example.com",
+		MonthlyIncome:   eur("3000.0"),
+		MonthlyExpenses: eur("1200.0"),
+		MaritalStatus:   "MARRIED",
+		AgreeToBeScored: true,
+		Amount:          eur("8000.0"),
+		Dependents:      1,
+	}
+
+	t.Run("flat and nested fields should be mapped", func(t *testing.T) {
+		result, err := ToJSONBankRequestFromApplicationRequest(req, map[string]string{
+			"applicant.mobile":  "phone",
+			"applicant.email":   "email",
+			"income.net":        "monthlyIncome",
+			"loanAmount":        "amount",
+			"consent":           "agreeToBeScored",
+			"household.members": "dependents",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"applicant": map[string]any{
+				"mobile": "+37126000000",
+				"email":  "john.doe@example.com",
+			},
+			"income": map[string]any{
+				"net": json.Number("3000.00"),
+			},
+			"loanAmount": json.Number("8000.00"),
+			"consent":    true,
+			"household": map[string]any{
+				"members": json.Number("1"),
+			},
+		}, result)
+	})
+
+	t.Run("unknown source field should fail", func(t *testing.T) {
+		result, err := ToJSONBankRequestFromApplicationRequest(req, map[string]string{
+			"mobile": "phoneNumber",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown application field "phoneNumber"`)
+		assert.Nil(t, result)
+	})
+
+	t.Run("loan parameters should be mapped when given", func(t *testing.T) {
+		loanMapping := map[string]string{
+			"loan.term":    "termMonths",
+			"loan.purpose": "purpose",
+			"loan.payDay":  "preferredPaymentDay",
+		}
+
+		withTerm := req
+		withTerm.TermMonths = &[]int{24}[0]
+		withTerm.Purpose = dto.LoanPurposeCar
+
+		result, err := ToJSONBankRequestFromApplicationRequest(withTerm, loanMapping)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"loan": map[string]any{
+				"term":    json.Number("24"),
+				"purpose": "CAR",
+			},
+		}, result)
+
+		result, err = ToJSONBankRequestFromApplicationRequest(req, loanMapping)
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
+
+func TestToBankSubmissionResponseFromJSONBankApplication(t *testing.T) {
+	mapping := testResponseMapping()
+
+	tests := []struct {
+		name     string
+		app      map[string]any
+		expected *dto.BankSubmissionResponse
+	}{
+		{
+			name: "string id and nested status should map",
+			app: map[string]any{
+				"loanId":   "loan-123",
+				"decision": map[string]any{"state": "PENDING"},
+			},
+			expected: &dto.BankSubmissionResponse{ID: "loan-123", Status: "PENDING"},
+		},
+		{
+			name: "numeric id should be formatted without exponent",
+			app: map[string]any{
+				"loanId": 12345678.0,
+			},
+			expected: &dto.BankSubmissionResponse{ID: "12345678"},
+		},
+		{
+			name:     "missing id should return nil",
+			app:      map[string]any{"decision": map[string]any{"state": "PENDING"}},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ToBankSubmissionResponseFromJSONBankApplication(tt.app, mapping)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestToOfferFromJSONBankApplication(t *testing.T) {
+	mapping := testResponseMapping()
+
+	t.Run("processed application with offer should return approved offer", func(t *testing.T) {
+		app := map[string]any{
+			"loanId": "loan-123",
+			"decision": map[string]any{
+				"state": "APPROVED",
+				"offer": map[string]any{
+					"monthly": 500.0,
+					"total":   "6000.50",
+					"apr":     12.5,
+					"terms": map[string]any{
+						"count":     12.0,
+						"firstDate": "2024-02-01",
+					},
+				},
+			},
+		}
+
+		offer := ToOfferFromJSONBankApplication(app, mapping, "TrustBank", money.EUR)
+		require.NotNil(t, offer)
+		assert.Equal(t, "TrustBank", offer.BankName)
+		assert.Equal(t, money.EUR, offer.Currency)
+		assert.Equal(t, dto.OfferStatusApproved, offer.Status)
+		assert.NotEmpty(t, offer.ID)
+		assert.False(t, offer.CreatedAt.IsZero())
+
+		require.NotNil(t, offer.MonthlyPaymentAmount)
+		require.NotNil(t, offer.TotalRepaymentAmount)
+		require.NotNil(t, offer.NumberOfPayments)
+		require.NotNil(t, offer.AnnualPercentageRate)
+		require.NotNil(t, offer.FirstRepaymentDate)
+
+		assert.Equal(t, eur("500"), *offer.MonthlyPaymentAmount)
+		assert.Equal(t, eur("6000.50"), *offer.TotalRepaymentAmount)
+		assert.Equal(t, 12, *offer.NumberOfPayments)
+		assert.Equal(t, 12.5, *offer.AnnualPercentageRate)
+		assert.Equal(t, "2024-02-01", *offer.FirstRepaymentDate)
+	})
+
+	t.Run("processed application without offer should return rejected offer", func(t *testing.T) {
+		app := map[string]any{
+			"loanId":   "loan-123",
+			"decision": map[string]any{"state": "DECLINED", "offer": nil},
+		}
+
+		offer := ToOfferFromJSONBankApplication(app, mapping, "TrustBank", money.EUR)
+		require.NotNil(t, offer)
+		assert.Equal(t, dto.OfferStatusRejected, offer.Status)
+		assert.Nil(t, offer.MonthlyPaymentAmount)
+		assert.Nil(t, offer.TotalRepaymentAmount)
+		assert.Nil(t, offer.NumberOfPayments)
+		assert.Nil(t, offer.AnnualPercentageRate)
+		assert.Nil(t, offer.FirstRepaymentDate)
+	})
+
+	t.Run("missing offer fields should stay nil", func(t *testing.T) {
+		app := map[string]any{
+			"decision": map[string]any{
+				"state": "APPROVED",
+				"offer": map[string]any{"apr": 9.9},
+			},
+		}
+
+		offer := ToOfferFromJSONBankApplication(app, mapping, "TrustBank", money.EUR)
+		require.NotNil(t, offer)
+		assert.Equal(t, dto.OfferStatusApproved, offer.Status)
+		require.NotNil(t, offer.AnnualPercentageRate)
+		assert.Equal(t, 9.9, *offer.AnnualPercentageRate)
+		assert.Nil(t, offer.MonthlyPaymentAmount)
+		assert.Nil(t, offer.NumberOfPayments)
+	})
+
+	t.Run("unprocessed statuses should return nil", func(t *testing.T) {
+		for _, app := range []map[string]any{
+			{"decision": map[string]any{"state": "IN_REVIEW"}},
+			{"decision": map[string]any{}},
+			{},
+		} {
+			assert.Nil(t, ToOfferFromJSONBankApplication(app, mapping, "TrustBank", money.EUR))
+		}
+	})
+}
@@ -0,0 +1,20 @@
+package mappers
+
+import "github.com/lielamurs/aggregator/internal/money"
+
+// bankAmount converts an amount read from a bank's wire format to money in
+// the bank's currency, rounding it to cents with the bank's rounding mode.
+// Amounts that are not finite numbers are treated as missing.
+func bankAmount(value float64, currency money.Currency, mode money.RoundingMode) *money.Money {
+	amount, err := money.FromFloat(value, currency, mode)
+	if err != nil {
+		return nil
+	}
+	return &amount
+}
+
+// toBankAmount converts an amount to a bank's wire format, rounded to the
+// number of decimal places the bank accepts.
+func toBankAmount(amount money.Money, decimals int, mode money.RoundingMode) float64 {
+	return amount.Round(decimals, mode).Float64()
+}
@@ -0,0 +1,40 @@
+package mappers
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lielamurs/aggregator/internal/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eur(value string) money.Money {
+	return money.MustParse(value, money.EUR)
+}
+
+func TestBankAmount(t *testing.T) {
+	amount := bankAmount(152.33, money.EUR, money.RoundHalfUp)
+	require.NotNil(t, amount)
+	assert.Equal(t, eur("152.33"), *amount)
+
+	amount = bankAmount(152.335, money.EUR, money.RoundHalfUp)
+	require.NotNil(t, amount)
+	assert.Equal(t, eur("152.34"), *amount)
+
+	amount = bankAmount(152.335, money.EUR, money.RoundDown)
+	require.NotNil(t, amount)
+	assert.Equal(t, eur("152.33"), *amount)
+
+	amount = bankAmount(152.33, "SEK", money.RoundHalfUp)
+	require.NotNil(t, amount)
+	assert.Equal(t, money.MustParse("152.33", "SEK"), *amount)
+
+	assert.Nil(t, bankAmount(math.NaN(), money.EUR, money.RoundHalfUp))
+}
+
+func TestToBankAmount(t *testing.T) {
+	assert.Equal(t, 152.33, toBankAmount(eur("152.33"), 2, money.RoundHalfUp))
+	assert.Equal(t, 153.0, toBankAmount(eur("152.50"), 0, money.RoundHalfUp))
+	assert.Equal(t, 152.0, toBankAmount(eur("152.50"), 0, money.RoundHalfEven))
+}
@@ -1,8 +1,11 @@
 package mappers
 
 import (
+	"strings"
+
 	"github.com/lielamurs/aggregator/internal/dto"
 	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/money"
 )
 
 func ToOfferModel(offer *dto.Offer) *models.Offer {
@@ -11,15 +14,21 @@ func ToOfferModel(offer *dto.Offer) *models.Offer {
 	}
 
 	return &models.Offer{
-		ID:                   offer.ID,
-		BankName:             offer.BankName,
-		MonthlyPaymentAmount: offer.MonthlyPaymentAmount,
-		TotalRepaymentAmount: offer.TotalRepaymentAmount,
-		NumberOfPayments:     offer.NumberOfPayments,
-		AnnualPercentageRate: offer.AnnualPercentageRate,
-		FirstRepaymentDate:   offer.FirstRepaymentDate,
-		Status:               string(offer.Status),
-		CreatedAt:            offer.CreatedAt,
+		ID:                           offer.ID,
+		BankName:                     offer.BankName,
+		Currency:                     string(offer.Currency),
+		MonthlyPaymentAmount:         offer.MonthlyPaymentAmount,
+		TotalRepaymentAmount:         offer.TotalRepaymentAmount,
+		NumberOfPayments:             offer.NumberOfPayments,
+		AnnualPercentageRate:         offer.AnnualPercentageRate,
+		FirstRepaymentDate:           offer.FirstRepaymentDate,
+		VerifiedAnnualPercentageRate: offer.VerifiedAnnualPercentageRate,
+		Discrepancies:                JoinOfferDiscrepancies(offer.Discrepancies),
+		DebtServiceRatio:             offer.DebtServiceRatio,
+		AffordabilityIssues:          JoinAffordabilityIssues(offer.AffordabilityIssues),
+		Status:                       string(offer.Status),
+		AcceptanceStatus:             (*string)(offer.AcceptanceStatus),
+		CreatedAt:                    offer.CreatedAt,
 	}
 }
 
@@ -28,15 +37,81 @@ func ToOfferFromModel(offer *models.Offer) *dto.Offer {
 		return nil
 	}
 
+	currency := offerCurrency(offer)
 	return &dto.Offer{
-		ID:                   offer.ID,
-		BankName:             offer.BankName,
-		MonthlyPaymentAmount: offer.MonthlyPaymentAmount,
-		TotalRepaymentAmount: offer.TotalRepaymentAmount,
-		NumberOfPayments:     offer.NumberOfPayments,
-		AnnualPercentageRate: offer.AnnualPercentageRate,
-		FirstRepaymentDate:   offer.FirstRepaymentDate,
-		Status:               dto.OfferStatus(offer.Status),
-		CreatedAt:            offer.CreatedAt,
+		ID:                           offer.ID,
+		BankName:                     offer.BankName,
+		Currency:                     currency,
+		MonthlyPaymentAmount:         inCurrency(offer.MonthlyPaymentAmount, currency),
+		TotalRepaymentAmount:         inCurrency(offer.TotalRepaymentAmount, currency),
+		NumberOfPayments:             offer.NumberOfPayments,
+		AnnualPercentageRate:         offer.AnnualPercentageRate,
+		FirstRepaymentDate:           offer.FirstRepaymentDate,
+		VerifiedAnnualPercentageRate: offer.VerifiedAnnualPercentageRate,
+		Discrepancies:                SplitOfferDiscrepancies(offer.Discrepancies),
+		DebtServiceRatio:             offer.DebtServiceRatio,
+		Unaffordable:                 offer.AffordabilityIssues != "",
+		AffordabilityIssues:          SplitAffordabilityIssues(offer.AffordabilityIssues),
+		Status:                       dto.OfferStatus(offer.Status),
+		AcceptanceStatus:             (*dto.OfferAcceptanceStatus)(offer.AcceptanceStatus),
+		CreatedAt:                    offer.CreatedAt,
+	}
+}
+
+// offerCurrency returns the currency the bank quoted the offer in. Offers
+// stored before currencies were recorded are in the default currency.
+func offerCurrency(offer *models.Offer) money.Currency {
+	if offer.Currency == "" {
+		return money.DefaultCurrency
+	}
+	return money.Currency(offer.Currency)
+}
+
+// inCurrency labels amount with currency, as amounts read from the
+// database carry no currency of their own.
+func inCurrency(amount *money.Money, currency money.Currency) *money.Money {
+	if amount == nil {
+		return nil
+	}
+	labelled := amount.WithCurrency(currency)
+	return &labelled
+}
+
+// JoinOfferDiscrepancies stores discrepancies as a comma separated list.
+func JoinOfferDiscrepancies(discrepancies []dto.OfferDiscrepancy) string {
+	values := make([]string, len(discrepancies))
+	for i, discrepancy := range discrepancies {
+		values[i] = string(discrepancy)
+	}
+	return strings.Join(values, ",")
+}
+
+func SplitOfferDiscrepancies(value string) []dto.OfferDiscrepancy {
+	var discrepancies []dto.OfferDiscrepancy
+	for _, discrepancy := range strings.Split(value, ",") {
+		if discrepancy = strings.TrimSpace(discrepancy); discrepancy != "" {
+			discrepancies = append(discrepancies, dto.OfferDiscrepancy(discrepancy))
+		}
+	}
+	return discrepancies
+}
+
+// JoinAffordabilityIssues stores affordability issues as a comma separated
+// list.
+func JoinAffordabilityIssues(issues []dto.AffordabilityIssue) string {
+	values := make([]string, len(issues))
+	for i, issue := range issues {
+		values[i] = string(issue)
+	}
+	return strings.Join(values, ",")
+}
+
+func SplitAffordabilityIssues(value string) []dto.AffordabilityIssue {
+	var issues []dto.AffordabilityIssue
+	for _, issue := range strings.Split(value, ",") {
+		if issue = strings.TrimSpace(issue); issue != "" {
+			issues = append(issues, dto.AffordabilityIssue(issue))
+		}
 	}
+	return issues
 }
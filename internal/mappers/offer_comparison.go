@@ -0,0 +1,139 @@
+package mappers
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/money"
+)
+
+// ToOfferComparisonFromModels ranks the approved offers by rankBy, falling
+// back to the other figures on ties, and marks the best affordable one as
+// recommended. Offers missing the compared figure are ranked last. The total cost of
+// credit is the total repayment minus the borrowed amount.
+func ToOfferComparisonFromModels(offers []models.Offer, amount money.Money, rankBy dto.OfferRankBy) *dto.OfferComparison {
+	comparison := &dto.OfferComparison{
+		RankBy:   rankBy,
+		Ranked:   []dto.RankedOffer{},
+		Rejected: []dto.Offer{},
+	}
+
+	for _, offer := range offers {
+		offerDTO := ToOfferFromModel(&offer)
+		if offerDTO == nil {
+			continue
+		}
+
+		declined := offerDTO.AcceptanceStatus != nil && *offerDTO.AcceptanceStatus == dto.OfferAcceptanceDeclined
+		if offerDTO.Status != dto.OfferStatusApproved || declined {
+			comparison.Rejected = append(comparison.Rejected, *offerDTO)
+			continue
+		}
+
+		ranked := dto.RankedOffer{Offer: *offerDTO}
+		if offerDTO.TotalRepaymentAmount != nil {
+			if cost, err := offerDTO.TotalRepaymentAmount.Sub(amount); err == nil {
+				ranked.TotalCostOfCredit = &cost
+			}
+		}
+		comparison.Ranked = append(comparison.Ranked, ranked)
+	}
+
+	keys := rankingKeys(rankBy)
+	slices.SortStableFunc(comparison.Ranked, func(a, b dto.RankedOffer) int {
+		for _, key := range keys {
+			if c := key(&a, &b); c != 0 {
+				return c
+			}
+		}
+		return cmp.Or(cmp.Compare(a.BankName, b.BankName), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+
+	if len(comparison.Ranked) == 0 {
+		return comparison
+	}
+
+	best := comparison.Ranked[0]
+	recommended := false
+	for i := range comparison.Ranked {
+		offer := &comparison.Ranked[i]
+		offer.Rank = i + 1
+		offer.Recommended = !recommended && !offer.Unaffordable
+		recommended = recommended || offer.Recommended
+		offer.Deltas = dto.OfferDeltas{
+			AnnualPercentageRate: rateDelta(offer.AnnualPercentageRate, best.AnnualPercentageRate),
+			MonthlyPaymentAmount: amountDelta(offer.MonthlyPaymentAmount, best.MonthlyPaymentAmount),
+			TotalCostOfCredit:    amountDelta(offer.TotalCostOfCredit, best.TotalCostOfCredit),
+		}
+	}
+
+	return comparison
+}
+
+type rankingKey func(a, b *dto.RankedOffer) int
+
+func rankingKeys(rankBy dto.OfferRankBy) []rankingKey {
+	apr := func(a, b *dto.RankedOffer) int {
+		return compareOptional(a.AnnualPercentageRate, b.AnnualPercentageRate, cmp.Compare[float64])
+	}
+	monthly := func(a, b *dto.RankedOffer) int {
+		return compareOptional(a.MonthlyPaymentAmount, b.MonthlyPaymentAmount, compareAmounts)
+	}
+	total := func(a, b *dto.RankedOffer) int {
+		return compareOptional(a.TotalCostOfCredit, b.TotalCostOfCredit, compareAmounts)
+	}
+
+	switch rankBy {
+	case dto.OfferRankByMonthly:
+		return []rankingKey{monthly, apr, total}
+	case dto.OfferRankByTotal:
+		return []rankingKey{total, apr, monthly}
+	default:
+		return []rankingKey{apr, total, monthly}
+	}
+}
+
+// compareAmounts orders amounts in different currencies by currency code, as
+// they cannot be compared with each other.
+func compareAmounts(a, b money.Money) int {
+	if c, err := a.Cmp(b); err == nil {
+		return c
+	}
+	return cmp.Compare(a.Currency(), b.Currency())
+}
+
+// compareOptional orders missing values after present ones.
+func compareOptional[T any](a, b *T, compare func(a, b T) int) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	default:
+		return compare(*a, *b)
+	}
+}
+
+func rateDelta(value, best *float64) *float64 {
+	if value == nil || best == nil {
+		return nil
+	}
+	d := math.Round((*value-*best)*100) / 100
+	return &d
+}
+
+func amountDelta(value, best *money.Money) *money.Money {
+	if value == nil || best == nil {
+		return nil
+	}
+	d, err := value.Sub(*best)
+	if err != nil {
+		return nil
+	}
+	return &d
+}
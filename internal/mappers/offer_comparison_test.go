@@ -0,0 +1,124 @@
+package mappers
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func comparisonOffer(bankName string, apr float64, monthly, total string) models.Offer {
+	monthlyPayment, totalRepayment := eur(monthly), eur(total)
+	return models.Offer{
+		ID:                   uuid.New(),
+		BankName:             bankName,
+		AnnualPercentageRate: &apr,
+		MonthlyPaymentAmount: &monthlyPayment,
+		TotalRepaymentAmount: &totalRepayment,
+		Status:               string(dto.OfferStatusApproved),
+	}
+}
+
+func rankedBankNames(comparison *dto.OfferComparison) []string {
+	names := make([]string, len(comparison.Ranked))
+	for i, offer := range comparison.Ranked {
+		names[i] = offer.BankName
+	}
+	return names
+}
+
+func TestToOfferComparisonFromModels(t *testing.T) {
+	offers := []models.Offer{
+		comparisonOffer("LowRate", 9.5, "320.10", "11524.0"),
+		comparisonOffer("LowPayment", 11.2, "210.40", "12624.0"),
+		comparisonOffer("LowTotal", 10.1, "480.00", "11520.0"),
+	}
+
+	tests := []struct {
+		name     string
+		rankBy   dto.OfferRankBy
+		expected []string
+	}{
+		{name: "apr", rankBy: dto.OfferRankByAPR, expected: []string{"LowRate", "LowTotal", "LowPayment"}},
+		{name: "monthly payment", rankBy: dto.OfferRankByMonthly, expected: []string{"LowPayment", "LowRate", "LowTotal"}},
+		{name: "total cost of credit", rankBy: dto.OfferRankByTotal, expected: []string{"LowTotal", "LowRate", "LowPayment"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comparison := ToOfferComparisonFromModels(offers, eur("10000"), tt.rankBy)
+
+			assert.Equal(t, tt.rankBy, comparison.RankBy)
+			assert.Equal(t, tt.expected, rankedBankNames(comparison))
+			for i, offer := range comparison.Ranked {
+				assert.Equal(t, i+1, offer.Rank)
+				assert.Equal(t, i == 0, offer.Recommended)
+			}
+		})
+	}
+
+	t.Run("deltas should be measured against the best offer", func(t *testing.T) {
+		comparison := ToOfferComparisonFromModels(offers, eur("10000"), dto.OfferRankByAPR)
+		require.Len(t, comparison.Ranked, 3)
+
+		best := comparison.Ranked[0]
+		assert.Equal(t, eur("1524"), *best.TotalCostOfCredit)
+		assert.Equal(t, 0.0, *best.Deltas.AnnualPercentageRate)
+
+		last := comparison.Ranked[2]
+		assert.Equal(t, 1.7, *last.Deltas.AnnualPercentageRate)
+		assert.Equal(t, eur("-109.70"), *last.Deltas.MonthlyPaymentAmount)
+		assert.Equal(t, eur("1100"), *last.Deltas.TotalCostOfCredit)
+	})
+
+	t.Run("unaffordable offers should not be recommended", func(t *testing.T) {
+		unaffordable := comparisonOffer("Unaffordable", 8.0, "900", "10800")
+		unaffordable.AffordabilityIssues = "DEBT_SERVICE_RATIO_EXCEEDED"
+
+		comparison := ToOfferComparisonFromModels(append([]models.Offer{unaffordable}, offers...), eur("10000"), dto.OfferRankByAPR)
+		require.Len(t, comparison.Ranked, 4)
+
+		assert.Equal(t, "Unaffordable", comparison.Ranked[0].BankName)
+		assert.True(t, comparison.Ranked[0].Unaffordable)
+		assert.False(t, comparison.Ranked[0].Recommended)
+		assert.True(t, comparison.Ranked[1].Recommended)
+		assert.False(t, comparison.Ranked[2].Recommended)
+	})
+
+	t.Run("rejected and declined offers should be listed separately", func(t *testing.T) {
+		declined := string(dto.OfferAcceptanceDeclined)
+		declinedOffer := comparisonOffer("Declined", 5.0, "100", "10100")
+		declinedOffer.AcceptanceStatus = &declined
+
+		comparison := ToOfferComparisonFromModels([]models.Offer{
+			{ID: uuid.New(), BankName: "Rejecting", Status: string(dto.OfferStatusRejected)},
+			declinedOffer,
+			comparisonOffer("Approved", 12.0, "300", "12000"),
+		}, eur("10000"), dto.OfferRankByAPR)
+
+		assert.Equal(t, []string{"Approved"}, rankedBankNames(comparison))
+		require.Len(t, comparison.Rejected, 2)
+		assert.Equal(t, "Rejecting", comparison.Rejected[0].BankName)
+		assert.Equal(t, "Declined", comparison.Rejected[1].BankName)
+	})
+
+	t.Run("offers missing the compared figure should rank last", func(t *testing.T) {
+		incomplete := models.Offer{ID: uuid.New(), BankName: "Incomplete", Status: string(dto.OfferStatusApproved)}
+
+		comparison := ToOfferComparisonFromModels([]models.Offer{incomplete, comparisonOffer("Complete", 14.0, "300", "12000")}, eur("10000"), dto.OfferRankByAPR)
+
+		assert.Equal(t, []string{"Complete", "Incomplete"}, rankedBankNames(comparison))
+		assert.Nil(t, comparison.Ranked[1].TotalCostOfCredit)
+		assert.Nil(t, comparison.Ranked[1].Deltas.AnnualPercentageRate)
+	})
+
+	t.Run("no offers should produce empty lists", func(t *testing.T) {
+		comparison := ToOfferComparisonFromModels(nil, eur("10000"), dto.OfferRankByAPR)
+
+		assert.Empty(t, comparison.Ranked)
+		assert.Empty(t, comparison.Rejected)
+	})
+}
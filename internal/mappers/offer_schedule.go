@@ -0,0 +1,37 @@
+package mappers
+
+import (
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/offermath"
+)
+
+func ToOfferScheduleFromModel(offer *models.Offer, schedule *offermath.Schedule) *dto.OfferSchedule {
+	if offer == nil || schedule == nil {
+		return nil
+	}
+
+	installments := make([]dto.ScheduleInstallment, len(schedule.Installments))
+	for i, installment := range schedule.Installments {
+		installments[i] = dto.ScheduleInstallment{
+			Number:    installment.Number,
+			DueDate:   installment.Date.Format(offermath.DateLayout),
+			Payment:   installment.Payment,
+			Interest:  installment.Interest,
+			Principal: installment.Principal,
+			Balance:   installment.Balance,
+		}
+	}
+
+	return &dto.OfferSchedule{
+		OfferID:                    offer.ID,
+		BankName:                   offer.BankName,
+		Principal:                  schedule.Principal,
+		AnnualPercentageRate:       schedule.AnnualPercentageRate,
+		StatedAnnualPercentageRate: offer.AnnualPercentageRate,
+		TotalInterest:              schedule.TotalInterest,
+		TotalRepayment:             schedule.TotalRepayment,
+		Discrepancies:              SplitOfferDiscrepancies(offer.Discrepancies),
+		Installments:               installments,
+	}
+}
@@ -0,0 +1,48 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/offermath"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToOfferScheduleFromModel(t *testing.T) {
+	t.Run("nil input should return nil", func(t *testing.T) {
+		assert.Nil(t, ToOfferScheduleFromModel(nil, nil))
+	})
+
+	t.Run("schedule should map with the stated figures", func(t *testing.T) {
+		offer := &models.Offer{
+			ID:                   uuid.New(),
+			BankName:             "FastBank",
+			AnnualPercentageRate: &[]float64{7.9}[0],
+			Discrepancies:        "APR_MISMATCH",
+		}
+		schedule := &offermath.Schedule{
+			Principal:            eur("1000"),
+			AnnualPercentageRate: 10,
+			TotalInterest:        eur("100"),
+			TotalRepayment:       eur("1100"),
+			Installments: []offermath.Installment{
+				{Number: 1, Date: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Payment: eur("1100"), Interest: eur("100"), Principal: eur("1000")},
+			},
+		}
+
+		result := ToOfferScheduleFromModel(offer, schedule)
+
+		require.NotNil(t, result)
+		assert.Equal(t, offer.ID, result.OfferID)
+		assert.Equal(t, 10.0, result.AnnualPercentageRate)
+		assert.Equal(t, 7.9, *result.StatedAnnualPercentageRate)
+		assert.Equal(t, []dto.OfferDiscrepancy{dto.OfferDiscrepancyAPR}, result.Discrepancies)
+		assert.Equal(t, []dto.ScheduleInstallment{
+			{Number: 1, DueDate: "2026-01-01", Payment: eur("1100"), Interest: eur("100"), Principal: eur("1000")},
+		}, result.Installments)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/lielamurs/aggregator/internal/dto"
 	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -30,8 +31,9 @@ func TestToOfferModel(t *testing.T) {
 			input: &dto.Offer{
 				ID:                   offerID,
 				BankName:             "TestBank",
-				MonthlyPaymentAmount: &[]float64{500.0}[0],
-				TotalRepaymentAmount: &[]float64{6000.0}[0],
+				Currency:             money.EUR,
+				MonthlyPaymentAmount: &[]money.Money{eur("500.0")}[0],
+				TotalRepaymentAmount: &[]money.Money{eur("6000.0")}[0],
 				NumberOfPayments:     &[]int{12}[0],
 				AnnualPercentageRate: &[]float64{12.5}[0],
 				FirstRepaymentDate:   &[]string{"2024-01-01"}[0],
@@ -41,8 +43,9 @@ func TestToOfferModel(t *testing.T) {
 			expected: &models.Offer{
 				ID:                   offerID,
 				BankName:             "TestBank",
-				MonthlyPaymentAmount: &[]float64{500.0}[0],
-				TotalRepaymentAmount: &[]float64{6000.0}[0],
+				Currency:             "EUR",
+				MonthlyPaymentAmount: &[]money.Money{eur("500.0")}[0],
+				TotalRepaymentAmount: &[]money.Money{eur("6000.0")}[0],
 				NumberOfPayments:     &[]int{12}[0],
 				AnnualPercentageRate: &[]float64{12.5}[0],
 				FirstRepaymentDate:   &[]string{"2024-01-01"}[0],
@@ -64,6 +67,7 @@ func TestToOfferModel(t *testing.T) {
 			require.NotNil(t, result)
 			assert.Equal(t, tt.expected.ID, result.ID)
 			assert.Equal(t, tt.expected.BankName, result.BankName)
+			assert.Equal(t, tt.expected.Currency, result.Currency)
 			assert.Equal(t, tt.expected.MonthlyPaymentAmount, result.MonthlyPaymentAmount)
 			assert.Equal(t, tt.expected.TotalRepaymentAmount, result.TotalRepaymentAmount)
 			assert.Equal(t, tt.expected.NumberOfPayments, result.NumberOfPayments)
@@ -92,26 +96,35 @@ func TestToOfferFromModel(t *testing.T) {
 		{
 			name: "complete offer model should map correctly",
 			input: &models.Offer{
-				ID:                   offerID,
-				BankName:             "TestBank",
-				MonthlyPaymentAmount: &[]float64{500.0}[0],
-				TotalRepaymentAmount: &[]float64{6000.0}[0],
-				NumberOfPayments:     &[]int{12}[0],
-				AnnualPercentageRate: &[]float64{12.5}[0],
-				FirstRepaymentDate:   &[]string{"2024-01-01"}[0],
-				Status:               "APPROVED",
-				CreatedAt:            now,
+				ID:                           offerID,
+				BankName:                     "TestBank",
+				MonthlyPaymentAmount:         &[]money.Money{eur("500.0")}[0],
+				TotalRepaymentAmount:         &[]money.Money{eur("6000.0")}[0],
+				NumberOfPayments:             &[]int{12}[0],
+				AnnualPercentageRate:         &[]float64{12.5}[0],
+				FirstRepaymentDate:           &[]string{"2024-01-01"}[0],
+				VerifiedAnnualPercentageRate: &[]float64{12.47}[0],
+				Discrepancies:                "TOTAL_REPAYMENT_MISMATCH",
+				DebtServiceRatio:             &[]float64{0.45}[0],
+				AffordabilityIssues:          "DEBT_SERVICE_RATIO_EXCEEDED",
+				Status:                       "APPROVED",
+				CreatedAt:                    now,
 			},
 			expected: &dto.Offer{
-				ID:                   offerID,
-				BankName:             "TestBank",
-				MonthlyPaymentAmount: &[]float64{500.0}[0],
-				TotalRepaymentAmount: &[]float64{6000.0}[0],
-				NumberOfPayments:     &[]int{12}[0],
-				AnnualPercentageRate: &[]float64{12.5}[0],
-				FirstRepaymentDate:   &[]string{"2024-01-01"}[0],
-				Status:               dto.OfferStatusApproved,
-				CreatedAt:            now,
+				ID:                           offerID,
+				BankName:                     "TestBank",
+				MonthlyPaymentAmount:         &[]money.Money{eur("500.0")}[0],
+				TotalRepaymentAmount:         &[]money.Money{eur("6000.0")}[0],
+				NumberOfPayments:             &[]int{12}[0],
+				AnnualPercentageRate:         &[]float64{12.5}[0],
+				FirstRepaymentDate:           &[]string{"2024-01-01"}[0],
+				VerifiedAnnualPercentageRate: &[]float64{12.47}[0],
+				Discrepancies:                []dto.OfferDiscrepancy{dto.OfferDiscrepancyTotalRepayment},
+				DebtServiceRatio:             &[]float64{0.45}[0],
+				Unaffordable:                 true,
+				AffordabilityIssues:          []dto.AffordabilityIssue{dto.AffordabilityIssueDebtServiceRatio},
+				Status:                       dto.OfferStatusApproved,
+				CreatedAt:                    now,
 			},
 		},
 	}
@@ -133,10 +146,32 @@ func TestToOfferFromModel(t *testing.T) {
 			assert.Equal(t, tt.expected.NumberOfPayments, result.NumberOfPayments)
 			assert.Equal(t, tt.expected.AnnualPercentageRate, result.AnnualPercentageRate)
 			assert.Equal(t, tt.expected.FirstRepaymentDate, result.FirstRepaymentDate)
+			assert.Equal(t, tt.expected.VerifiedAnnualPercentageRate, result.VerifiedAnnualPercentageRate)
+			assert.Equal(t, tt.expected.Discrepancies, result.Discrepancies)
+			assert.Equal(t, tt.expected.DebtServiceRatio, result.DebtServiceRatio)
+			assert.Equal(t, tt.expected.Unaffordable, result.Unaffordable)
+			assert.Equal(t, tt.expected.AffordabilityIssues, result.AffordabilityIssues)
 			assert.Equal(t, tt.expected.Status, result.Status)
 			assert.Equal(t, tt.expected.CreatedAt, result.CreatedAt)
 		})
 	}
+
+	t.Run("amounts should be in the offer currency", func(t *testing.T) {
+		payment := eur("500")
+		result := ToOfferFromModel(&models.Offer{ID: offerID, Currency: "SEK", MonthlyPaymentAmount: &payment})
+
+		require.NotNil(t, result)
+		assert.Equal(t, money.Currency("SEK"), result.Currency)
+		assert.Equal(t, money.MustParse("500", "SEK"), *result.MonthlyPaymentAmount)
+		assert.Nil(t, result.TotalRepaymentAmount)
+	})
+
+	t.Run("offers without a currency should be in the default currency", func(t *testing.T) {
+		result := ToOfferFromModel(&models.Offer{ID: offerID})
+
+		require.NotNil(t, result)
+		assert.Equal(t, money.DefaultCurrency, result.Currency)
+	})
 }
 
 func TestOfferMappers_RoundTrip(t *testing.T) {
@@ -147,8 +182,8 @@ func TestOfferMappers_RoundTrip(t *testing.T) {
 		original := &dto.Offer{
 			ID:                   offerID,
 			BankName:             "TestBank",
-			MonthlyPaymentAmount: &[]float64{500.0}[0],
-			TotalRepaymentAmount: &[]float64{6000.0}[0],
+			MonthlyPaymentAmount: &[]money.Money{eur("500.0")}[0],
+			TotalRepaymentAmount: &[]money.Money{eur("6000.0")}[0],
 			NumberOfPayments:     &[]int{12}[0],
 			AnnualPercentageRate: &[]float64{12.5}[0],
 			FirstRepaymentDate:   &[]string{"2024-01-01"}[0],
@@ -175,8 +210,8 @@ func TestOfferMappers_RoundTrip(t *testing.T) {
 		original := &models.Offer{
 			ID:                   offerID,
 			BankName:             "TestBank",
-			MonthlyPaymentAmount: &[]float64{500.0}[0],
-			TotalRepaymentAmount: &[]float64{6000.0}[0],
+			MonthlyPaymentAmount: &[]money.Money{eur("500.0")}[0],
+			TotalRepaymentAmount: &[]money.Money{eur("6000.0")}[0],
 			NumberOfPayments:     &[]int{12}[0],
 			AnnualPercentageRate: &[]float64{12.5}[0],
 			FirstRepaymentDate:   &[]string{"2024-01-01"}[0],
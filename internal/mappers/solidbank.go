@@ -5,21 +5,29 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/money"
+)
+
+// SolidBank takes amounts with two decimal places. Its offers are rounded to
+// cents half up.
+const (
+	solidBankDecimals = 2
+	solidBankRounding = money.RoundHalfUp
 )
 
 func ToSolidBankRequestFromApplicationRequest(req dto.ApplicationRequest) *dto.SolidBankApplicationRequest {
 	return &dto.SolidBankApplicationRequest{
 		Phone:           req.Phone,
 		Email:           req.Email,
-		MonthlyIncome:   req.MonthlyIncome,
-		MonthlyExpenses: req.MonthlyExpenses,
+		MonthlyIncome:   toBankAmount(req.MonthlyIncome, solidBankDecimals, solidBankRounding),
+		MonthlyExpenses: toBankAmount(req.MonthlyExpenses, solidBankDecimals, solidBankRounding),
 		MaritalStatus:   req.MaritalStatus,
 		AgreeToBeScored: req.AgreeToBeScored,
-		Amount:          req.Amount,
+		Amount:          toBankAmount(req.Amount, solidBankDecimals, solidBankRounding),
 	}
 }
 
-func ToOfferFromSolidBankApplication(app dto.SolidBankApplication, bankName string) *dto.Offer {
+func ToOfferFromSolidBankApplication(app dto.SolidBankApplication, bankName string, currency money.Currency) *dto.Offer {
 	if app.Status != "PROCESSED" {
 		return nil
 	}
@@ -27,13 +35,14 @@ func ToOfferFromSolidBankApplication(app dto.SolidBankApplication, bankName stri
 	offer := &dto.Offer{
 		ID:        uuid.New(),
 		BankName:  bankName,
+		Currency:  currency,
 		CreatedAt: time.Now(),
 	}
 
 	if app.Offer != nil {
 		offer.Status = dto.OfferStatusApproved
-		offer.MonthlyPaymentAmount = &app.Offer.MonthlyPaymentAmount
-		offer.TotalRepaymentAmount = &app.Offer.TotalRepaymentAmount
+		offer.MonthlyPaymentAmount = bankAmount(app.Offer.MonthlyPaymentAmount, currency, solidBankRounding)
+		offer.TotalRepaymentAmount = bankAmount(app.Offer.TotalRepaymentAmount, currency, solidBankRounding)
 		offer.NumberOfPayments = &app.Offer.NumberOfPayments
 		offer.AnnualPercentageRate = &app.Offer.AnnualPercentageRate
 		offer.FirstRepaymentDate = &app.Offer.FirstRepaymentDate
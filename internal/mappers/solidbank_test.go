@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -20,11 +21,11 @@ func TestToSolidBankRequestFromApplicationRequest(t *testing.T) {
 			input: dto.ApplicationRequest{
 				Phone:           "+1234567890",
 				Email:           "test@example.com",
-				MonthlyIncome:   5000.0,
-				MonthlyExpenses: 2000.0,
+				MonthlyIncome:   eur("5000.0"),
+				MonthlyExpenses: eur("2000.0"),
 				MaritalStatus:   "single",
 				AgreeToBeScored: true,
-				Amount:          10000.0,
+				Amount:          eur("10000.0"),
 				Dependents:      2,
 			},
 			expected: &dto.SolidBankApplicationRequest{
@@ -42,11 +43,11 @@ func TestToSolidBankRequestFromApplicationRequest(t *testing.T) {
 			input: dto.ApplicationRequest{
 				Phone:           "+1234567890",
 				Email:           "test@example.com",
-				MonthlyIncome:   999999.99,
-				MonthlyExpenses: 888888.88,
+				MonthlyIncome:   eur("999999.99"),
+				MonthlyExpenses: eur("888888.88"),
 				MaritalStatus:   "married",
 				AgreeToBeScored: false,
-				Amount:          1000000.0,
+				Amount:          eur("1000000.0"),
 				Dependents:      10,
 			},
 			expected: &dto.SolidBankApplicationRequest{
@@ -64,11 +65,11 @@ func TestToSolidBankRequestFromApplicationRequest(t *testing.T) {
 			input: dto.ApplicationRequest{
 				Phone:           "+1234567890",
 				Email:           "test@example.com",
-				MonthlyIncome:   5000.0,
-				MonthlyExpenses: 2000.0,
+				MonthlyIncome:   eur("5000.0"),
+				MonthlyExpenses: eur("2000.0"),
 				MaritalStatus:   "divorced",
 				AgreeToBeScored: true,
-				Amount:          15000.0,
+				Amount:          eur("15000.0"),
 				Dependents:      3,
 			},
 			expected: &dto.SolidBankApplicationRequest{
@@ -171,7 +172,7 @@ func TestToOfferFromSolidBankApplication(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := ToOfferFromSolidBankApplication(tt.application, tt.bankName)
+			result := ToOfferFromSolidBankApplication(tt.application, tt.bankName, money.EUR)
 
 			if tt.shouldReturnNil {
 				assert.Nil(t, result)
@@ -191,8 +192,8 @@ func TestToOfferFromSolidBankApplication(t *testing.T) {
 				require.NotNil(t, result.AnnualPercentageRate)
 				require.NotNil(t, result.FirstRepaymentDate)
 
-				assert.Equal(t, tt.application.Offer.MonthlyPaymentAmount, *result.MonthlyPaymentAmount)
-				assert.Equal(t, tt.application.Offer.TotalRepaymentAmount, *result.TotalRepaymentAmount)
+				assert.Equal(t, tt.application.Offer.MonthlyPaymentAmount, result.MonthlyPaymentAmount.Float64())
+				assert.Equal(t, tt.application.Offer.TotalRepaymentAmount, result.TotalRepaymentAmount.Float64())
 				assert.Equal(t, tt.application.Offer.NumberOfPayments, *result.NumberOfPayments)
 				assert.Equal(t, tt.application.Offer.AnnualPercentageRate, *result.AnnualPercentageRate)
 				assert.Equal(t, tt.application.Offer.FirstRepaymentDate, *result.FirstRepaymentDate)
@@ -227,7 +228,7 @@ func TestToOfferFromSolidBankApplication_EdgeCases(t *testing.T) {
 					},
 				}
 
-				result := ToOfferFromSolidBankApplication(app, "SolidBank")
+				result := ToOfferFromSolidBankApplication(app, "SolidBank", money.EUR)
 				assert.Nil(t, result)
 			})
 		}
@@ -246,12 +247,12 @@ func TestToOfferFromSolidBankApplication_EdgeCases(t *testing.T) {
 			},
 		}
 
-		result := ToOfferFromSolidBankApplication(app, "SolidBank")
+		result := ToOfferFromSolidBankApplication(app, "SolidBank", money.EUR)
 		require.NotNil(t, result)
 		assert.Equal(t, dto.OfferStatusApproved, result.Status)
 
-		assert.Equal(t, 99999.99, *result.MonthlyPaymentAmount)
-		assert.Equal(t, 1199999.88, *result.TotalRepaymentAmount)
+		assert.Equal(t, eur("99999.99"), *result.MonthlyPaymentAmount)
+		assert.Equal(t, eur("1199999.88"), *result.TotalRepaymentAmount)
 		assert.Equal(t, 240, *result.NumberOfPayments)
 		assert.Equal(t, 99.99, *result.AnnualPercentageRate)
 		assert.Equal(t, "2024-12-31", *result.FirstRepaymentDate)
@@ -264,8 +265,8 @@ func TestToOfferFromSolidBankApplication_EdgeCases(t *testing.T) {
 			Offer:  nil,
 		}
 
-		result1 := ToOfferFromSolidBankApplication(app, "SolidBank")
-		result2 := ToOfferFromSolidBankApplication(app, "SolidBank")
+		result1 := ToOfferFromSolidBankApplication(app, "SolidBank", money.EUR)
+		result2 := ToOfferFromSolidBankApplication(app, "SolidBank", money.EUR)
 
 		require.NotNil(t, result1)
 		require.NotNil(t, result2)
@@ -279,9 +280,9 @@ func TestToOfferFromSolidBankApplication_EdgeCases(t *testing.T) {
 			Offer:  nil,
 		}
 
-		result1 := ToOfferFromSolidBankApplication(app, "SolidBank")
+		result1 := ToOfferFromSolidBankApplication(app, "SolidBank", money.EUR)
 		time.Sleep(1 * time.Millisecond)
-		result2 := ToOfferFromSolidBankApplication(app, "SolidBank")
+		result2 := ToOfferFromSolidBankApplication(app, "SolidBank", money.EUR)
 
 		require.NotNil(t, result1)
 		require.NotNil(t, result2)
@@ -294,11 +295,11 @@ func TestToSolidBankRequestFromApplicationRequest_EdgeCases(t *testing.T) {
 		input := dto.ApplicationRequest{
 			Phone:           "+1-234-567-890 ext. 123",
 			Email:           "test+tag@example.co.uk",
-			MonthlyIncome:   5000.50,
-			MonthlyExpenses: 2000.25,
+			MonthlyIncome:   eur("5000.50"),
+			MonthlyExpenses: eur("2000.25"),
 			MaritalStatus:   "it's complicated",
 			AgreeToBeScored: true,
-			Amount:          10000.99,
+			Amount:          eur("10000.99"),
 			Dependents:      2,
 		}
 
@@ -316,11 +317,11 @@ func TestToSolidBankRequestFromApplicationRequest_EdgeCases(t *testing.T) {
 		input := dto.ApplicationRequest{
 			Phone:           "+1234567890",
 			Email:           "test@example.com",
-			MonthlyIncome:   5000.0,
-			MonthlyExpenses: 2000.0,
+			MonthlyIncome:   eur("5000.0"),
+			MonthlyExpenses: eur("2000.0"),
 			MaritalStatus:   "single",
 			AgreeToBeScored: true,
-			Amount:          10000.0,
+			Amount:          eur("10000.0"),
 			Dependents:      99,
 		}
 
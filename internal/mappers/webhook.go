@@ -0,0 +1,113 @@
+package mappers
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+)
+
+// ToWebhookSubscriptionFromModel maps a subscription without its secret,
+// which is only returned when the subscription is created.
+func ToWebhookSubscriptionFromModel(subscription *models.WebhookSubscription) *dto.WebhookSubscription {
+	if subscription == nil {
+		return nil
+	}
+
+	return &dto.WebhookSubscription{
+		ID:        subscription.ID,
+		URL:       subscription.URL,
+		Events:    SplitWebhookEventTypes(subscription.EventTypes),
+		CreatedAt: subscription.CreatedAt,
+	}
+}
+
+func ToWebhookSubscriptionsResponseFromModels(subscriptions []models.WebhookSubscription) *dto.WebhookSubscriptionsResponse {
+	response := &dto.WebhookSubscriptionsResponse{
+		Subscriptions: make([]dto.WebhookSubscription, 0, len(subscriptions)),
+	}
+
+	for _, subscription := range subscriptions {
+		if mapped := ToWebhookSubscriptionFromModel(&subscription); mapped != nil {
+			response.Subscriptions = append(response.Subscriptions, *mapped)
+		}
+	}
+
+	return response
+}
+
+// JoinWebhookEventTypes stores event types as a comma separated list. An
+// empty list subscribes to every event.
+func JoinWebhookEventTypes(eventTypes []dto.WebhookEventType) string {
+	values := make([]string, len(eventTypes))
+	for i, eventType := range eventTypes {
+		values[i] = string(eventType)
+	}
+	return strings.Join(values, ",")
+}
+
+func SplitWebhookEventTypes(value string) []dto.WebhookEventType {
+	eventTypes := []dto.WebhookEventType{}
+	for _, eventType := range strings.Split(value, ",") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			eventTypes = append(eventTypes, dto.WebhookEventType(eventType))
+		}
+	}
+	return eventTypes
+}
+
+func ToWebhookDeliveryFromModel(delivery *models.WebhookDelivery) *dto.WebhookDelivery {
+	if delivery == nil {
+		return nil
+	}
+
+	response := &dto.WebhookDelivery{
+		ID:             delivery.ID,
+		EventID:        delivery.EventID,
+		EventType:      dto.WebhookEventType(delivery.EventType),
+		SubscriptionID: delivery.SubscriptionID,
+		URL:            delivery.URL,
+		Status:         dto.WebhookDeliveryStatus(delivery.Status),
+		Attempts:       delivery.Attempts,
+		DeliveredAt:    delivery.DeliveredAt,
+		AttemptLog:     make([]dto.WebhookDeliveryAttempt, 0, len(delivery.AttemptLog)),
+		CreatedAt:      delivery.CreatedAt,
+	}
+
+	if delivery.Status == string(dto.WebhookDeliveryPending) {
+		response.NextAttemptAt = delivery.NextAttemptAt
+	}
+
+	for _, attempt := range delivery.AttemptLog {
+		var errorMsg string
+		if attempt.Error != nil {
+			errorMsg = *attempt.Error
+		}
+
+		response.AttemptLog = append(response.AttemptLog, dto.WebhookDeliveryAttempt{
+			Attempt:    attempt.Attempt,
+			StatusCode: attempt.StatusCode,
+			Error:      errorMsg,
+			DurationMs: attempt.DurationMs,
+			CreatedAt:  attempt.CreatedAt,
+		})
+	}
+
+	return response
+}
+
+func ToWebhookDeliveriesResponseFromModels(applicationID uuid.UUID, deliveries []models.WebhookDelivery) *dto.WebhookDeliveriesResponse {
+	response := &dto.WebhookDeliveriesResponse{
+		ApplicationID: applicationID,
+		Deliveries:    make([]dto.WebhookDelivery, 0, len(deliveries)),
+	}
+
+	for _, delivery := range deliveries {
+		if mapped := ToWebhookDeliveryFromModel(&delivery); mapped != nil {
+			response.Deliveries = append(response.Deliveries, *mapped)
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,92 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookEventTypes(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventTypes []dto.WebhookEventType
+		stored     string
+	}{
+		{
+			name:       "empty list should subscribe to everything",
+			eventTypes: []dto.WebhookEventType{},
+			stored:     "",
+		},
+		{
+			name:       "event types should round trip",
+			eventTypes: []dto.WebhookEventType{dto.WebhookEventApplicationCompleted, dto.WebhookEventOfferReceived},
+			stored:     "application.completed,offer.received",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.stored, JoinWebhookEventTypes(tt.eventTypes))
+			assert.Equal(t, tt.eventTypes, SplitWebhookEventTypes(tt.stored))
+		})
+	}
+}
+
+func TestToWebhookSubscriptionFromModel(t *testing.T) {
+	assert.Nil(t, ToWebhookSubscriptionFromModel(nil))
+
+	subscription := ToWebhookSubscriptionFromModel(&models.WebhookSubscription{
+		ID:         uuid.New(),
+		URL:        "https://client.example.com/hooks",
+		Secret:     "whsec_secret",
+		EventTypes: "offer.received",
+	})
+
+	require.NotNil(t, subscription)
+	assert.Empty(t, subscription.Secret)
+	assert.Equal(t, []dto.WebhookEventType{dto.WebhookEventOfferReceived}, subscription.Events)
+}
+
+func TestToWebhookDeliveryFromModel(t *testing.T) {
+	now := time.Now()
+	statusCode := 503
+	errorMsg := "endpoint responded with HTTP 503"
+
+	tests := []struct {
+		name              string
+		status            dto.WebhookDeliveryStatus
+		expectNextAttempt bool
+	}{
+		{name: "pending delivery should show the next attempt", status: dto.WebhookDeliveryPending, expectNextAttempt: true},
+		{name: "failed delivery should not show a next attempt", status: dto.WebhookDeliveryFailed, expectNextAttempt: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delivery := ToWebhookDeliveryFromModel(&models.WebhookDelivery{
+				ID:            uuid.New(),
+				EventType:     string(dto.WebhookEventSubmissionFailed),
+				URL:           "https://client.example.com/hooks",
+				Secret:        "whsec_secret",
+				Status:        string(tt.status),
+				Attempts:      1,
+				NextAttemptAt: &now,
+				AttemptLog: []models.WebhookDeliveryAttempt{
+					{Attempt: 1, StatusCode: &statusCode, Error: &errorMsg, DurationMs: 12, CreatedAt: now},
+				},
+			})
+
+			require.NotNil(t, delivery)
+			assert.Equal(t, tt.status, delivery.Status)
+			assert.Equal(t, tt.expectNextAttempt, delivery.NextAttemptAt != nil)
+			require.Len(t, delivery.AttemptLog, 1)
+			assert.Equal(t, &statusCode, delivery.AttemptLog[0].StatusCode)
+			assert.Equal(t, errorMsg, delivery.AttemptLog[0].Error)
+		})
+	}
+}
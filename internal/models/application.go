@@ -4,22 +4,35 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/money"
 )
 
 type Application struct {
 	ID              uuid.UUID
 	Phone           string
 	Email           string
-	MonthlyIncome   float64
-	MonthlyExpenses float64
+	MonthlyIncome   money.Money
+	MonthlyExpenses money.Money
 	MaritalStatus   string
 	AgreeToBeScored bool
-	Amount          float64
+	Amount          money.Money
 	Dependents      int
 	Status          string
+	ClientID        *string
+	ClientIP        *string
+	CallbackURL     *string
+	CallbackSecret  *string
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 
+	TermMonths          *int
+	Purpose             *string
+	Currency            string
+	PreferredPaymentDay *int
+
+	DisposableIncome   *money.Money
+	SubsistenceMinimum *money.Money
+
 	Offers          []Offer          `gorm:"foreignKey:ApplicationID"`
 	BankSubmissions []BankSubmission `gorm:"foreignKey:ApplicationID"`
 }
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ApplicationEvent struct {
+	ID            uuid.UUID
+	ApplicationID uuid.UUID
+	EntityType    string
+	SubmissionID  *uuid.UUID
+	BankName      *string
+	OldStatus     *string
+	NewStatus     string
+	Actor         string
+	Reason        *string
+	CreatedAt     time.Time
+}
@@ -7,14 +7,20 @@ import (
 )
 
 type BankSubmission struct {
-	ID            uuid.UUID
-	ApplicationID uuid.UUID
-	BankName      string
-	Status        string
-	BankID        *string
-	SubmittedAt   *time.Time
-	CompletedAt   *time.Time
-	Error         *string
-	ErrorMessage  *string
-	CreatedAt     time.Time
+	ID              uuid.UUID
+	ApplicationID   uuid.UUID
+	BankName        string
+	Status          string
+	BankID          *string
+	SubmittedAt     *time.Time
+	CompletedAt     *time.Time
+	Error           *string
+	ErrorMessage    *string
+	NextPollAt      *time.Time
+	PollAttempts    int
+	LockedBy        *string
+	LockedUntil     *time.Time
+	WithdrawnAt     *time.Time
+	WithdrawalError *string
+	CreatedAt       time.Time
 }
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+type IdempotencyKey struct {
+	Key            string `gorm:"primaryKey"`
+	RequestHash    string
+	Status         string
+	ResponseStatus *int
+	ResponseBody   []byte
+	LockedBy       *string
+	LockedUntil    *time.Time
+	CreatedAt      time.Time
+	ExpiresAt      time.Time
+}
@@ -4,17 +4,25 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/money"
 )
 
 type Offer struct {
-	ID                   uuid.UUID
-	ApplicationID        uuid.UUID
-	BankName             string
-	MonthlyPaymentAmount *float64
-	TotalRepaymentAmount *float64
-	NumberOfPayments     *int
-	AnnualPercentageRate *float64
-	FirstRepaymentDate   *string
-	Status               string
-	CreatedAt            time.Time
+	ID                           uuid.UUID
+	ApplicationID                uuid.UUID
+	BankName                     string
+	Currency                     string
+	MonthlyPaymentAmount         *money.Money
+	TotalRepaymentAmount         *money.Money
+	NumberOfPayments             *int
+	AnnualPercentageRate         *float64
+	FirstRepaymentDate           *string
+	VerifiedAnnualPercentageRate *float64
+	Discrepancies                string
+	DebtServiceRatio             *float64
+	AffordabilityIssues          string
+	Status                       string
+	AcceptanceStatus             *string
+	AcceptanceClaimedAt          *time.Time
+	CreatedAt                    time.Time
 }
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SubmissionJob struct {
+	ID            uuid.UUID
+	ApplicationID uuid.UUID
+	BankName      string
+	Status        string
+	Attempts      int
+	RunAt         time.Time
+	LockedBy      *string
+	LockedUntil   *time.Time
+	LastError     *string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookSubscription struct {
+	ID         uuid.UUID
+	ClientID   string
+	URL        string
+	Secret     string
+	EventTypes string
+	CreatedAt  time.Time
+}
+
+type WebhookEvent struct {
+	ID            uuid.UUID
+	ApplicationID uuid.UUID
+	EventType     string
+	Payload       []byte
+	CreatedAt     time.Time
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	EventID        uuid.UUID
+	ApplicationID  uuid.UUID
+	SubscriptionID *uuid.UUID
+	EventType      string
+	URL            string
+	Secret         string
+	Status         string
+	Attempts       int
+	NextAttemptAt  *time.Time
+	LockedBy       *string
+	LockedUntil    *time.Time
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+
+	AttemptLog []WebhookDeliveryAttempt `gorm:"foreignKey:DeliveryID"`
+}
+
+type WebhookDeliveryAttempt struct {
+	ID         uuid.UUID
+	DeliveryID uuid.UUID
+	Attempt    int
+	StatusCode *int
+	Error      *string
+	DurationMs int64
+	CreatedAt  time.Time
+}
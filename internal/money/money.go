@@ -0,0 +1,301 @@
+// Package money holds exact decimal amounts of a currency. Amounts are kept
+// in cents, matching the DECIMAL(12,2) columns they are stored in, and are
+// parsed from and written as decimal text so no precision is lost to binary
+// floating point on the way in or out.
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+type Currency string
+
+const (
+	EUR Currency = "EUR"
+
+	DefaultCurrency = EUR
+)
+
+// Decimals is the number of decimal places amounts are kept with.
+const Decimals = 2
+
+const centsPerUnit = 100
+
+var (
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// RoundingMode decides which way an amount that falls between two
+// representable values is rounded.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds halves away from zero.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds halves to the nearest even digit.
+	RoundHalfEven
+	// RoundDown truncates towards zero.
+	RoundDown
+)
+
+// Money is an amount in cents of a currency. The zero value is zero in the
+// default currency.
+type Money struct {
+	cents    int64
+	currency Currency
+}
+
+func New(cents int64, currency Currency) Money {
+	return Money{cents: cents, currency: currency}
+}
+
+// Parse reads a decimal amount such as "152.33". Amounts with more than two
+// decimal places are rejected rather than silently rounded.
+func Parse(value string, currency Currency) (Money, error) {
+	rat, err := parseRat(value)
+	if err != nil {
+		return Money{}, err
+	}
+
+	cents, exact, err := ratToUnits(rat, Decimals, RoundDown)
+	if err != nil {
+		return Money{}, err
+	}
+	if !exact {
+		return Money{}, fmt.Errorf("%w: %q has more than %d decimal places", ErrInvalidAmount, value, Decimals)
+	}
+	return New(cents, currency), nil
+}
+
+// ParseRounded reads a decimal amount, rounding it to cents with mode.
+func ParseRounded(value string, currency Currency, mode RoundingMode) (Money, error) {
+	rat, err := parseRat(value)
+	if err != nil {
+		return Money{}, err
+	}
+
+	cents, _, err := ratToUnits(rat, Decimals, mode)
+	if err != nil {
+		return Money{}, err
+	}
+	return New(cents, currency), nil
+}
+
+// FromFloat converts a float read from a bank's wire format, rounding it to
+// cents with mode. The float's shortest decimal form is rounded, so 152.33
+// stays 152.33 instead of becoming 152.3299999.
+func FromFloat(value float64, currency Currency, mode RoundingMode) (Money, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return Money{}, fmt.Errorf("%w: %v", ErrInvalidAmount, value)
+	}
+	return ParseRounded(strconv.FormatFloat(value, 'g', -1, 64), currency, mode)
+}
+
+func (m Money) Cents() int64 {
+	return m.cents
+}
+
+func (m Money) Currency() Currency {
+	if m.currency == "" {
+		return DefaultCurrency
+	}
+	return m.currency
+}
+
+func (m Money) WithCurrency(currency Currency) Money {
+	m.currency = currency
+	return m
+}
+
+func (m Money) IsZero() bool {
+	return m.cents == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.cents > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.cents < 0
+}
+
+// Cmp compares m with other, returning -1, 0 or 1 as m is less than, equal
+// to or greater than other. Amounts in different currencies cannot be
+// compared.
+func (m Money) Cmp(other Money) (int, error) {
+	if m.Currency() != other.Currency() {
+		return 0, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency(), other.Currency())
+	}
+	switch {
+	case m.cents < other.cents:
+		return -1, nil
+	case m.cents > other.cents:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency() != other.Currency() {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency(), other.Currency())
+	}
+	return New(m.cents+other.cents, m.currency), nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency() != other.Currency() {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency(), other.Currency())
+	}
+	return New(m.cents-other.cents, m.currency), nil
+}
+
+// Mul multiplies the amount by a whole factor, such as a number of payments.
+func (m Money) Mul(factor int64) Money {
+	return New(m.cents*factor, m.currency)
+}
+
+// Round rounds the amount to places decimal places (0 to 2) with mode, for
+// banks that expect less precise amounts.
+func (m Money) Round(places int, mode RoundingMode) Money {
+	if places >= Decimals {
+		return m
+	}
+
+	step := int64(math.Pow10(Decimals - max(places, 0)))
+	rat := new(big.Rat).SetFrac64(m.cents, step)
+	units, _, _ := ratToUnits(rat, 0, mode)
+	return New(units*step, m.currency)
+}
+
+// Float64 returns the amount as the float closest to it, for wire formats
+// and calculations that need one.
+func (m Money) Float64() float64 {
+	return float64(m.cents) / centsPerUnit
+}
+
+// String formats the amount with two decimal places, without currency.
+func (m Money) String() string {
+	sign := ""
+	cents := m.cents
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/centsPerUnit, cents%centsPerUnit)
+}
+
+// MarshalJSON writes the amount as a JSON number with two decimal places.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON reads a JSON number or numeric string exactly. The currency
+// is left unchanged.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+
+	parsed, err := Parse(text, m.currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value stores the amount as decimal text.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan reads a DECIMAL column. The currency is left unchanged.
+func (m *Money) Scan(src any) error {
+	var (
+		parsed Money
+		err    error
+	)
+
+	switch v := src.(type) {
+	case nil:
+		parsed = New(0, m.currency)
+	case string:
+		parsed, err = Parse(v, m.currency)
+	case []byte:
+		parsed, err = Parse(string(v), m.currency)
+	case int64:
+		parsed = New(v*centsPerUnit, m.currency)
+	case float64:
+		parsed, err = FromFloat(v, m.currency, RoundHalfUp)
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidAmount, src)
+	}
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+func parseRat(value string) (*big.Rat, error) {
+	value = strings.TrimSpace(value)
+	rat, ok := new(big.Rat).SetString(value)
+	if value == "" || !ok || strings.ContainsAny(value, "/") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	return rat, nil
+}
+
+// ratToUnits converts rat to a whole number of 10^-places units, rounding
+// with mode. exact reports whether no rounding was needed.
+func ratToUnits(rat *big.Rat, places int, mode RoundingMode) (units int64, exact bool, err error) {
+	scaled := new(big.Rat).Mul(rat, new(big.Rat).SetInt64(int64(math.Pow10(places))))
+
+	quotient, remainder := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	if remainder.Sign() != 0 {
+		// Compare twice the remainder with the denominator to find out
+		// whether the dropped part is below, at or above one half.
+		half := new(big.Int).Abs(remainder)
+		half.Lsh(half, 1)
+		position := half.Cmp(scaled.Denom())
+
+		roundAway := false
+		switch mode {
+		case RoundHalfUp:
+			roundAway = position >= 0
+		case RoundHalfEven:
+			roundAway = position > 0 || (position == 0 && quotient.Bit(0) == 1)
+		}
+		if roundAway {
+			quotient.Add(quotient, big.NewInt(int64(scaled.Sign())))
+		}
+	}
+
+	if !quotient.IsInt64() {
+		return 0, false, fmt.Errorf("%w: %s is out of range", ErrInvalidAmount, rat.FloatString(places))
+	}
+	return quotient.Int64(), remainder.Sign() == 0, nil
+}
+
+// MustParse is like Parse but panics on invalid amounts. It is meant for
+// constants and tests.
+func MustParse(value string, currency Currency) Money {
+	amount, err := Parse(value, currency)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
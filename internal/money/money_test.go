@@ -0,0 +1,173 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int64
+	}{
+		{name: "whole amount", input: "1500", expected: 150000},
+		{name: "cents", input: "152.33", expected: 15233},
+		{name: "single decimal", input: "0.5", expected: 50},
+		{name: "negative amount", input: "-12.05", expected: -1205},
+		{name: "exponent", input: "1.5e3", expected: 150000},
+		{name: "trailing zeros", input: "10.500", expected: 1050},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(tt.input, EUR)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Cents())
+			assert.Equal(t, EUR, result.Currency())
+		})
+	}
+
+	invalid := []string{"", "abc", "1/3", "152.333", "1e30"}
+	for _, input := range invalid {
+		t.Run(input+" should be rejected", func(t *testing.T) {
+			_, err := Parse(input, EUR)
+			assert.ErrorIs(t, err, ErrInvalidAmount)
+		})
+	}
+}
+
+func TestParseRounded(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		mode     RoundingMode
+		expected int64
+	}{
+		{name: "half up should round halves away from zero", input: "0.125", mode: RoundHalfUp, expected: 13},
+		{name: "half up should round negative halves away from zero", input: "-0.125", mode: RoundHalfUp, expected: -13},
+		{name: "half even should round halves to even", input: "0.125", mode: RoundHalfEven, expected: 12},
+		{name: "half even should round odd halves up", input: "0.135", mode: RoundHalfEven, expected: 14},
+		{name: "half even should round above half up", input: "0.1251", mode: RoundHalfEven, expected: 13},
+		{name: "down should truncate", input: "0.129", mode: RoundDown, expected: 12},
+		{name: "down should truncate towards zero", input: "-0.129", mode: RoundDown, expected: -12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseRounded(tt.input, EUR, tt.mode)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Cents())
+		})
+	}
+}
+
+func TestFromFloat(t *testing.T) {
+	result, err := FromFloat(152.33, EUR, RoundHalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, int64(15233), result.Cents())
+
+	result, err = FromFloat(0.1+0.2, EUR, RoundHalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, int64(30), result.Cents())
+
+	result, err = FromFloat(2.675, EUR, RoundHalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, int64(268), result.Cents())
+}
+
+func TestMoney_Round(t *testing.T) {
+	amount := New(15250, EUR)
+
+	assert.Equal(t, amount, amount.Round(2, RoundHalfUp))
+	assert.Equal(t, int64(15300), amount.Round(0, RoundHalfUp).Cents())
+	assert.Equal(t, int64(15200), amount.Round(0, RoundHalfEven).Cents())
+	assert.Equal(t, int64(15200), amount.Round(0, RoundDown).Cents())
+	assert.Equal(t, int64(15260), New(15255, EUR).Round(1, RoundHalfUp).Cents())
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a := New(1000, EUR)
+	b := New(250, "")
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1250), sum.Cents())
+
+	difference, err := b.Sub(a)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-750), difference.Cents())
+	assert.True(t, difference.IsNegative())
+
+	assert.Equal(t, int64(12000), a.Mul(12).Cents())
+	cmp, err := a.Cmp(b)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cmp)
+	cmp, err = b.Cmp(a)
+	require.NoError(t, err)
+	assert.Equal(t, -1, cmp)
+
+	_, err = a.Add(New(100, "USD"))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = a.Cmp(New(100, "USD"))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "152.33", New(15233, EUR).String())
+	assert.Equal(t, "0.05", New(5, EUR).String())
+	assert.Equal(t, "-0.50", New(-50, EUR).String())
+	assert.Equal(t, "1000.00", New(100000, EUR).String())
+}
+
+func TestMoney_JSON(t *testing.T) {
+	var payload struct {
+		Amount   Money  `json:"amount"`
+		Optional *Money `json:"optional,omitempty"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"amount": 152.33}`), &payload))
+	assert.Equal(t, int64(15233), payload.Amount.Cents())
+	assert.Nil(t, payload.Optional)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"amount": "99.9", "optional": 1}`), &payload))
+	assert.Equal(t, int64(9990), payload.Amount.Cents())
+	assert.Equal(t, int64(100), payload.Optional.Cents())
+
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount": 99.90, "optional": 1.00}`, string(data))
+
+	err = json.Unmarshal([]byte(`{"amount": 152.333}`), &payload)
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+}
+
+func TestMoney_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		expected int64
+	}{
+		{name: "numeric text", src: "152.33", expected: 15233},
+		{name: "numeric bytes", src: []byte("7.10"), expected: 710},
+		{name: "integer", src: int64(12), expected: 1200},
+		{name: "float", src: 152.33, expected: 15233},
+		{name: "null", src: nil, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount := New(1, EUR)
+			require.NoError(t, amount.Scan(tt.src))
+			assert.Equal(t, tt.expected, amount.Cents())
+		})
+	}
+
+	value, err := New(15233, EUR).Value()
+	require.NoError(t, err)
+	assert.Equal(t, "152.33", value)
+}
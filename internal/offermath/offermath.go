@@ -0,0 +1,216 @@
+// Package offermath recomputes the figures of a loan offer from its cash
+// flows, so offers can be checked independently of what the bank states.
+//
+// The annual percentage rate follows the EU consumer credit definition: the
+// yearly rate X at which the payments, discounted by (1+X)^-t, add up to the
+// borrowed amount, where t is the time in years (days / 365) between the
+// drawdown and each payment. Payments fall monthly from the first repayment
+// date, on the same day of the month or the last day of shorter months.
+package offermath
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/lielamurs/aggregator/internal/money"
+)
+
+var ErrInvalidLoan = errors.New("invalid loan")
+
+const (
+	daysPerYear = 365
+
+	solverIterations = 200
+	solverPrecision  = 1e-12
+)
+
+// Loan is an offer reduced to its cash flows: the principal is drawn on
+// DrawdownDate and repaid in Payments equal monthly instalments.
+type Loan struct {
+	Principal          money.Money
+	Payments           int
+	MonthlyPayment     money.Money
+	DrawdownDate       time.Time
+	FirstRepaymentDate time.Time
+}
+
+// Installment is one row of an amortization schedule. Interest and
+// principal add up to the payment; Balance is what is left afterwards.
+type Installment struct {
+	Number    int
+	Date      time.Time
+	Payment   money.Money
+	Interest  money.Money
+	Principal money.Money
+	Balance   money.Money
+}
+
+type Schedule struct {
+	Principal            money.Money
+	AnnualPercentageRate float64
+	Installments         []Installment
+	TotalInterest        money.Money
+	TotalRepayment       money.Money
+}
+
+func (l Loan) validate() error {
+	switch {
+	case !l.Principal.IsPositive():
+		return fmt.Errorf("%w: principal must be positive", ErrInvalidLoan)
+	case l.Payments <= 0:
+		return fmt.Errorf("%w: number of payments must be positive", ErrInvalidLoan)
+	case !l.MonthlyPayment.IsPositive():
+		return fmt.Errorf("%w: monthly payment must be positive", ErrInvalidLoan)
+	case !l.FirstRepaymentDate.After(l.DrawdownDate):
+		return fmt.Errorf("%w: first repayment date must be after the drawdown date", ErrInvalidLoan)
+	}
+	return nil
+}
+
+// PaymentDates returns the due date of every instalment.
+func (l Loan) PaymentDates() []time.Time {
+	dates := make([]time.Time, l.Payments)
+	for i := range dates {
+		dates[i] = addMonths(l.FirstRepaymentDate, i)
+	}
+	return dates
+}
+
+// APR returns the annual percentage rate of the loan in percent.
+func APR(loan Loan) (float64, error) {
+	if err := loan.validate(); err != nil {
+		return 0, err
+	}
+
+	years := loan.paymentYears()
+	principal := loan.Principal.Float64()
+	payment := loan.MonthlyPayment.Float64()
+	presentValue := func(rate float64) float64 {
+		sum := 0.0
+		for _, t := range years {
+			sum += payment * math.Pow(1+rate, -t)
+		}
+		return sum - principal
+	}
+
+	// presentValue falls as the rate rises, so the root is bracketed by
+	// widening the upper bound and then found by bisection.
+	low, high := -0.9999, 1.0
+	for presentValue(high) > 0 {
+		high *= 2
+		if high > 1e6 {
+			return 0, fmt.Errorf("%w: annual percentage rate out of range", ErrInvalidLoan)
+		}
+	}
+	if presentValue(low) < 0 {
+		return 0, fmt.Errorf("%w: annual percentage rate out of range", ErrInvalidLoan)
+	}
+
+	for range solverIterations {
+		mid := (low + high) / 2
+		if presentValue(mid) > 0 {
+			low = mid
+		} else {
+			high = mid
+		}
+		if high-low < solverPrecision {
+			break
+		}
+	}
+
+	return (low + high) / 2 * 100, nil
+}
+
+// Amortize splits every payment into interest and principal, accruing
+// interest at the loan's annual percentage rate for the days since the
+// previous payment. Interest is rounded to cents half up; the last payment
+// absorbs the rounding so the balance ends at zero.
+func Amortize(loan Loan) (*Schedule, error) {
+	apr, err := APR(loan)
+	if err != nil {
+		return nil, err
+	}
+
+	rate := apr / 100
+	dates := loan.PaymentDates()
+	currency := loan.Principal.Currency()
+	schedule := &Schedule{
+		Principal:            loan.Principal,
+		AnnualPercentageRate: RoundCents(apr),
+		Installments:         make([]Installment, len(dates)),
+		TotalInterest:        money.New(0, currency),
+		TotalRepayment:       money.New(0, currency),
+	}
+
+	balance := loan.Principal
+	previous := loan.DrawdownDate
+	for i, date := range dates {
+		interest, err := money.FromFloat(balance.Float64()*(math.Pow(1+rate, yearsBetween(previous, date))-1), currency, money.RoundHalfUp)
+		if err != nil {
+			return nil, err
+		}
+
+		payment := loan.MonthlyPayment
+		if i == len(dates)-1 {
+			if payment, err = balance.Add(interest); err != nil {
+				return nil, err
+			}
+		}
+
+		principal, err := payment.Sub(interest)
+		if err != nil {
+			return nil, err
+		}
+		if balance, err = balance.Sub(principal); err != nil {
+			return nil, err
+		}
+
+		schedule.Installments[i] = Installment{
+			Number:    i + 1,
+			Date:      date,
+			Payment:   payment,
+			Interest:  interest,
+			Principal: principal,
+			Balance:   balance,
+		}
+		if schedule.TotalInterest, err = schedule.TotalInterest.Add(interest); err != nil {
+			return nil, err
+		}
+		if schedule.TotalRepayment, err = schedule.TotalRepayment.Add(payment); err != nil {
+			return nil, err
+		}
+		previous = date
+	}
+
+	return schedule, nil
+}
+
+// RoundCents rounds a rate or other non-money figure to two decimal places.
+func RoundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func (l Loan) paymentYears() []float64 {
+	dates := l.PaymentDates()
+	years := make([]float64, len(dates))
+	for i, date := range dates {
+		years[i] = yearsBetween(l.DrawdownDate, date)
+	}
+	return years
+}
+
+func yearsBetween(from, to time.Time) float64 {
+	return to.Sub(from).Hours() / 24 / daysPerYear
+}
+
+// addMonths moves date by months, keeping the day of the month unless the
+// target month is shorter, in which case its last day is used.
+func addMonths(date time.Time, months int) time.Time {
+	year, month, day := date.Date()
+	firstOfMonth := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, date.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), min(day, lastDay),
+		date.Hour(), date.Minute(), date.Second(), date.Nanosecond(), date.Location())
+}
@@ -0,0 +1,174 @@
+package offermath
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lielamurs/aggregator/internal/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func eur(value string) money.Money {
+	amount, err := money.Parse(value, money.EUR)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+func monthlyLoan() Loan {
+	return Loan{
+		Principal:          eur("5000"),
+		Payments:           24,
+		MonthlyPayment:     eur("230.72"),
+		DrawdownDate:       date(2025, 1, 15),
+		FirstRepaymentDate: date(2025, 2, 15),
+	}
+}
+
+func TestAPR(t *testing.T) {
+	tests := []struct {
+		name     string
+		loan     Loan
+		expected float64
+	}{
+		{
+			name: "single payment after a year should match simple interest",
+			loan: Loan{
+				Principal: eur("1000"), Payments: 1, MonthlyPayment: eur("1100"),
+				DrawdownDate: date(2025, 1, 1), FirstRepaymentDate: date(2026, 1, 1),
+			},
+			expected: 10,
+		},
+		{
+			name: "payments adding up to the principal should be interest free",
+			loan: Loan{
+				Principal: eur("1200"), Payments: 12, MonthlyPayment: eur("100"),
+				DrawdownDate: date(2025, 1, 1), FirstRepaymentDate: date(2025, 2, 1),
+			},
+			expected: 0,
+		},
+		{
+			name:     "monthly annuity should discount payments by their day counts",
+			loan:     monthlyLoan(),
+			expected: 10.49,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apr, err := APR(tt.loan)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expected, apr, 0.01)
+		})
+	}
+
+	invalid := []struct {
+		name   string
+		modify func(loan *Loan)
+	}{
+		{name: "zero principal", modify: func(loan *Loan) { loan.Principal = money.Money{} }},
+		{name: "no payments", modify: func(loan *Loan) { loan.Payments = 0 }},
+		{name: "zero monthly payment", modify: func(loan *Loan) { loan.MonthlyPayment = money.Money{} }},
+		{name: "first repayment before drawdown", modify: func(loan *Loan) { loan.FirstRepaymentDate = date(2025, 1, 1) }},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name+" should be rejected", func(t *testing.T) {
+			loan := monthlyLoan()
+			tt.modify(&loan)
+
+			_, err := APR(loan)
+			assert.ErrorIs(t, err, ErrInvalidLoan)
+		})
+	}
+}
+
+func TestAmortize(t *testing.T) {
+	loan := monthlyLoan()
+
+	schedule, err := Amortize(loan)
+	require.NoError(t, err)
+	require.Len(t, schedule.Installments, loan.Payments)
+
+	first := schedule.Installments[0]
+	assert.Equal(t, 1, first.Number)
+	assert.Equal(t, date(2025, 2, 15), first.Date)
+	assert.Equal(t, loan.MonthlyPayment, first.Payment)
+	assert.Equal(t, first.Payment.Cents(), first.Interest.Cents()+first.Principal.Cents())
+	assert.Equal(t, loan.Principal.Cents()-first.Principal.Cents(), first.Balance.Cents())
+
+	var repaid int64
+	for _, installment := range schedule.Installments {
+		repaid += installment.Principal.Cents()
+	}
+	assert.Equal(t, loan.Principal.Cents(), repaid)
+
+	last := schedule.Installments[len(schedule.Installments)-1]
+	assert.Equal(t, date(2027, 1, 15), last.Date)
+	assert.True(t, last.Balance.IsZero())
+	assert.InDelta(t, loan.MonthlyPayment.Cents(), last.Payment.Cents(), 5)
+	assert.InDelta(t, loan.MonthlyPayment.Mul(int64(loan.Payments)).Cents(), schedule.TotalRepayment.Cents(), 5)
+	assert.Equal(t, schedule.TotalRepayment.Cents()-loan.Principal.Cents(), schedule.TotalInterest.Cents())
+}
+
+func TestLoan_PaymentDates(t *testing.T) {
+	loan := Loan{Payments: 4, FirstRepaymentDate: date(2024, 11, 30)}
+
+	assert.Equal(t, []time.Time{
+		date(2024, 11, 30),
+		date(2024, 12, 30),
+		date(2025, 1, 30),
+		date(2025, 2, 28),
+	}, loan.PaymentDates())
+}
+
+func TestVerify(t *testing.T) {
+	loan := monthlyLoan()
+	tolerance := Tolerance{APR: 0.1, Amount: eur("1")}
+
+	tests := []struct {
+		name                 string
+		statedAPR            *float64
+		statedTotalRepayment *money.Money
+		expected             []Discrepancy
+	}{
+		{
+			name:                 "matching figures should not be flagged",
+			statedAPR:            &[]float64{10.5}[0],
+			statedTotalRepayment: &[]money.Money{eur("5537.28")}[0],
+			expected:             []Discrepancy{},
+		},
+		{
+			name:                 "understated APR should be flagged",
+			statedAPR:            &[]float64{9.9}[0],
+			statedTotalRepayment: &[]money.Money{eur("5537.28")}[0],
+			expected:             []Discrepancy{DiscrepancyAPR},
+		},
+		{
+			name:                 "wrong total repayment should be flagged",
+			statedAPR:            &[]float64{10.49}[0],
+			statedTotalRepayment: &[]money.Money{eur("5400")}[0],
+			expected:             []Discrepancy{DiscrepancyTotalRepayment},
+		},
+		{
+			name:     "missing figures should not be flagged",
+			expected: []Discrepancy{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verification, err := Verify(loan, tt.statedAPR, tt.statedTotalRepayment, tolerance)
+			require.NoError(t, err)
+			assert.InDelta(t, 10.49, verification.AnnualPercentageRate, 0.01)
+			assert.Equal(t, eur("5537.28"), verification.TotalRepayment)
+			assert.Equal(t, tt.expected, verification.Discrepancies)
+		})
+	}
+}
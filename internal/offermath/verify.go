@@ -0,0 +1,85 @@
+package offermath
+
+import (
+	"math"
+	"time"
+
+	"github.com/lielamurs/aggregator/internal/money"
+)
+
+// DateLayout is the format banks use for the first repayment date.
+const DateLayout = "2006-01-02"
+
+type Discrepancy string
+
+const (
+	DiscrepancyAPR            Discrepancy = "APR_MISMATCH"
+	DiscrepancyTotalRepayment Discrepancy = "TOTAL_REPAYMENT_MISMATCH"
+)
+
+// Tolerance is how far stated figures may be from the recomputed ones: APR
+// in percentage points and amounts in the loan's currency.
+type Tolerance struct {
+	APR    float64
+	Amount money.Money
+}
+
+// Verification holds the recomputed figures of an offer and the stated
+// figures that deviate from them beyond the tolerance.
+type Verification struct {
+	AnnualPercentageRate float64
+	TotalRepayment       money.Money
+	Discrepancies        []Discrepancy
+}
+
+// Verify recomputes the APR and total repayment of the loan and compares
+// them with the figures the bank stated. Missing stated figures are not
+// flagged.
+func Verify(loan Loan, statedAPR *float64, statedTotalRepayment *money.Money, tolerance Tolerance) (*Verification, error) {
+	apr, err := APR(loan)
+	if err != nil {
+		return nil, err
+	}
+
+	verification := &Verification{
+		AnnualPercentageRate: RoundCents(apr),
+		TotalRepayment:       loan.MonthlyPayment.Mul(int64(loan.Payments)),
+		Discrepancies:        []Discrepancy{},
+	}
+	if statedAPR != nil && math.Abs(*statedAPR-apr) > tolerance.APR {
+		verification.Discrepancies = append(verification.Discrepancies, DiscrepancyAPR)
+	}
+	if statedTotalRepayment != nil {
+		difference, err := statedTotalRepayment.Sub(verification.TotalRepayment)
+		if err != nil {
+			return nil, err
+		}
+		beyond, err := abs(difference).Cmp(tolerance.Amount)
+		if err != nil {
+			return nil, err
+		}
+		if beyond > 0 {
+			verification.Discrepancies = append(verification.Discrepancies, DiscrepancyTotalRepayment)
+		}
+	}
+	return verification, nil
+}
+
+func abs(amount money.Money) money.Money {
+	if amount.IsNegative() {
+		return amount.Mul(-1)
+	}
+	return amount
+}
+
+// ParseDate parses a first repayment date as midnight UTC.
+func ParseDate(value string) (time.Time, error) {
+	return time.Parse(DateLayout, value)
+}
+
+// StartOfDay truncates t to midnight UTC of its calendar day, so day counts
+// between a timestamp and a parsed date are whole.
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
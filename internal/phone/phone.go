@@ -0,0 +1,83 @@
+// Package phone normalizes customer phone numbers to E.164, the
+// international format banks expect: a plus sign followed by the country
+// code and the subscriber number, at most 15 digits in total.
+package phone
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidNumber     = errors.New("invalid phone number")
+	ErrCountryNotAllowed = errors.New("phone number country code not allowed")
+)
+
+const (
+	minDigits = 8
+	maxDigits = 15
+)
+
+// Normalizer converts phone numbers to E.164. Numbers may contain spaces,
+// hyphens, dots and parentheses, and may start with "+" or "00". Numbers
+// without either are national numbers: a leading trunk prefix "0" is dropped
+// and DefaultCountryCode is prepended. National numbers are invalid when
+// DefaultCountryCode is empty. An empty AllowedCountryCodes accepts every
+// country code.
+type Normalizer struct {
+	AllowedCountryCodes []string
+	DefaultCountryCode  string
+}
+
+func (n Normalizer) Normalize(number string) (string, error) {
+	number = strings.TrimSpace(number)
+
+	var international bool
+	switch {
+	case strings.HasPrefix(number, "+"):
+		number, international = number[1:], true
+	case strings.HasPrefix(number, "00"):
+		number, international = number[2:], true
+	}
+
+	var digits strings.Builder
+	for _, r := range number {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case strings.ContainsRune(" -.()", r):
+		default:
+			return "", ErrInvalidNumber
+		}
+	}
+
+	normalized := digits.String()
+	if !international {
+		if n.DefaultCountryCode == "" {
+			return "", ErrInvalidNumber
+		}
+		normalized = n.DefaultCountryCode + strings.TrimPrefix(normalized, "0")
+	}
+
+	if len(normalized) < minDigits || len(normalized) > maxDigits || normalized[0] == '0' {
+		return "", ErrInvalidNumber
+	}
+
+	if !n.countryAllowed(normalized) {
+		return "", ErrCountryNotAllowed
+	}
+
+	return "+" + normalized, nil
+}
+
+func (n Normalizer) countryAllowed(digits string) bool {
+	if len(n.AllowedCountryCodes) == 0 {
+		return true
+	}
+	for _, code := range n.AllowedCountryCodes {
+		if strings.HasPrefix(digits, code) {
+			return true
+		}
+	}
+	return false
+}
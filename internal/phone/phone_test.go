@@ -0,0 +1,71 @@
+package phone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizer_Normalize(t *testing.T) {
+	normalizer := Normalizer{AllowedCountryCodes: []string{"371", "370"}, DefaultCountryCode: "371"}
+
+	valid := []struct {
+		input    string
+		expected string
+	}{
+		{input: "+37126000000", expected: "+37126000000"},
+		{input: "+371 26 000 000", expected: "+37126000000"},
+		{input: "00371-26-000-000", expected: "+37126000000"},
+		{input: "+370 (612) 345.67", expected: "+37061234567"},
+		{input: "26000000", expected: "+37126000000"},
+		{input: " 26 000 000 ", expected: "+37126000000"},
+		{input: "026000000", expected: "+37126000000"},
+		{input: "0 26 000 000", expected: "+37126000000"},
+	}
+	for _, tt := range valid {
+		t.Run(tt.input+" should be normalized", func(t *testing.T) {
+			normalized, err := normalizer.Normalize(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, normalized)
+		})
+	}
+
+	invalid := []struct {
+		input    string
+		expected error
+	}{
+		{input: "", expected: ErrInvalidNumber},
+		{input: "invalid-phone", expected: ErrInvalidNumber},
+		{input: "+371 26", expected: ErrInvalidNumber},
+		{input: "+3712600000000000", expected: ErrInvalidNumber},
+		{input: "+0371260000", expected: ErrInvalidNumber},
+		{input: "0", expected: ErrInvalidNumber},
+		{input: "0260", expected: ErrInvalidNumber},
+		{input: "+37126000000 ext 1", expected: ErrInvalidNumber},
+		{input: "+4915112345678", expected: ErrCountryNotAllowed},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.input+" should be rejected", func(t *testing.T) {
+			_, err := normalizer.Normalize(tt.input)
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+
+	t.Run("national numbers without a default country code should be rejected", func(t *testing.T) {
+		_, err := Normalizer{}.Normalize("26000000")
+		assert.ErrorIs(t, err, ErrInvalidNumber)
+	})
+
+	t.Run("trunk prefix should be dropped for the default country code", func(t *testing.T) {
+		normalized, err := Normalizer{DefaultCountryCode: "370"}.Normalize("(0612) 34567")
+		require.NoError(t, err)
+		assert.Equal(t, "+37061234567", normalized)
+	})
+
+	t.Run("any country code should be accepted without an allowed list", func(t *testing.T) {
+		normalized, err := Normalizer{}.Normalize("+49 151 1234 5678")
+		require.NoError(t, err)
+		assert.Equal(t, "+4915112345678", normalized)
+	})
+}
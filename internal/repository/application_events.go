@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/models"
+	"gorm.io/gorm"
+)
+
+type ApplicationEventsRepository struct {
+	db *gorm.DB
+}
+
+func NewApplicationEventsRepository(db *gorm.DB) *ApplicationEventsRepository {
+	return &ApplicationEventsRepository{
+		db: db,
+	}
+}
+
+func (r *ApplicationEventsRepository) Create(ctx context.Context, event *models.ApplicationEvent) error {
+	return conn(ctx, r.db).Create(event).Error
+}
+
+func (r *ApplicationEventsRepository) GetByApplicationID(ctx context.Context, applicationID uuid.UUID) ([]models.ApplicationEvent, error) {
+	var events []models.ApplicationEvent
+	err := conn(ctx, r.db).Where("application_id = ?", applicationID).Order("created_at, id").Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// NotifyUpdated announces a change to the application to every replica
+// listening on ApplicationUpdatesChannel. Inside a transaction the
+// notification is only delivered once it commits.
+func (r *ApplicationEventsRepository) NotifyUpdated(ctx context.Context, applicationID uuid.UUID) error {
+	return conn(ctx, r.db).Exec("SELECT pg_notify(?, ?)", ApplicationUpdatesChannel, applicationID.String()).Error
+}
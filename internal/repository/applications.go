@@ -2,10 +2,14 @@ package repository
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
 	"github.com/lielamurs/aggregator/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ApplicationsRepository struct {
@@ -19,12 +23,15 @@ func NewApplicationsRepository(db *gorm.DB) *ApplicationsRepository {
 }
 
 func (r *ApplicationsRepository) Create(ctx context.Context, app *models.Application) error {
-	return r.db.WithContext(ctx).Create(app).Error
+	return conn(ctx, r.db).Create(app).Error
 }
 
 func (r *ApplicationsRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Application, error) {
 	var app models.Application
-	err := r.db.WithContext(ctx).Preload("Offers").Preload("BankSubmissions").First(&app, "id = ?", id).Error
+	err := conn(ctx, r.db).
+		Preload("Offers", func(db *gorm.DB) *gorm.DB { return db.Order("created_at, id") }).
+		Preload("BankSubmissions", func(db *gorm.DB) *gorm.DB { return db.Order("created_at, id") }).
+		First(&app, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -32,23 +39,186 @@ func (r *ApplicationsRepository) GetByID(ctx context.Context, id uuid.UUID) (*mo
 }
 
 func (r *ApplicationsRepository) Update(ctx context.Context, app *models.Application) error {
-	return r.db.WithContext(ctx).Save(app).Error
+	return conn(ctx, r.db).Save(app).Error
+}
+
+// UpdateStatus changes the status only if it still equals from, reporting
+// whether the row was updated.
+func (r *ApplicationsRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from, to string) (bool, error) {
+	result := conn(ctx, r.db).Model(&models.Application{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]any{
+			"status":     to,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// LockStatus locks the application row until the surrounding transaction
+// ends and returns its status.
+func (r *ApplicationsRepository) LockStatus(ctx context.Context, id uuid.UUID) (dto.ApplicationStatus, error) {
+	var app models.Application
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "status").
+		First(&app, "id = ?", id).Error
+	if err != nil {
+		return "", err
+	}
+	return dto.ApplicationStatus(app.Status), nil
+}
+
+// LockCustomer takes transaction-scoped advisory locks on the phone number,
+// the lower-cased email address and, when known, the client IP, serializing
+// applications from the same customer or address until the surrounding
+// transaction ends. The locks are always taken in that order so two
+// customers sharing some of them cannot deadlock.
+func (r *ApplicationsRepository) LockCustomer(ctx context.Context, phone, email, clientIP string) error {
+	keys := []string{"phone:" + phone, "email:" + strings.ToLower(email)}
+	if clientIP != "" {
+		keys = append(keys, "ip:"+clientIP)
+	}
+
+	for _, key := range keys {
+		if err := conn(ctx, r.db).Exec("SELECT pg_advisory_xact_lock(hashtext(?))", key).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (r *ApplicationsRepository) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&models.Application{}).Where("id = ?", id).Count(&count).Error
+	err := conn(ctx, r.db).Model(&models.Application{}).Where("id = ?", id).Count(&count).Error
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
-func (r *ApplicationsRepository) GetProcessingApplicationsWithBankSubmissions(ctx context.Context) ([]models.Application, error) {
+// GetSettledApplications returns the ID and status of active applications
+// that have no submission job or bank submission left in flight. Pending
+// applications are only settled once they have jobs, which then all failed
+// before reaching a bank.
+func (r *ApplicationsRepository) GetSettledApplications(ctx context.Context) ([]models.Application, error) {
+	var apps []models.Application
+	err := conn(ctx, r.db).Select("id", "status").
+		Where("status = ? OR (status = ? AND EXISTS (SELECT 1 FROM submission_jobs sj WHERE sj.application_id = applications.id))",
+			dto.StatusProcessing, dto.StatusPending).
+		Where("NOT EXISTS (SELECT 1 FROM bank_submissions bs WHERE bs.application_id = applications.id AND bs.status IN ?)",
+			[]dto.BankSubmissionStatus{dto.SubmissionStatusDraft, dto.SubmissionStatusRetry}).
+		Where("NOT EXISTS (SELECT 1 FROM submission_jobs sj WHERE sj.application_id = applications.id AND sj.status IN ?)",
+			[]dto.SubmissionJobStatus{dto.JobStatusPending, dto.JobStatusRunning}).
+		Find(&apps).Error
+	if err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
+
+// GetActiveApplicationsCreatedBefore returns the ID and status of pending and
+// processing applications created before the given time.
+func (r *ApplicationsRepository) GetActiveApplicationsCreatedBefore(ctx context.Context, before time.Time) ([]models.Application, error) {
+	var apps []models.Application
+	err := conn(ctx, r.db).Select("id", "status").
+		Where("status IN ? AND created_at < ?", []dto.ApplicationStatus{dto.StatusPending, dto.StatusProcessing}, before).
+		Find(&apps).Error
+	if err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
+
+type ApplicationFilter struct {
+	Status           string
+	Email            string
+	Phone            string
+	ClientIP         string
+	CreatedFrom      *time.Time
+	CreatedTo        *time.Time
+	BankName         string
+	SubmissionStatus string
+	AfterCreatedAt   *time.Time
+	AfterID          *uuid.UUID
+}
+
+// Search returns up to limit applications matching the filter, newest first.
+// AfterCreatedAt and AfterID continue the listing after a previous page.
+func (r *ApplicationsRepository) Search(ctx context.Context, filter ApplicationFilter, limit int) ([]models.Application, error) {
+	query := r.applyFilter(conn(ctx, r.db).Preload("BankSubmissions"), filter)
+
 	var apps []models.Application
-	err := r.db.WithContext(ctx).Preload("BankSubmissions").Where("status = ?", "PROCESSING").Find(&apps).Error
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&apps).Error
 	if err != nil {
 		return nil, err
 	}
 	return apps, nil
 }
+
+// Count returns the number of applications matching the filter.
+func (r *ApplicationsRepository) Count(ctx context.Context, filter ApplicationFilter) (int64, error) {
+	var count int64
+	err := r.applyFilter(conn(ctx, r.db).Model(&models.Application{}), filter).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// FindInProgressDuplicate returns the newest pending or processing
+// application from the same phone and email asking for the same loan, or nil
+// if there is none.
+func (r *ApplicationsRepository) FindInProgressDuplicate(ctx context.Context, app *models.Application) (*models.Application, error) {
+	var apps []models.Application
+	err := conn(ctx, r.db).
+		Where("status IN ?", []dto.ApplicationStatus{dto.StatusPending, dto.StatusProcessing}).
+		Where("phone = ? AND lower(email) = lower(?)", app.Phone, app.Email).
+		Where("amount = ? AND currency = ?", app.Amount, app.Currency).
+		Where("term_months IS NOT DISTINCT FROM ? AND purpose IS NOT DISTINCT FROM ?", app.TermMonths, app.Purpose).
+		Order("created_at DESC, id DESC").
+		Limit(1).
+		Find(&apps).Error
+	if err != nil || len(apps) == 0 {
+		return nil, err
+	}
+	return &apps[0], nil
+}
+
+func (r *ApplicationsRepository) applyFilter(query *gorm.DB, filter ApplicationFilter) *gorm.DB {
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Email != "" {
+		query = query.Where("lower(email) = lower(?)", filter.Email)
+	}
+	if filter.Phone != "" {
+		query = query.Where("phone = ?", filter.Phone)
+	}
+	if filter.ClientIP != "" {
+		query = query.Where("client_ip = ?", filter.ClientIP)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filter.CreatedTo)
+	}
+
+	if filter.BankName != "" || filter.SubmissionStatus != "" {
+		submissions := r.db.Table("bank_submissions bs").Select("1").Where("bs.application_id = applications.id")
+		if filter.BankName != "" {
+			submissions = submissions.Where("bs.bank_name = ?", filter.BankName)
+		}
+		if filter.SubmissionStatus != "" {
+			submissions = submissions.Where("bs.status = ?", filter.SubmissionStatus)
+		}
+		query = query.Where("EXISTS (?)", submissions)
+	}
+
+	if filter.AfterCreatedAt != nil && filter.AfterID != nil {
+		query = query.Where("(created_at, id) < (?, ?)", *filter.AfterCreatedAt, *filter.AfterID)
+	}
+	return query
+}
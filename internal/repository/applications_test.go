@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/money"
+	"github.com/lielamurs/aggregator/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func eur(value string) money.Money {
+	return money.MustParse(value, money.EUR)
+}
+
+func createSearchApplication(t *testing.T, db *gorm.DB, email string, status dto.ApplicationStatus, createdAt time.Time, submissions ...models.BankSubmission) uuid.UUID {
+	t.Helper()
+
+	app := &models.Application{
+		ID:              uuid.New(),
+		Phone:           "+37120000000",
+		Email:           email,
+		MonthlyIncome:   eur("2000"),
+		MonthlyExpenses: eur("500"),
+		MaritalStatus:   "SINGLE",
+		AgreeToBeScored: true,
+		Amount:          eur("5000"),
+		Status:          string(status),
+		CreatedAt:       createdAt,
+		UpdatedAt:       createdAt,
+	}
+	require.NoError(t, db.Create(app).Error)
+
+	for _, submission := range submissions {
+		submission.ID = uuid.New()
+		submission.ApplicationID = app.ID
+		require.NoError(t, db.Create(&submission).Error)
+	}
+
+	return app.ID
+}
+
+func TestApplicationsRepository_Search(t *testing.T) {
+	db := testutil.OpenPostgres(t)
+	repo := NewApplicationsRepository(db)
+	ctx := context.Background()
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	older := createSearchApplication(t, db, "older@example.com", dto.StatusCompleted, base,
+		models.BankSubmission{BankName: "SolidBank", Status: string(dto.SubmissionStatusSuccess)})
+	middle := createSearchApplication(t, db, "Middle@Example.com", dto.StatusProcessing, base.Add(time.Minute),
+		models.BankSubmission{BankName: "FastBank", Status: string(dto.SubmissionStatusDraft)},
+		models.BankSubmission{BankName: "SolidBank", Status: string(dto.SubmissionStatusFailed)})
+	newest := createSearchApplication(t, db, "newest@example.com", dto.StatusProcessing, base.Add(2*time.Minute),
+		models.BankSubmission{BankName: "SolidBank", Status: string(dto.SubmissionStatusDraft)})
+
+	ids := func(apps []models.Application) []uuid.UUID {
+		result := make([]uuid.UUID, len(apps))
+		for i, app := range apps {
+			result[i] = app.ID
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		filter   ApplicationFilter
+		expected []uuid.UUID
+	}{
+		{
+			name:     "no filter should list newest first",
+			expected: []uuid.UUID{newest, middle, older},
+		},
+		{
+			name:     "status filter",
+			filter:   ApplicationFilter{Status: string(dto.StatusProcessing)},
+			expected: []uuid.UUID{newest, middle},
+		},
+		{
+			name:     "email filter should ignore case",
+			filter:   ApplicationFilter{Email: "middle@example.com"},
+			expected: []uuid.UUID{middle},
+		},
+		{
+			name:     "created range should include from and exclude to",
+			filter:   ApplicationFilter{CreatedFrom: &base, CreatedTo: &[]time.Time{base.Add(2 * time.Minute)}[0]},
+			expected: []uuid.UUID{middle, older},
+		},
+		{
+			name:     "bank name filter",
+			filter:   ApplicationFilter{BankName: "FastBank"},
+			expected: []uuid.UUID{middle},
+		},
+		{
+			name:     "bank name and submission status should match the same submission",
+			filter:   ApplicationFilter{BankName: "SolidBank", SubmissionStatus: string(dto.SubmissionStatusDraft)},
+			expected: []uuid.UUID{newest},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apps, err := repo.Search(ctx, tt.filter, 10)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ids(apps))
+		})
+	}
+
+	t.Run("keyset should continue after the previous page", func(t *testing.T) {
+		firstPage, err := repo.Search(ctx, ApplicationFilter{}, 2)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{newest, middle}, ids(firstPage))
+		assert.Len(t, firstPage[1].BankSubmissions, 2)
+
+		last := firstPage[1]
+		secondPage, err := repo.Search(ctx, ApplicationFilter{AfterCreatedAt: &last.CreatedAt, AfterID: &last.ID}, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{older}, ids(secondPage))
+	})
+}
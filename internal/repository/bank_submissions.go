@@ -2,9 +2,13 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
 	"github.com/lielamurs/aggregator/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BankSubmissionsRepository struct {
@@ -18,9 +22,139 @@ func NewBankSubmissionsRepository(db *gorm.DB) *BankSubmissionsRepository {
 }
 
 func (r *BankSubmissionsRepository) Create(ctx context.Context, submission *models.BankSubmission) error {
-	return r.db.WithContext(ctx).Create(submission).Error
+	return conn(ctx, r.db).Create(submission).Error
 }
 
 func (r *BankSubmissionsRepository) Update(ctx context.Context, submission *models.BankSubmission) error {
-	return r.db.WithContext(ctx).Save(submission).Error
+	return conn(ctx, r.db).Save(submission).Error
+}
+
+func (r *BankSubmissionsRepository) GetByApplicationAndBank(ctx context.Context, applicationID uuid.UUID, bankName string) (*models.BankSubmission, error) {
+	var submission models.BankSubmission
+	err := conn(ctx, r.db).Where("application_id = ? AND bank_name = ?", applicationID, bankName).First(&submission).Error
+	if err != nil {
+		return nil, err
+	}
+	return &submission, nil
+}
+
+func (r *BankSubmissionsRepository) GetByApplicationID(ctx context.Context, applicationID uuid.UUID) ([]models.BankSubmission, error) {
+	var submissions []models.BankSubmission
+	err := conn(ctx, r.db).Where("application_id = ?", applicationID).Order("created_at").Find(&submissions).Error
+	if err != nil {
+		return nil, err
+	}
+	return submissions, nil
+}
+
+// AllFailed reports whether no bank decided on the application: every
+// submission failed, timed out or was cancelled. Submissions skipped as
+// ineligible are left out, but an application every bank skipped has not
+// failed, and neither has one without submissions.
+func (r *BankSubmissionsRepository) AllFailed(ctx context.Context, applicationID uuid.UUID) (bool, error) {
+	var statuses []string
+	err := conn(ctx, r.db).Model(&models.BankSubmission{}).
+		Where("application_id = ?", applicationID).
+		Pluck("status", &statuses).Error
+	if err != nil {
+		return false, err
+	}
+
+	skipped := 0
+	for _, status := range statuses {
+		switch dto.BankSubmissionStatus(status) {
+		case dto.SubmissionStatusFailed, dto.SubmissionStatusTimedOut, dto.SubmissionStatusCancelled:
+		case dto.SubmissionStatusSkipped:
+			skipped++
+		default:
+			return false, nil
+		}
+	}
+	return skipped < len(statuses), nil
+}
+
+// ClaimDue leases up to limit submissions of active applications whose next
+// poll or retry is due.
+// Rows locked by another replica are skipped and an expired lease can be
+// taken over, so each submission is processed by one replica at a time.
+func (r *BankSubmissionsRepository) ClaimDue(ctx context.Context, workerID string, limit int, lease time.Duration) ([]models.BankSubmission, error) {
+	var submissions []models.BankSubmission
+	now := time.Now()
+	lockedUntil := now.Add(lease)
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ?", []dto.BankSubmissionStatus{dto.SubmissionStatusDraft, dto.SubmissionStatusRetry}).
+			Where("next_poll_at IS NULL OR next_poll_at <= ?", now).
+			Where("locked_until IS NULL OR locked_until < ?", now).
+			Where("EXISTS (SELECT 1 FROM applications a WHERE a.id = bank_submissions.application_id AND a.status IN ?)",
+				[]dto.ApplicationStatus{dto.StatusPending, dto.StatusProcessing}).
+			Order("next_poll_at NULLS FIRST").
+			Limit(limit).
+			Find(&submissions).Error
+		if err != nil || len(submissions) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(submissions))
+		for i := range submissions {
+			ids[i] = submissions[i].ID
+			submissions[i].LockedBy = &workerID
+			submissions[i].LockedUntil = &lockedUntil
+		}
+
+		return tx.Model(&models.BankSubmission{}).Where("id IN ?", ids).Updates(map[string]any{
+			"locked_by":    workerID,
+			"locked_until": lockedUntil,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return submissions, nil
+}
+
+// Release saves the submission and gives up its lease. It fails with
+// ErrLeaseLost if another replica took the submission over in the meantime.
+func (r *BankSubmissionsRepository) Release(ctx context.Context, submission *models.BankSubmission, workerID string) error {
+	submission.LockedBy = nil
+	submission.LockedUntil = nil
+
+	result := conn(ctx, r.db).Model(submission).Where("locked_by = ?", workerID).Select("*").Updates(submission)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+// Cancel saves the cancelled submission if it is still in the status it was
+// read in, reporting whether it was updated. Any lease is dropped, so a
+// replica processing the submission fails to release it with ErrLeaseLost
+// instead of overwriting the cancellation.
+func (r *BankSubmissionsRepository) Cancel(ctx context.Context, submission *models.BankSubmission, from string) (bool, error) {
+	submission.LockedBy = nil
+	submission.LockedUntil = nil
+
+	result := conn(ctx, r.db).Model(submission).Where("status = ?", from).Select("*").Updates(submission)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateWithdrawal records the outcome of withdrawing the application from
+// the submission's bank.
+func (r *BankSubmissionsRepository) UpdateWithdrawal(ctx context.Context, id uuid.UUID, withdrawnAt *time.Time, withdrawalError *string) error {
+	return conn(ctx, r.db).Model(&models.BankSubmission{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"withdrawn_at":     withdrawnAt,
+			"withdrawal_error": withdrawalError,
+		}).Error
 }
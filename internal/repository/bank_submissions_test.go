@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func createDraftSubmissions(t *testing.T, db *gorm.DB, count int) []models.BankSubmission {
+	t.Helper()
+
+	app := &models.Application{
+		ID:              uuid.New(),
+		Phone:           "+37120000000",
+		Email:           "john@example.com",
+		MonthlyIncome:   eur("2000"),
+		MonthlyExpenses: eur("500"),
+		MaritalStatus:   "SINGLE",
+		AgreeToBeScored: true,
+		Amount:          eur("5000"),
+		Status:          string(dto.StatusProcessing),
+	}
+	require.NoError(t, db.Create(app).Error)
+
+	submissions := make([]models.BankSubmission, count)
+	for i := range submissions {
+		bankID := fmt.Sprintf("bank-%d", i)
+		submissions[i] = models.BankSubmission{
+			ID:            uuid.New(),
+			ApplicationID: app.ID,
+			BankName:      fmt.Sprintf("Bank%d", i),
+			Status:        string(dto.SubmissionStatusDraft),
+			BankID:        &bankID,
+		}
+	}
+	require.NoError(t, db.Create(&submissions).Error)
+
+	return submissions
+}
+
+func TestBankSubmissionsRepository_ClaimDue(t *testing.T) {
+	db := testutil.OpenPostgres(t)
+	repo := NewBankSubmissionsRepository(db)
+	ctx := context.Background()
+
+	t.Run("concurrent claims should never hand out a submission twice", func(t *testing.T) {
+		require.NoError(t, db.Exec("TRUNCATE applications CASCADE").Error)
+		createDraftSubmissions(t, db, 50)
+
+		var mu sync.Mutex
+		claimed := make(map[uuid.UUID]string)
+
+		var wg sync.WaitGroup
+		for w := 0; w < 8; w++ {
+			wg.Add(1)
+			go func(workerID string) {
+				defer wg.Done()
+				for {
+					submissions, err := repo.ClaimDue(ctx, workerID, 5, time.Minute)
+					if !assert.NoError(t, err) || len(submissions) == 0 {
+						return
+					}
+
+					mu.Lock()
+					for _, submission := range submissions {
+						owner, exists := claimed[submission.ID]
+						assert.False(t, exists, "submission %s claimed by %s and %s", submission.ID, owner, workerID)
+						claimed[submission.ID] = workerID
+					}
+					mu.Unlock()
+				}
+			}(fmt.Sprintf("worker-%d", w))
+		}
+		wg.Wait()
+
+		assert.Len(t, claimed, 50)
+	})
+
+	t.Run("expired lease should be taken over and the old owner should lose it", func(t *testing.T) {
+		require.NoError(t, db.Exec("TRUNCATE applications CASCADE").Error)
+		createDraftSubmissions(t, db, 1)
+
+		first, err := repo.ClaimDue(ctx, "worker-1", 10, -time.Second)
+		require.NoError(t, err)
+		require.Len(t, first, 1)
+
+		second, err := repo.ClaimDue(ctx, "worker-2", 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, second, 1)
+
+		none, err := repo.ClaimDue(ctx, "worker-3", 10, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+
+		assert.ErrorIs(t, repo.Release(ctx, &first[0], "worker-1"), ErrLeaseLost)
+		assert.NoError(t, repo.Release(ctx, &second[0], "worker-2"))
+
+		released, err := repo.ClaimDue(ctx, "worker-3", 10, time.Minute)
+		require.NoError(t, err)
+		assert.Len(t, released, 1)
+	})
+
+	t.Run("submissions should only be claimed once their next poll is due", func(t *testing.T) {
+		require.NoError(t, db.Exec("TRUNCATE applications CASCADE").Error)
+		submissions := createDraftSubmissions(t, db, 2)
+
+		nextPollAt := time.Now().Add(time.Hour)
+		require.NoError(t, db.Model(&submissions[1]).Update("next_poll_at", nextPollAt).Error)
+
+		claimed, err := repo.ClaimDue(ctx, "worker-1", 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, submissions[0].ID, claimed[0].ID)
+	})
+}
+
+func TestBankSubmissionsRepository_AllFailed(t *testing.T) {
+	db := testutil.OpenPostgres(t)
+	repo := NewBankSubmissionsRepository(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		statuses []dto.BankSubmissionStatus
+		expected bool
+	}{
+		{name: "every submission failed", statuses: []dto.BankSubmissionStatus{dto.SubmissionStatusFailed, dto.SubmissionStatusFailed}, expected: true},
+		{name: "skipped submissions should not count", statuses: []dto.BankSubmissionStatus{dto.SubmissionStatusFailed, dto.SubmissionStatusSkipped}, expected: true},
+		{name: "every bank skipped", statuses: []dto.BankSubmissionStatus{dto.SubmissionStatusSkipped, dto.SubmissionStatusSkipped}, expected: false},
+		{name: "one bank answered", statuses: []dto.BankSubmissionStatus{dto.SubmissionStatusFailed, dto.SubmissionStatusSuccess}, expected: false},
+		{name: "every bank timed out", statuses: []dto.BankSubmissionStatus{dto.SubmissionStatusTimedOut, dto.SubmissionStatusTimedOut}, expected: true},
+		{name: "no bank decided", statuses: []dto.BankSubmissionStatus{dto.SubmissionStatusTimedOut, dto.SubmissionStatusCancelled, dto.SubmissionStatusFailed}, expected: true},
+		{name: "one bank answered before the others timed out", statuses: []dto.BankSubmissionStatus{dto.SubmissionStatusTimedOut, dto.SubmissionStatusSuccess}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			submissions := createDraftSubmissions(t, db, len(tt.statuses))
+			for i, status := range tt.statuses {
+				require.NoError(t, db.Model(&submissions[i]).Update("status", status).Error)
+			}
+
+			allFailed, err := repo.AllFailed(ctx, submissions[0].ApplicationID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, allFailed)
+		})
+	}
+
+	t.Run("application without submissions", func(t *testing.T) {
+		allFailed, err := repo.AllFailed(ctx, uuid.New())
+		require.NoError(t, err)
+		assert.False(t, allFailed)
+	})
+}
@@ -18,9 +18,6 @@ type DB struct {
 }
 
 func NewConnection(cfg config.DatabaseConfig, appLogger *logrus.Logger) (*DB, error) {
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode)
-
 	gormLogger := logger.New(
 		appLogger,
 		logger.Config{
@@ -31,7 +28,7 @@ func NewConnection(cfg config.DatabaseConfig, appLogger *logrus.Logger) (*DB, er
 		},
 	)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+	db, err := gorm.Open(postgres.Open(dataSourceName(cfg)), &gorm.Config{
 		Logger: gormLogger,
 	})
 	if err != nil {
@@ -79,3 +76,8 @@ func (db *DB) HealthCheck(ctx context.Context) error {
 
 	return nil
 }
+
+func dataSourceName(cfg config.DatabaseConfig) string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode)
+}
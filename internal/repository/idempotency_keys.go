@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyKeysRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyKeysRepository(db *gorm.DB) *IdempotencyKeysRepository {
+	return &IdempotencyKeysRepository{
+		db: db,
+	}
+}
+
+// Create stores the key unless it already exists, reporting whether it was
+// stored.
+func (r *IdempotencyKeysRepository) Create(ctx context.Context, key *models.IdempotencyKey) (bool, error) {
+	result := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *IdempotencyKeysRepository) Get(ctx context.Context, key string) (*models.IdempotencyKey, error) {
+	var idempotencyKey models.IdempotencyKey
+	err := conn(ctx, r.db).First(&idempotencyKey, "key = ?", key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &idempotencyKey, nil
+}
+
+// Relock takes over an in-progress key whose lock expired, e.g. because the
+// instance serving the original request crashed.
+func (r *IdempotencyKeysRepository) Relock(ctx context.Context, key, lockedBy string, lockedUntil time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&models.IdempotencyKey{}).
+		Where("key = ? AND status = ? AND locked_until < ?", key, dto.IdempotencyKeyInProgress, time.Now()).
+		Updates(map[string]any{
+			"locked_by":    lockedBy,
+			"locked_until": lockedUntil,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Complete stores the response for a key locked by lockedBy. It fails with
+// ErrLeaseLost when the lock expired and another request took the key over.
+func (r *IdempotencyKeysRepository) Complete(ctx context.Context, key, lockedBy string, responseStatus int, responseBody []byte) error {
+	result := conn(ctx, r.db).Model(&models.IdempotencyKey{}).
+		Where("key = ? AND status = ? AND locked_by = ?", key, dto.IdempotencyKeyInProgress, lockedBy).
+		Updates(map[string]any{
+			"status":          dto.IdempotencyKeyCompleted,
+			"response_status": responseStatus,
+			"response_body":   responseBody,
+			"locked_by":       nil,
+			"locked_until":    nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Delete removes an in-progress key locked by lockedBy, failing with
+// ErrLeaseLost when another request took it over.
+func (r *IdempotencyKeysRepository) Delete(ctx context.Context, key, lockedBy string) error {
+	result := conn(ctx, r.db).
+		Where("key = ? AND status = ? AND locked_by = ?", key, dto.IdempotencyKeyInProgress, lockedBy).
+		Delete(&models.IdempotencyKey{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (r *IdempotencyKeysRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	return conn(ctx, r.db).Delete(&models.IdempotencyKey{}, "expires_at < ?", now).Error
+}
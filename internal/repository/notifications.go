@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lielamurs/aggregator/internal/config"
+)
+
+// ApplicationUpdatesChannel is the Postgres notification channel carrying
+// the IDs of applications whose status or offers changed.
+const ApplicationUpdatesChannel = "application_updates"
+
+// NotificationListener receives Postgres notifications on a dedicated
+// connection, since LISTEN cannot share the pooled connections gorm uses.
+type NotificationListener struct {
+	dsn string
+}
+
+func NewNotificationListener(cfg config.DatabaseConfig) *NotificationListener {
+	return &NotificationListener{
+		dsn: dataSourceName(cfg),
+	}
+}
+
+// Listen subscribes to channel and passes every notification payload to
+// onNotify until ctx is cancelled or the connection fails. onListen runs
+// once the subscription is active.
+func (l *NotificationListener) Listen(ctx context.Context, channel string, onListen func(), onNotify func(payload string)) error {
+	conn, err := pgx.Connect(ctx, l.dsn)
+	if err != nil {
+		return fmt.Errorf("failed to connect notification listener: %w", err)
+	}
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", channel, err)
+	}
+
+	onListen()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		onNotify(notification.Payload)
+	}
+}
@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
 	"github.com/lielamurs/aggregator/internal/models"
 	"gorm.io/gorm"
 )
@@ -18,5 +21,69 @@ func NewOffersRepository(db *gorm.DB) *OffersRepository {
 }
 
 func (r *OffersRepository) Create(ctx context.Context, offer *models.Offer) error {
-	return r.db.WithContext(ctx).Create(offer).Error
+	return conn(ctx, r.db).Create(offer).Error
+}
+
+func (r *OffersRepository) UpdateAcceptanceStatus(ctx context.Context, id uuid.UUID, status dto.OfferAcceptanceStatus) error {
+	return conn(ctx, r.db).Model(&models.Offer{}).
+		Where("id = ?", id).
+		Update("acceptance_status", status).Error
+}
+
+// ClaimAcceptance marks the offer PENDING while its acceptance is forwarded
+// to the bank. It only succeeds for an approved offer nobody decided on, and
+// while no other offer of the application is PENDING. Claims made before
+// staleBefore were abandoned by a crashed request and can be taken over.
+// Callers lock the application row first so concurrent claims are
+// serialized.
+func (r *OffersRepository) ClaimAcceptance(ctx context.Context, applicationID, offerID uuid.UUID, staleBefore time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&models.Offer{}).
+		Where("id = ? AND application_id = ? AND status = ?", offerID, applicationID, dto.OfferStatusApproved).
+		Where("acceptance_status IS NULL OR (acceptance_status = ? AND acceptance_claimed_at < ?)", dto.OfferAcceptancePending, staleBefore).
+		Where("NOT EXISTS (?)", r.pendingAcceptances(ctx, applicationID, staleBefore).Where("o.id <> ?", offerID)).
+		Updates(map[string]any{
+			"acceptance_status":     dto.OfferAcceptancePending,
+			"acceptance_claimed_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// HasPendingAcceptance reports whether an acceptance claimed at or after
+// staleBefore is still being forwarded to a bank.
+func (r *OffersRepository) HasPendingAcceptance(ctx context.Context, applicationID uuid.UUID, staleBefore time.Time) (bool, error) {
+	var count int64
+	if err := r.pendingAcceptances(ctx, applicationID, staleBefore).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *OffersRepository) pendingAcceptances(ctx context.Context, applicationID uuid.UUID, staleBefore time.Time) *gorm.DB {
+	return conn(ctx, r.db).Table("offers AS o").Select("1").
+		Where("o.application_id = ? AND o.acceptance_status = ? AND o.acceptance_claimed_at >= ?",
+			applicationID, dto.OfferAcceptancePending, staleBefore)
+}
+
+// ReleaseAcceptance clears the offer's PENDING claim when the bank could not
+// be asked, so the customer can try again.
+func (r *OffersRepository) ReleaseAcceptance(ctx context.Context, id uuid.UUID) error {
+	return conn(ctx, r.db).Model(&models.Offer{}).
+		Where("id = ? AND acceptance_status = ?", id, dto.OfferAcceptancePending).
+		Updates(map[string]any{
+			"acceptance_status":     nil,
+			"acceptance_claimed_at": nil,
+		}).Error
+}
+
+// SupersedeCompetingOffers marks the application's other approved offers the
+// customer has not decided on, including abandoned claims, as superseded by
+// the accepted one.
+func (r *OffersRepository) SupersedeCompetingOffers(ctx context.Context, applicationID, acceptedID uuid.UUID) error {
+	return conn(ctx, r.db).Model(&models.Offer{}).
+		Where("application_id = ? AND id <> ? AND status = ? AND (acceptance_status IS NULL OR acceptance_status = ?)",
+			applicationID, acceptedID, dto.OfferStatusApproved, dto.OfferAcceptancePending).
+		Update("acceptance_status", dto.OfferAcceptanceSuperseded).Error
 }
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrLeaseLost = errors.New("lease lost to another worker")
+
+type SubmissionJobsRepository struct {
+	db *gorm.DB
+}
+
+func NewSubmissionJobsRepository(db *gorm.DB) *SubmissionJobsRepository {
+	return &SubmissionJobsRepository{
+		db: db,
+	}
+}
+
+func (r *SubmissionJobsRepository) Create(ctx context.Context, job *models.SubmissionJob) error {
+	return conn(ctx, r.db).Create(job).Error
+}
+
+// ClaimDue leases up to limit jobs that are due, including running jobs whose
+// lease expired because their worker died. Rows locked by another claimer are
+// skipped, so concurrent workers never receive the same job.
+func (r *SubmissionJobsRepository) ClaimDue(ctx context.Context, workerID string, limit int, lease time.Duration) ([]models.SubmissionJob, error) {
+	var jobs []models.SubmissionJob
+	now := time.Now()
+	lockedUntil := now.Add(lease)
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?)",
+				dto.JobStatusPending, now, dto.JobStatusRunning, now).
+			Order("run_at").
+			Limit(limit).
+			Find(&jobs).Error
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(jobs))
+		for i := range jobs {
+			ids[i] = jobs[i].ID
+			jobs[i].Status = string(dto.JobStatusRunning)
+			jobs[i].Attempts++
+			jobs[i].LockedBy = &workerID
+			jobs[i].LockedUntil = &lockedUntil
+			jobs[i].UpdatedAt = now
+		}
+
+		return tx.Model(&models.SubmissionJob{}).Where("id IN ?", ids).Updates(map[string]any{
+			"status":       dto.JobStatusRunning,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"locked_by":    workerID,
+			"locked_until": lockedUntil,
+			"updated_at":   now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// Finish moves a job to a terminal status. It only succeeds while the worker
+// still holds the lease.
+func (r *SubmissionJobsRepository) Finish(ctx context.Context, job *models.SubmissionJob, status dto.SubmissionJobStatus, lastError *string) error {
+	return r.release(ctx, job, map[string]any{
+		"status":     status,
+		"last_error": lastError,
+	})
+}
+
+func (r *SubmissionJobsRepository) Reschedule(ctx context.Context, job *models.SubmissionJob, runAt time.Time, lastError string) error {
+	return r.release(ctx, job, map[string]any{
+		"status":     dto.JobStatusPending,
+		"run_at":     runAt,
+		"last_error": lastError,
+	})
+}
+
+// Release returns a claimed job that was never started to the queue, undoing
+// the attempt counted when it was claimed.
+func (r *SubmissionJobsRepository) Release(ctx context.Context, job *models.SubmissionJob) error {
+	return r.release(ctx, job, map[string]any{
+		"status":   dto.JobStatusPending,
+		"attempts": gorm.Expr("attempts - 1"),
+	})
+}
+
+func (r *SubmissionJobsRepository) release(ctx context.Context, job *models.SubmissionJob, updates map[string]any) error {
+	updates["locked_by"] = nil
+	updates["locked_until"] = nil
+	updates["updated_at"] = time.Now()
+
+	var lockedBy string
+	if job.LockedBy != nil {
+		lockedBy = *job.LockedBy
+	}
+
+	result := conn(ctx, r.db).Model(&models.SubmissionJob{}).
+		Where("id = ? AND locked_by = ?", job.ID, lockedBy).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhooksRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhooksRepository(db *gorm.DB) *WebhooksRepository {
+	return &WebhooksRepository{
+		db: db,
+	}
+}
+
+func (r *WebhooksRepository) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	return conn(ctx, r.db).Create(subscription).Error
+}
+
+func (r *WebhooksRepository) GetSubscriptionsByClientID(ctx context.Context, clientID string) ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := conn(ctx, r.db).Where("client_id = ?", clientID).Order("created_at, id").Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// DeleteSubscription removes a subscription owned by the client, reporting
+// whether it existed.
+func (r *WebhooksRepository) DeleteSubscription(ctx context.Context, clientID string, id uuid.UUID) (bool, error) {
+	result := conn(ctx, r.db).Where("id = ? AND client_id = ?", id, clientID).Delete(&models.WebhookSubscription{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CreateEvent stores an outbox event together with its deliveries. Callers
+// run it in the transaction that changes the state the event describes.
+func (r *WebhooksRepository) CreateEvent(ctx context.Context, event *models.WebhookEvent, deliveries []models.WebhookDelivery) error {
+	db := conn(ctx, r.db)
+	if err := db.Create(event).Error; err != nil {
+		return err
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return db.Omit(clause.Associations).Create(&deliveries).Error
+}
+
+func (r *WebhooksRepository) GetEventsByIDs(ctx context.Context, ids []uuid.UUID) ([]models.WebhookEvent, error) {
+	var events []models.WebhookEvent
+	err := conn(ctx, r.db).Where("id IN ?", ids).Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ClaimDueDeliveries leases up to limit pending deliveries whose next attempt
+// is due. Pending rows with an expired lease are claimed again, so deliveries
+// held by a crashed dispatcher are not lost.
+func (r *WebhooksRepository) ClaimDueDeliveries(ctx context.Context, workerID string, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	now := time.Now()
+	lockedUntil := now.Add(lease)
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)", dto.WebhookDeliveryPending, now).
+			Where("locked_until IS NULL OR locked_until < ?", now).
+			Order("next_attempt_at NULLS FIRST, created_at").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(deliveries))
+		for i := range deliveries {
+			ids[i] = deliveries[i].ID
+			deliveries[i].LockedBy = &workerID
+			deliveries[i].LockedUntil = &lockedUntil
+		}
+
+		return tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).Updates(map[string]any{
+			"locked_by":    workerID,
+			"locked_until": lockedUntil,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// ReleaseDelivery saves the outcome of a delivery attempt, logs the attempt
+// and gives up the lease. It fails with ErrLeaseLost when another dispatcher
+// took the delivery over in the meantime.
+func (r *WebhooksRepository) ReleaseDelivery(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookDeliveryAttempt, workerID string) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		delivery.LockedBy = nil
+		delivery.LockedUntil = nil
+		delivery.UpdatedAt = time.Now()
+
+		result := tx.Model(delivery).Omit(clause.Associations).
+			Where("locked_by = ?", workerID).
+			Select("*").
+			Updates(delivery)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrLeaseLost
+		}
+
+		return tx.Create(attempt).Error
+	})
+}
+
+func (r *WebhooksRepository) GetDeliveriesByApplicationID(ctx context.Context, applicationID uuid.UUID) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := conn(ctx, r.db).
+		Preload("AttemptLog", func(db *gorm.DB) *gorm.DB { return db.Order("attempt") }).
+		Where("application_id = ?", applicationID).
+		Order("created_at, id").
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,133 @@
+// Package safeurl keeps requests to customer supplied URLs, such as webhook
+// endpoints, away from the service's own network: only https URLs resolving
+// to public addresses are accepted, and addresses are checked again when
+// connecting so a DNS answer that changes later cannot point a request
+// inside.
+package safeurl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrInvalidURL     = errors.New("invalid URL")
+	ErrInsecureScheme = errors.New("URL must use https")
+	ErrPrivateAddress = errors.New("URL must not point to a private, loopback or link-local address")
+)
+
+// nonPublic lists ranges that are not covered by the netip.Addr predicates
+// but still do not lead to the public internet.
+var nonPublic = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// Resolver looks up the addresses of a host, like net.Resolver.
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// Policy decides which URLs may be requested. AllowInsecure accepts http
+// URLs and private addresses, for development against local endpoints.
+// Hosts are looked up with net.DefaultResolver unless Resolver is set.
+type Policy struct {
+	AllowInsecure bool
+	Resolver      Resolver
+}
+
+// Check validates rawURL and resolves its host, failing when any of its
+// addresses is not public.
+func (p Policy) Check(ctx context.Context, rawURL string) error {
+	u, err := p.parse(rawURL)
+	if err != nil || p.AllowInsecure {
+		return err
+	}
+
+	var resolver Resolver = net.DefaultResolver
+	if p.Resolver != nil {
+		resolver = p.Resolver
+	}
+
+	addrs, err := resolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return fmt.Errorf("%w: cannot resolve %s", ErrInvalidURL, u.Hostname())
+	}
+	for _, addr := range addrs {
+		if !IsPublic(addr) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrPrivateAddress, u.Hostname(), addr)
+		}
+	}
+	return nil
+}
+
+// CheckScheme validates rawURL without resolving it, for URLs that are only
+// requested through a connection guarded by Control.
+func (p Policy) CheckScheme(rawURL string) error {
+	_, err := p.parse(rawURL)
+	return err
+}
+
+func (p Policy) parse(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return nil, ErrInvalidURL
+	}
+
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if !p.AllowInsecure {
+			return nil, ErrInsecureScheme
+		}
+	default:
+		return nil, ErrInvalidURL
+	}
+	return u, nil
+}
+
+// Control is a net.Dialer Control function refusing connections to
+// addresses that are not public.
+func (p Policy) Control(network, address string, _ syscall.RawConn) error {
+	if p.AllowInsecure {
+		return nil
+	}
+
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, address)
+	}
+	if !IsPublic(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+// Dialer returns a dialer that connects only to addresses Control accepts.
+func (p Policy) Dialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{Timeout: timeout, Control: p.Control}
+}
+
+// IsPublic reports whether addr is a globally routable unicast address.
+func IsPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublic {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
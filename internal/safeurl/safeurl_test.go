@@ -0,0 +1,79 @@
+package safeurl
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		addr     string
+		expected bool
+	}{
+		{addr: "93.184.216.34", expected: true},
+		{addr: "2606:2800:220:1:248:1893:25c8:1946", expected: true},
+		{addr: "127.0.0.1", expected: false},
+		{addr: "10.1.2.3", expected: false},
+		{addr: "172.16.0.1", expected: false},
+		{addr: "192.168.1.1", expected: false},
+		{addr: "169.254.169.254", expected: false},
+		{addr: "100.64.0.1", expected: false},
+		{addr: "0.0.0.0", expected: false},
+		{addr: "::1", expected: false},
+		{addr: "fe80::1", expected: false},
+		{addr: "fd00::1", expected: false},
+		{addr: "::ffff:127.0.0.1", expected: false},
+		{addr: "::ffff:169.254.169.254", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsPublic(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestPolicy_Check(t *testing.T) {
+	ctx := context.Background()
+	policy := Policy{}
+
+	tests := []struct {
+		name     string
+		url      string
+		expected error
+	}{
+		{name: "public address should be accepted", url: "https://93.184.216.34/hook"},
+		{name: "http should be rejected", url: "http://93.184.216.34/hook", expected: ErrInsecureScheme},
+		{name: "other schemes should be rejected", url: "ftp://93.184.216.34/hook", expected: ErrInvalidURL},
+		{name: "missing host should be rejected", url: "https:///hook", expected: ErrInvalidURL},
+		{name: "loopback should be rejected", url: "https://127.0.0.1:8080/hook", expected: ErrPrivateAddress},
+		{name: "metadata endpoint should be rejected", url: "https://169.254.169.254/latest", expected: ErrPrivateAddress},
+		{name: "private IPv6 should be rejected", url: "https://[fd00::1]/hook", expected: ErrPrivateAddress},
+		{name: "localhost should be rejected", url: "https://localhost/hook", expected: ErrPrivateAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(ctx, tt.url)
+			if tt.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+		})
+	}
+
+	t.Run("insecure policy should accept local http endpoints", func(t *testing.T) {
+		assert.NoError(t, Policy{AllowInsecure: true}.Check(ctx, "http://127.0.0.1:8080/hook"))
+	})
+}
+
+func TestPolicy_Control(t *testing.T) {
+	assert.NoError(t, Policy{}.Control("tcp4", "93.184.216.34:443", nil))
+	assert.ErrorIs(t, Policy{}.Control("tcp4", "127.0.0.1:443", nil), ErrPrivateAddress)
+	assert.ErrorIs(t, Policy{}.Control("tcp6", "[::1]:443", nil), ErrPrivateAddress)
+	assert.NoError(t, Policy{AllowInsecure: true}.Control("tcp4", "127.0.0.1:443", nil))
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lielamurs/aggregator/internal/affordability"
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/mappers"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/money"
+)
+
+var ErrOfferNotAssessable = errors.New("offer affordability cannot be assessed")
+
+// affordabilityPolicy converts the configured policy to amounts in currency.
+func affordabilityPolicy(cfg config.AffordabilityConfig, currency money.Currency) (affordability.Policy, error) {
+	subsistence, err := money.FromFloat(cfg.SubsistenceMinimum, currency, money.RoundHalfUp)
+	if err != nil {
+		return affordability.Policy{}, fmt.Errorf("invalid subsistence minimum: %w", err)
+	}
+
+	allowance, err := money.FromFloat(cfg.DependentAllowance, currency, money.RoundHalfUp)
+	if err != nil {
+		return affordability.Policy{}, fmt.Errorf("invalid dependent allowance: %w", err)
+	}
+
+	return affordability.Policy{
+		SubsistenceMinimum:  subsistence,
+		DependentAllowance:  allowance,
+		MaxDebtServiceRatio: cfg.MaxDebtServiceRatio,
+	}, nil
+}
+
+func applicationHousehold(application *models.Application) affordability.Household {
+	return affordability.Household{
+		MonthlyIncome:   application.MonthlyIncome,
+		MonthlyExpenses: application.MonthlyExpenses,
+		Dependents:      application.Dependents,
+	}
+}
+
+// assessApplication records the household's disposable income and
+// subsistence minimum on the application.
+func assessApplication(application *models.Application, cfg config.AffordabilityConfig) error {
+	policy, err := affordabilityPolicy(cfg, application.Amount.Currency())
+	if err != nil {
+		return err
+	}
+
+	assessment, err := affordability.Assess(applicationHousehold(application), policy)
+	if err != nil {
+		return err
+	}
+
+	application.DisposableIncome = &assessment.DisposableIncome
+	application.SubsistenceMinimum = &assessment.SubsistenceMinimum
+	return nil
+}
+
+// assessOffer records the debt-service ratio of the offer's monthly payment
+// and the responsible-lending rules the payment breaks on the offer.
+func assessOffer(offer *models.Offer, application *models.Application, cfg config.AffordabilityConfig) error {
+	if offer.Status != string(dto.OfferStatusApproved) || offer.MonthlyPaymentAmount == nil {
+		return fmt.Errorf("%w: approved offer with a monthly payment required", ErrOfferNotAssessable)
+	}
+
+	policy, err := affordabilityPolicy(cfg, application.Amount.Currency())
+	if err != nil {
+		return err
+	}
+
+	assessment, err := affordability.AssessPayment(applicationHousehold(application), *offer.MonthlyPaymentAmount, policy)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOfferNotAssessable, err)
+	}
+
+	issues := make([]dto.AffordabilityIssue, len(assessment.Issues))
+	for i, issue := range assessment.Issues {
+		issues[i] = dto.AffordabilityIssue(issue)
+	}
+	offer.DebtServiceRatio = &assessment.DebtServiceRatio
+	offer.AffordabilityIssues = mappers.JoinAffordabilityIssues(issues)
+	return nil
+}
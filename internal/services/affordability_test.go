@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func affordabilityApplication() *models.Application {
+	return &models.Application{
+		MonthlyIncome:   eur("2500"),
+		MonthlyExpenses: eur("400"),
+		Dependents:      1,
+		Amount:          eur("5000"),
+	}
+}
+
+func TestAssessApplication(t *testing.T) {
+	application := affordabilityApplication()
+
+	require.NoError(t, assessApplication(application, config.AffordabilityConfig{SubsistenceMinimum: 500, DependentAllowance: 250}))
+	require.NotNil(t, application.DisposableIncome)
+	require.NotNil(t, application.SubsistenceMinimum)
+	assert.Equal(t, eur("2100"), *application.DisposableIncome)
+	assert.Equal(t, eur("750"), *application.SubsistenceMinimum)
+}
+
+func TestAssessOffer(t *testing.T) {
+	cfg := config.AffordabilityConfig{SubsistenceMinimum: 500, DependentAllowance: 250, MaxDebtServiceRatio: 0.4}
+
+	t.Run("affordable payment should not be flagged", func(t *testing.T) {
+		offer := verifiableOffer(10.5, "5537.28")
+
+		require.NoError(t, assessOffer(offer, affordabilityApplication(), cfg))
+		require.NotNil(t, offer.DebtServiceRatio)
+		assert.Equal(t, 0.2523, *offer.DebtServiceRatio)
+		assert.Empty(t, offer.AffordabilityIssues)
+	})
+
+	t.Run("payment above the ratio should be flagged", func(t *testing.T) {
+		offer := verifiableOffer(10.5, "5537.28")
+		payment := eur("700")
+		offer.MonthlyPaymentAmount = &payment
+
+		require.NoError(t, assessOffer(offer, affordabilityApplication(), cfg))
+		assert.Equal(t, 0.44, *offer.DebtServiceRatio)
+		assert.Equal(t, "DEBT_SERVICE_RATIO_EXCEEDED", offer.AffordabilityIssues)
+	})
+
+	t.Run("rejected offers should not be assessable", func(t *testing.T) {
+		offer := &models.Offer{Status: string(dto.OfferStatusRejected)}
+
+		assert.ErrorIs(t, assessOffer(offer, affordabilityApplication(), cfg), ErrOfferNotAssessable)
+		assert.Nil(t, offer.DebtServiceRatio)
+	})
+
+	t.Run("applicants without income should not be assessable", func(t *testing.T) {
+		application := affordabilityApplication()
+		application.MonthlyIncome = eur("0")
+
+		assert.ErrorIs(t, assessOffer(verifiableOffer(10.5, "5537.28"), application, cfg), ErrOfferNotAssessable)
+	})
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/repository"
+)
+
+// ApplicationEventRecorder writes the status history of applications and
+// their bank submissions. Callers record events in the transaction that
+// changes the status, so the history never disagrees with the current state.
+//
+// Changes that clients can subscribe to are also published as webhooks and
+// announced to live status streams in the same transaction.
+type ApplicationEventRecorder struct {
+	eventsRepo *repository.ApplicationEventsRepository
+	webhooks   *WebhookPublisher
+}
+
+func NewApplicationEventRecorder(eventsRepo *repository.ApplicationEventsRepository, webhooks *WebhookPublisher) *ApplicationEventRecorder {
+	return &ApplicationEventRecorder{
+		eventsRepo: eventsRepo,
+		webhooks:   webhooks,
+	}
+}
+
+func (r *ApplicationEventRecorder) ApplicationStatusChanged(ctx context.Context, applicationID uuid.UUID, from, to dto.ApplicationStatus, actor dto.EventActor, reason string) error {
+	event := &models.ApplicationEvent{
+		ID:            uuid.New(),
+		ApplicationID: applicationID,
+		EntityType:    string(dto.EventEntityApplication),
+		OldStatus:     optionalString(string(from)),
+		NewStatus:     string(to),
+		Actor:         string(actor),
+		Reason:        optionalString(reason),
+		CreatedAt:     time.Now(),
+	}
+
+	if err := r.eventsRepo.Create(ctx, event); err != nil {
+		return fmt.Errorf("failed to record application event: %w", err)
+	}
+	if err := r.eventsRepo.NotifyUpdated(ctx, applicationID); err != nil {
+		return fmt.Errorf("failed to notify application update: %w", err)
+	}
+	return r.webhooks.ApplicationStatusChanged(ctx, applicationID, to)
+}
+
+// SubmissionStatusChanged records the submission moving from its previous
+// status to its current one. The submission's error message, if any, is
+// kept as the reason.
+func (r *ApplicationEventRecorder) SubmissionStatusChanged(ctx context.Context, submission *models.BankSubmission, from string, actor dto.EventActor) error {
+	if submission.Status == from {
+		return nil
+	}
+
+	var reason *string
+	if submission.ErrorMessage != nil {
+		reason = optionalString(*submission.ErrorMessage)
+	}
+
+	event := &models.ApplicationEvent{
+		ID:            uuid.New(),
+		ApplicationID: submission.ApplicationID,
+		EntityType:    string(dto.EventEntityBankSubmission),
+		SubmissionID:  &submission.ID,
+		BankName:      &submission.BankName,
+		OldStatus:     optionalString(from),
+		NewStatus:     submission.Status,
+		Actor:         string(actor),
+		Reason:        reason,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := r.eventsRepo.Create(ctx, event); err != nil {
+		return fmt.Errorf("failed to record bank submission event: %w", err)
+	}
+	return r.webhooks.SubmissionStatusChanged(ctx, submission)
+}
+
+// OfferReceived publishes an offer saved for the application. Offers have no
+// status history of their own; the submission's SUCCESS event covers it.
+func (r *ApplicationEventRecorder) OfferReceived(ctx context.Context, offer *models.Offer) error {
+	if err := r.eventsRepo.NotifyUpdated(ctx, offer.ApplicationID); err != nil {
+		return fmt.Errorf("failed to notify application update: %w", err)
+	}
+	return r.webhooks.OfferReceived(ctx, offer)
+}
+
+// OfferDecided announces a change to the customer's decision on an offer to
+// live status streams. Accepting an offer also changes the application
+// status, which is recorded and published through ApplicationStatusChanged.
+func (r *ApplicationEventRecorder) OfferDecided(ctx context.Context, offer *models.Offer) error {
+	if err := r.eventsRepo.NotifyUpdated(ctx, offer.ApplicationID); err != nil {
+		return fmt.Errorf("failed to notify application update: %w", err)
+	}
+	return nil
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
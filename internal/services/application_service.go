@@ -2,14 +2,18 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"sync"
+	"net/http"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/config"
 	"github.com/lielamurs/aggregator/internal/dto"
 	"github.com/lielamurs/aggregator/internal/mappers"
 	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/offermath"
 	"github.com/lielamurs/aggregator/internal/repository"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -18,29 +22,78 @@ import (
 type ApplicationService interface {
 	SubmitApplication(ctx context.Context, app *dto.CustomerApplication) (*dto.ApplicationResponse, error)
 	GetApplicationStatus(ctx context.Context, applicationID uuid.UUID) (*models.Application, error)
+	GetApplicationEvents(ctx context.Context, applicationID uuid.UUID) ([]models.ApplicationEvent, error)
+	SearchApplications(ctx context.Context, req *dto.ApplicationSearchRequest) ([]models.Application, *dto.ApplicationCursor, error)
+	GetOfferSchedule(ctx context.Context, applicationID, offerID uuid.UUID) (*models.Offer, *offermath.Schedule, error)
+	AcceptOffer(ctx context.Context, applicationID, offerID uuid.UUID) (*models.Application, error)
+	CancelApplication(ctx context.Context, applicationID uuid.UUID) (*models.Application, error)
 }
 
+var (
+	ErrInvalidCursor            = errors.New("invalid cursor")
+	ErrOfferNotFound            = errors.New("offer not found")
+	ErrOfferNotAcceptable       = errors.New("offer cannot be accepted")
+	ErrOfferDeclined            = errors.New("offer declined by bank")
+	ErrBankUnavailable          = errors.New("bank unavailable")
+	ErrApplicationStatusChanged = errors.New("application status changed concurrently")
+	ErrAcceptanceInProgress     = errors.New("an offer acceptance is in progress")
+)
+
+// acceptanceClaimTimeout is how long an offer stays claimed for a request
+// forwarding its acceptance. Claims older than this were left behind by a
+// crashed request and may be taken over.
+const acceptanceClaimTimeout = 5 * time.Minute
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
 type applicationService struct {
-	applicationsRepo    *repository.ApplicationsRepository
-	offersRepo          *repository.OffersRepository
-	bankSubmissionsRepo *repository.BankSubmissionsRepository
-	bankServices        []BankService
-	logger              *logrus.Logger
+	applicationsRepo   *repository.ApplicationsRepository
+	submissionJobsRepo *repository.SubmissionJobsRepository
+	eventsRepo         *repository.ApplicationEventsRepository
+	offersRepo         *repository.OffersRepository
+	transactor         *repository.Transactor
+	stateMachine       *ApplicationStateMachine
+	events             *ApplicationEventRecorder
+	submissions        SubmissionService
+	bankServices       []BankService
+	jobNotifier        SubmissionJobNotifier
+	affordability      config.AffordabilityConfig
+	velocity           config.VelocityConfig
+	logger             *logrus.Logger
 }
 
 func NewApplicationService(
 	applicationsRepo *repository.ApplicationsRepository,
+	submissionJobsRepo *repository.SubmissionJobsRepository,
+	eventsRepo *repository.ApplicationEventsRepository,
 	offersRepo *repository.OffersRepository,
-	bankSubmissionsRepo *repository.BankSubmissionsRepository,
+	transactor *repository.Transactor,
+	stateMachine *ApplicationStateMachine,
+	events *ApplicationEventRecorder,
+	submissions SubmissionService,
 	bankServices []BankService,
+	jobNotifier SubmissionJobNotifier,
+	affordability config.AffordabilityConfig,
+	velocity config.VelocityConfig,
 	logger *logrus.Logger,
 ) ApplicationService {
 	return &applicationService{
-		applicationsRepo:    applicationsRepo,
-		offersRepo:          offersRepo,
-		bankSubmissionsRepo: bankSubmissionsRepo,
-		bankServices:        bankServices,
-		logger:              logger,
+		applicationsRepo:   applicationsRepo,
+		submissionJobsRepo: submissionJobsRepo,
+		eventsRepo:         eventsRepo,
+		offersRepo:         offersRepo,
+		transactor:         transactor,
+		stateMachine:       stateMachine,
+		events:             events,
+		submissions:        submissions,
+		bankServices:       bankServices,
+		jobNotifier:        jobNotifier,
+		affordability:      affordability,
+		velocity:           velocity,
+		logger:             logger,
 	}
 }
 
@@ -50,12 +103,71 @@ func (s *applicationService) SubmitApplication(ctx context.Context, customerApp
 		return nil, fmt.Errorf("failed to convert application to model")
 	}
 
-	if err := s.applicationsRepo.Create(ctx, application); err != nil {
+	if err := assessApplication(application, s.affordability); err != nil {
+		s.logger.WithError(err).WithField("application_id", application.ID).Warn("Failed to assess affordability")
+	}
+
+	// The velocity checks and the insert share a transaction holding the
+	// customer's locks, so concurrent requests from the same customer or IP
+	// see each other's applications.
+	var clientIP string
+	if application.ClientIP != nil {
+		clientIP = *application.ClientIP
+	}
+
+	var linked *dto.ApplicationResponse
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.applicationsRepo.LockCustomer(ctx, application.Phone, application.Email, clientIP); err != nil {
+			return fmt.Errorf("failed to lock customer applications: %w", err)
+		}
+
+		var err error
+		linked, err = s.checkVelocity(ctx, application)
+		if err != nil || linked != nil {
+			return err
+		}
+
+		if err := s.applicationsRepo.Create(ctx, application); err != nil {
+			return fmt.Errorf("failed to save application: %w", err)
+		}
+
+		if err := s.events.ApplicationStatusChanged(ctx, application.ID, "", dto.StatusPending, dto.EventActorCustomer, ""); err != nil {
+			return err
+		}
+
+		for _, bankService := range s.bankServices {
+			job := &models.SubmissionJob{
+				ID:            uuid.New(),
+				ApplicationID: application.ID,
+				BankName:      bankService.GetBankName(),
+				Status:        string(dto.JobStatusPending),
+				RunAt:         application.CreatedAt,
+				CreatedAt:     application.CreatedAt,
+				UpdatedAt:     application.CreatedAt,
+			}
+			if err := s.submissionJobsRepo.Create(ctx, job); err != nil {
+				return fmt.Errorf("failed to save submission job: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if errors.Is(err, ErrDuplicateApplication) || errors.Is(err, ErrVelocityLimitExceeded) {
+		return nil, err
+	}
+	if err != nil {
 		s.logger.WithError(err).WithField("application_id", customerApp.ID).Error("Failed to save application")
-		return nil, fmt.Errorf("failed to save application: %w", err)
+		return nil, err
+	}
+	if linked != nil {
+		s.logger.WithFields(logrus.Fields{
+			"application_id": linked.ID,
+			"reason":         linked.LinkedReason,
+		}).Info("Application request linked to an existing application")
+		return linked, nil
 	}
 
-	go s.processApplication(context.Background(), customerApp)
+	s.jobNotifier.Notify()
 
 	return &dto.ApplicationResponse{
 		ID:     customerApp.ID,
@@ -79,124 +191,333 @@ func (s *applicationService) GetApplicationStatus(ctx context.Context, applicati
 	return application, nil
 }
 
-func (s *applicationService) processApplication(ctx context.Context, customerApp *dto.CustomerApplication) {
-	logger := s.logger.WithField("application_id", customerApp.ID)
-	logger.Info("Starting application processing")
-
-	customerApp.Status = dto.StatusProcessing
-	customerApp.UpdatedAt = time.Now()
+func (s *applicationService) GetApplicationEvents(ctx context.Context, applicationID uuid.UUID) ([]models.ApplicationEvent, error) {
+	logger := s.logger.WithField("application_id", applicationID)
 
-	if err := s.updateApplication(ctx, customerApp); err != nil {
-		logger.WithError(err).Error("Failed to update application status to processing")
-		return
+	exists, err := s.applicationsRepo.Exists(ctx, applicationID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to check application existence")
+		return nil, fmt.Errorf("failed to get application: %w", err)
 	}
 
-	var wg sync.WaitGroup
-	results := make(chan dto.BankResult, len(s.bankServices))
+	if !exists {
+		logger.Debug("Application not found")
+		return nil, fmt.Errorf("application with ID %s not found", applicationID)
+	}
 
-	for _, bankService := range s.bankServices {
-		wg.Add(1)
-		go func(bank BankService) {
-			defer wg.Done()
-			s.submitToBankAsync(ctx, bank, customerApp, results)
-		}(bankService)
+	events, err := s.eventsRepo.GetByApplicationID(ctx, applicationID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get application events from database")
+		return nil, fmt.Errorf("failed to get application events: %w", err)
 	}
 
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+	return events, nil
+}
 
-	for result := range results {
-		if result.Err != nil {
-			logger.WithError(result.Err).WithField("bank", result.BankName).Error("Bank submission failed")
+// SearchApplications returns one page of applications matching the request
+// and the cursor of the next page, which is nil on the last page.
+func (s *applicationService) SearchApplications(ctx context.Context, req *dto.ApplicationSearchRequest) ([]models.Application, *dto.ApplicationCursor, error) {
+	filter := repository.ApplicationFilter{
+		Status:           req.Status,
+		Email:            req.Email,
+		Phone:            req.Phone,
+		BankName:         req.BankName,
+		SubmissionStatus: req.SubmissionStatus,
+	}
 
-			if err := s.saveBankSubmission(ctx, customerApp.ID, result.BankName, dto.SubmissionStatusFailed, "", result.Err); err != nil {
-				logger.WithError(err).WithField("bank", result.BankName).Error("Failed to save bank submission")
-			}
-		} else {
-			logger.WithField("bank", result.BankName).Info("Bank submission successful")
+	var err error
+	if filter.CreatedFrom, err = parseOptionalTime(req.CreatedFrom); err != nil {
+		return nil, nil, fmt.Errorf("invalid createdFrom: %w", err)
+	}
+	if filter.CreatedTo, err = parseOptionalTime(req.CreatedTo); err != nil {
+		return nil, nil, fmt.Errorf("invalid createdTo: %w", err)
+	}
 
-			if err := s.saveBankSubmission(ctx, customerApp.ID, result.BankName, dto.SubmissionStatusDraft, result.SubmissionID, nil); err != nil {
-				logger.WithError(err).WithField("bank", result.BankName).Error("Failed to save bank submission")
-			}
+	if req.Cursor != "" {
+		cursor, err := mappers.DecodeApplicationCursor(req.Cursor)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
 		}
+		filter.AfterCreatedAt = &cursor.CreatedAt
+		filter.AfterID = &cursor.ID
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	limit = min(limit, maxSearchLimit)
+
+	applications, err := s.applicationsRepo.Search(ctx, filter, limit+1)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to search applications")
+		return nil, nil, fmt.Errorf("failed to search applications: %w", err)
 	}
 
-	logger.Info("Application processing completed - bank submissions saved as DRAFT")
+	if len(applications) <= limit {
+		return applications, nil, nil
+	}
+
+	applications = applications[:limit]
+	last := applications[limit-1]
+	return applications, &dto.ApplicationCursor{CreatedAt: last.CreatedAt, ID: last.ID}, nil
+}
+
+// GetOfferSchedule recomputes the amortization schedule of one of the
+// application's approved offers from its payments.
+func (s *applicationService) GetOfferSchedule(ctx context.Context, applicationID, offerID uuid.UUID) (*models.Offer, *offermath.Schedule, error) {
+	application, err := s.GetApplicationStatus(ctx, applicationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	index := slices.IndexFunc(application.Offers, func(offer models.Offer) bool { return offer.ID == offerID })
+	if index < 0 {
+		return nil, nil, ErrOfferNotFound
+	}
+
+	offer := &application.Offers[index]
+	loan, err := offerLoan(offer, application.Amount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schedule, err := offermath.Amortize(loan)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrOfferNotVerifiable, err)
+	}
+	return offer, schedule, nil
 }
 
-func (s *applicationService) submitToBankAsync(ctx context.Context, bank BankService, customerApp *dto.CustomerApplication, results chan<- dto.BankResult) {
+// AcceptOffer forwards the customer's acceptance of an offer to the bank
+// that issued it and, once the bank agrees, marks the offer accepted, its
+// competitors superseded and the application accepted. Offers from banks
+// without an acceptance API are accepted on our side only. An offer the bank
+// refuses is marked declined and the application stays completed, so the
+// customer can pick another one.
+func (s *applicationService) AcceptOffer(ctx context.Context, applicationID, offerID uuid.UUID) (*models.Application, error) {
 	logger := s.logger.WithFields(logrus.Fields{
-		"application_id": customerApp.ID,
-		"bank":           bank.GetBankName(),
+		"application_id": applicationID,
+		"offer_id":       offerID,
 	})
 
-	logger.Info("Submitting application to bank")
-
-	response, err := bank.SubmitApplication(ctx, customerApp.CustomerData)
+	application, err := s.GetApplicationStatus(ctx, applicationID)
 	if err != nil {
-		logger.WithError(err).Error("Bank submission failed")
-		results <- dto.BankResult{
-			BankName: bank.GetBankName(),
-			Err:      err,
+		return nil, err
+	}
+
+	from := dto.ApplicationStatus(application.Status)
+	if !CanTransition(from, dto.StatusAccepted) {
+		return nil, &InvalidTransitionError{From: from, To: dto.StatusAccepted}
+	}
+
+	index := slices.IndexFunc(application.Offers, func(offer models.Offer) bool { return offer.ID == offerID })
+	if index < 0 {
+		return nil, ErrOfferNotFound
+	}
+	offer := &application.Offers[index]
+	decided := offer.AcceptanceStatus != nil && dto.OfferAcceptanceStatus(*offer.AcceptanceStatus) != dto.OfferAcceptancePending
+	if dto.OfferStatus(offer.Status) != dto.OfferStatusApproved || decided {
+		return nil, ErrOfferNotAcceptable
+	}
+
+	logger = logger.WithField("bank", offer.BankName)
+
+	if err := s.claimAcceptance(ctx, applicationID, offer.ID); err != nil {
+		logger.WithError(err).Warn("Failed to claim offer for acceptance")
+		return nil, err
+	}
+
+	if err := s.forwardAcceptance(ctx, application, offer); err != nil {
+		if !errors.Is(err, ErrOfferDeclined) {
+			logger.WithError(err).Error("Failed to forward offer acceptance to bank")
+			// The customer may have gone away; the claim must still be
+			// released so the offer can be accepted again.
+			if releaseErr := s.offersRepo.ReleaseAcceptance(context.WithoutCancel(ctx), offer.ID); releaseErr != nil {
+				logger.WithError(releaseErr).Error("Failed to release offer acceptance claim")
+			}
+			return nil, err
+		}
+
+		logger.WithError(err).Warn("Bank declined offer acceptance")
+		err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.offersRepo.UpdateAcceptanceStatus(ctx, offer.ID, dto.OfferAcceptanceDeclined); err != nil {
+				return fmt.Errorf("failed to mark offer declined: %w", err)
+			}
+			return s.events.OfferDecided(ctx, offer)
+		})
+		if err != nil {
+			logger.WithError(err).Error("Failed to record declined offer")
+			return nil, err
 		}
-		return
+		return nil, ErrOfferDeclined
 	}
 
-	logger.WithField("submission_id", response.ID).Info("Bank submission successful")
-	results <- dto.BankResult{
-		BankName:     bank.GetBankName(),
-		SubmissionID: response.ID,
+	reason := fmt.Sprintf("offer %s from %s accepted", offer.ID, offer.BankName)
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.offersRepo.UpdateAcceptanceStatus(ctx, offer.ID, dto.OfferAcceptanceAccepted); err != nil {
+			return fmt.Errorf("failed to mark offer accepted: %w", err)
+		}
+		if err := s.offersRepo.SupersedeCompetingOffers(ctx, applicationID, offer.ID); err != nil {
+			return fmt.Errorf("failed to mark competing offers superseded: %w", err)
+		}
+
+		updated, err := s.stateMachine.Transition(ctx, applicationID, from, dto.StatusAccepted, dto.EventActorCustomer, reason)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return ErrApplicationStatusChanged
+		}
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to record offer acceptance")
+		return nil, err
 	}
+
+	logger.Info("Offer accepted")
+
+	return s.GetApplicationStatus(ctx, applicationID)
 }
 
-func (s *applicationService) updateApplication(ctx context.Context, customerApp *dto.CustomerApplication) error {
-	if customerApp == nil {
-		return fmt.Errorf("application cannot be nil")
+// claimAcceptance marks the offer PENDING before the bank is asked, so
+// concurrent requests cannot forward a second acceptance. The application row
+// is locked while claiming, which serializes claims with each other and with
+// cancellation.
+func (s *applicationService) claimAcceptance(ctx context.Context, applicationID, offerID uuid.UUID) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		status, err := s.applicationsRepo.LockStatus(ctx, applicationID)
+		if err != nil {
+			return fmt.Errorf("failed to lock application: %w", err)
+		}
+		if !CanTransition(status, dto.StatusAccepted) {
+			return &InvalidTransitionError{From: status, To: dto.StatusAccepted}
+		}
+
+		claimed, err := s.offersRepo.ClaimAcceptance(ctx, applicationID, offerID, time.Now().Add(-acceptanceClaimTimeout))
+		if err != nil {
+			return fmt.Errorf("failed to claim offer: %w", err)
+		}
+		if !claimed {
+			return ErrAcceptanceInProgress
+		}
+		return nil
+	})
+}
+
+// CancelApplication withdraws the application on the customer's request.
+// Submissions still waiting for a decision stop being polled, and the
+// application is CANCELLED in the same transaction. The banks that hold the
+// application are then asked to withdraw it; failures are recorded on the
+// bank submissions and do not undo the cancellation.
+func (s *applicationService) CancelApplication(ctx context.Context, applicationID uuid.UUID) (*models.Application, error) {
+	logger := s.logger.WithField("application_id", applicationID)
+
+	application, err := s.GetApplicationStatus(ctx, applicationID)
+	if err != nil {
+		return nil, err
 	}
 
-	application := mappers.ToApplicationModel(customerApp)
-	if application == nil {
-		return fmt.Errorf("failed to convert application to model")
+	from := dto.ApplicationStatus(application.Status)
+	if !CanTransition(from, dto.StatusCancelled) {
+		return nil, &InvalidTransitionError{From: from, To: dto.StatusCancelled}
 	}
 
-	return s.applicationsRepo.Update(ctx, application)
-}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.applicationsRepo.LockStatus(ctx, applicationID); err != nil {
+			return fmt.Errorf("failed to lock application: %w", err)
+		}
+		pending, err := s.offersRepo.HasPendingAcceptance(ctx, applicationID, time.Now().Add(-acceptanceClaimTimeout))
+		if err != nil {
+			return fmt.Errorf("failed to check offer acceptances: %w", err)
+		}
+		if pending {
+			return ErrAcceptanceInProgress
+		}
 
-func (s *applicationService) saveBankSubmission(ctx context.Context, applicationID uuid.UUID, bankName string, status dto.BankSubmissionStatus, bankID string, submissionErr error) error {
-	exists, err := s.applicationsRepo.Exists(ctx, applicationID)
+		if err := s.submissions.CancelSubmissions(ctx, applicationID, dto.EventActorCustomer); err != nil {
+			return err
+		}
+
+		updated, err := s.stateMachine.Transition(ctx, applicationID, from, dto.StatusCancelled, dto.EventActorCustomer, "cancelled by customer")
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return ErrApplicationStatusChanged
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to verify application exists: %w", err)
+		logger.WithError(err).Error("Failed to cancel application")
+		return nil, err
 	}
 
-	if !exists {
-		return fmt.Errorf("application with ID %s not found", applicationID)
+	logger.Info("Application cancelled")
+
+	if err := s.submissions.WithdrawSubmissions(ctx, applicationID); err != nil {
+		logger.WithError(err).Error("Failed to withdraw application from banks")
 	}
 
-	now := time.Now()
-	bankSubmission := &dto.BankSubmission{
-		ID:        uuid.New(),
-		BankName:  bankName,
-		Status:    status,
-		BankID:    bankID,
-		CreatedAt: now,
+	return s.GetApplicationStatus(ctx, applicationID)
+}
+
+// forwardAcceptance tells the issuing bank about the acceptance. Only an
+// explicit refusal is reported as ErrOfferDeclined; outages are reported as
+// ErrBankUnavailable and any other failure, such as a cancelled request or a
+// response we could not make sense of, as a plain error, so the offer stays
+// open.
+func (s *applicationService) forwardAcceptance(ctx context.Context, application *models.Application, offer *models.Offer) error {
+	bankIndex := slices.IndexFunc(s.bankServices, func(bank BankService) bool { return bank.GetBankName() == offer.BankName })
+	submissionIndex := slices.IndexFunc(application.BankSubmissions, func(submission models.BankSubmission) bool {
+		return submission.BankName == offer.BankName
+	})
+	if bankIndex < 0 || submissionIndex < 0 || application.BankSubmissions[submissionIndex].BankID == nil {
+		return fmt.Errorf("%w: %s is not configured or has no application", ErrBankUnavailable, offer.BankName)
 	}
 
-	if status == dto.SubmissionStatusDraft {
-		bankSubmission.SubmittedAt = now
+	err := acceptOffer(ctx, s.bankServices[bankIndex], *application.BankSubmissions[submissionIndex].BankID)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrOfferAcceptanceNotSupported):
+		s.logger.WithField("bank", offer.BankName).Info("Bank has no acceptance API, accepting offer locally")
+		return nil
+	case isBankRefusal(err):
+		return fmt.Errorf("%w: %v", ErrOfferDeclined, err)
+	case errors.Is(err, ErrCircuitOpen) || isBankUnavailable(err):
+		return fmt.Errorf("%w: %v", ErrBankUnavailable, err)
+	default:
+		return fmt.Errorf("failed to forward offer acceptance to %s: %w", offer.BankName, err)
 	}
+}
 
-	if submissionErr != nil {
-		errorMsg := submissionErr.Error()
-		bankSubmission.ErrorMessage = &errorMsg
+// isBankRefusal reports whether the bank answered that it will not go ahead
+// with the offer, e.g. because it expired or was withdrawn. Other client
+// errors point at a request the bank could not process rather than a
+// decision.
+func isBankRefusal(err error) bool {
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		return false
 	}
 
-	submission := mappers.ToBankSubmissionModel(bankSubmission)
-	if submission == nil {
-		return fmt.Errorf("failed to convert bank submission to model")
+	switch httpErr.StatusCode {
+	case http.StatusConflict, http.StatusGone, http.StatusUnprocessableEntity:
+		return true
+	default:
+		return false
 	}
+}
 
-	submission.ApplicationID = applicationID
-	return s.bankSubmissionsRepo.Create(ctx, submission)
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
 }
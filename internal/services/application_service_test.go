@@ -0,0 +1,543 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/mappers"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/repository"
+	"github.com/lielamurs/aggregator/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type acceptingBankService struct {
+	countingBankService
+	acceptErr error
+	accepted  []string
+}
+
+func (b *acceptingBankService) AcceptOffer(ctx context.Context, bankID string) error {
+	b.accepted = append(b.accepted, bankID)
+	return b.acceptErr
+}
+
+// blockingAcceptBankService holds every acceptance until release is closed.
+type blockingAcceptBankService struct {
+	countingBankService
+	calls   *atomic.Int32
+	release chan struct{}
+}
+
+func (b *blockingAcceptBankService) AcceptOffer(ctx context.Context, bankID string) error {
+	b.calls.Add(1)
+	<-b.release
+	return nil
+}
+
+// cancelledAcceptBankService cancels the request while the bank is being
+// asked, as when the customer disconnects mid-acceptance.
+type cancelledAcceptBankService struct {
+	countingBankService
+	cancel context.CancelFunc
+}
+
+func (b *cancelledAcceptBankService) AcceptOffer(ctx context.Context, bankID string) error {
+	b.cancel()
+	return ctx.Err()
+}
+
+type cancellingBankService struct {
+	countingBankService
+	cancelErr error
+	cancelled []string
+}
+
+func (b *cancellingBankService) CancelApplication(ctx context.Context, bankID string) error {
+	b.cancelled = append(b.cancelled, bankID)
+	return b.cancelErr
+}
+
+func newTestApplicationService(db *gorm.DB, banks ...BankService) ApplicationService {
+	velocity := config.VelocityConfig{LimitAction: config.VelocityActionOff, DuplicateAction: config.VelocityActionOff}
+	return newVelocityTestApplicationService(db, velocity, banks...)
+}
+
+func newVelocityTestApplicationService(db *gorm.DB, velocity config.VelocityConfig, banks ...BankService) ApplicationService {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	applicationsRepo := repository.NewApplicationsRepository(db)
+	eventsRepo := repository.NewApplicationEventsRepository(db)
+	offersRepo := repository.NewOffersRepository(db)
+	transactor := repository.NewTransactor(db)
+	events := NewApplicationEventRecorder(eventsRepo, NewWebhookPublisher(applicationsRepo, repository.NewWebhooksRepository(db)))
+	stateMachine := NewApplicationStateMachine(applicationsRepo, transactor, events, logger)
+	submissions := NewSubmissionService(
+		applicationsRepo,
+		offersRepo,
+		repository.NewBankSubmissionsRepository(db),
+		transactor,
+		stateMachine,
+		events,
+		banks,
+		PollSchedules{},
+		config.SubmissionProcessorConfig{BatchSize: 3, LeaseSeconds: 60},
+		config.OfferVerificationConfig{APRTolerance: 0.1, AmountTolerance: 1},
+		config.AffordabilityConfig{SubsistenceMinimum: 500, DependentAllowance: 250, MaxDebtServiceRatio: 0.4},
+		logger,
+	)
+	return NewApplicationService(
+		applicationsRepo,
+		repository.NewSubmissionJobsRepository(db),
+		eventsRepo,
+		offersRepo,
+		transactor,
+		stateMachine,
+		events,
+		submissions,
+		banks,
+		nil,
+		config.AffordabilityConfig{SubsistenceMinimum: 500, DependentAllowance: 250, MaxDebtServiceRatio: 0.4},
+		velocity,
+		logger,
+	)
+}
+
+// createCompletedApplicationWithOffers creates a completed application with
+// an approved offer from each bank.
+func createCompletedApplicationWithOffers(t *testing.T, db *gorm.DB, bankNames ...string) (uuid.UUID, []models.Offer) {
+	t.Helper()
+
+	applicationID := createProcessingApplication(t, db)
+	require.NoError(t, db.Model(&models.Application{}).Where("id = ?", applicationID).Update("status", dto.StatusCompleted).Error)
+
+	var offers []models.Offer
+	for _, bankName := range bankNames {
+		submission := createDraftSubmission(t, db, applicationID, bankName, time.Now())
+		require.NoError(t, db.Model(submission).Update("status", dto.SubmissionStatusSuccess).Error)
+
+		offer := models.Offer{
+			ID:            uuid.New(),
+			ApplicationID: applicationID,
+			BankName:      bankName,
+			Status:        string(dto.OfferStatusApproved),
+		}
+		require.NoError(t, db.Create(&offer).Error)
+		offers = append(offers, offer)
+	}
+	return applicationID, offers
+}
+
+func acceptanceStatuses(t *testing.T, db *gorm.DB, applicationID uuid.UUID) map[string]string {
+	t.Helper()
+
+	var offers []models.Offer
+	require.NoError(t, db.Where("application_id = ?", applicationID).Find(&offers).Error)
+
+	statuses := make(map[string]string, len(offers))
+	for _, offer := range offers {
+		statuses[offer.BankName] = ""
+		if offer.AcceptanceStatus != nil {
+			statuses[offer.BankName] = *offer.AcceptanceStatus
+		}
+	}
+	return statuses
+}
+
+func TestApplicationService_AcceptOffer(t *testing.T) {
+	db := testutil.OpenPostgres(t)
+	ctx := context.Background()
+
+	t.Run("accepted offer should supersede competing offers", func(t *testing.T) {
+		fastBank := &acceptingBankService{countingBankService: countingBankService{name: "FastBank"}}
+		solidBank := &countingBankService{name: "SolidBank"}
+		service := newTestApplicationService(db, fastBank, solidBank)
+		applicationID, offers := createCompletedApplicationWithOffers(t, db, "FastBank", "SolidBank")
+
+		application, err := service.AcceptOffer(ctx, applicationID, offers[0].ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, string(dto.StatusAccepted), application.Status)
+		assert.Equal(t, []string{"bank-" + applicationID.String()}, fastBank.accepted)
+		assert.Equal(t, map[string]string{
+			"FastBank":  string(dto.OfferAcceptanceAccepted),
+			"SolidBank": string(dto.OfferAcceptanceSuperseded),
+		}, acceptanceStatuses(t, db, applicationID))
+
+		_, err = service.AcceptOffer(ctx, applicationID, offers[1].ID)
+		var transitionErr *InvalidTransitionError
+		assert.ErrorAs(t, err, &transitionErr)
+	})
+
+	t.Run("offer declined by the bank should leave the application completed", func(t *testing.T) {
+		fastBank := &acceptingBankService{
+			countingBankService: countingBankService{name: "FastBank"},
+			acceptErr:           &HTTPError{StatusCode: 409, Body: "offer expired"},
+		}
+		service := newTestApplicationService(db, fastBank)
+		applicationID, offers := createCompletedApplicationWithOffers(t, db, "FastBank")
+
+		_, err := service.AcceptOffer(ctx, applicationID, offers[0].ID)
+		assert.ErrorIs(t, err, ErrOfferDeclined)
+
+		application, err := service.GetApplicationStatus(ctx, applicationID)
+		require.NoError(t, err)
+		assert.Equal(t, string(dto.StatusCompleted), application.Status)
+		assert.Equal(t, map[string]string{"FastBank": string(dto.OfferAcceptanceDeclined)}, acceptanceStatuses(t, db, applicationID))
+
+		_, err = service.AcceptOffer(ctx, applicationID, offers[0].ID)
+		assert.ErrorIs(t, err, ErrOfferNotAcceptable)
+	})
+
+	t.Run("bank outage should not record a decision", func(t *testing.T) {
+		fastBank := &acceptingBankService{
+			countingBankService: countingBankService{name: "FastBank"},
+			acceptErr:           &HTTPError{StatusCode: 503},
+		}
+		service := newTestApplicationService(db, fastBank)
+		applicationID, offers := createCompletedApplicationWithOffers(t, db, "FastBank")
+
+		_, err := service.AcceptOffer(ctx, applicationID, offers[0].ID)
+		assert.ErrorIs(t, err, ErrBankUnavailable)
+		assert.Equal(t, map[string]string{"FastBank": ""}, acceptanceStatuses(t, db, applicationID), "the claim should be released")
+	})
+
+	t.Run("unclassified bank errors should not record a decision", func(t *testing.T) {
+		fastBank := &acceptingBankService{
+			countingBankService: countingBankService{name: "FastBank"},
+			acceptErr:           &HTTPError{StatusCode: 400, Body: "malformed request"},
+		}
+		service := newTestApplicationService(db, fastBank)
+		applicationID, offers := createCompletedApplicationWithOffers(t, db, "FastBank")
+
+		_, err := service.AcceptOffer(ctx, applicationID, offers[0].ID)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrOfferDeclined)
+		assert.Equal(t, map[string]string{"FastBank": ""}, acceptanceStatuses(t, db, applicationID), "the claim should be released")
+	})
+
+	t.Run("cancelled request should release the claim", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		fastBank := &cancelledAcceptBankService{countingBankService: countingBankService{name: "FastBank"}, cancel: cancel}
+		service := newTestApplicationService(db, fastBank)
+		applicationID, offers := createCompletedApplicationWithOffers(t, db, "FastBank")
+
+		_, err := service.AcceptOffer(ctx, applicationID, offers[0].ID)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.NotErrorIs(t, err, ErrOfferDeclined)
+		assert.Equal(t, map[string]string{"FastBank": ""}, acceptanceStatuses(t, db, applicationID), "the claim should be released")
+	})
+
+	t.Run("concurrent acceptances should reach one bank once", func(t *testing.T) {
+		var calls atomic.Int32
+		release := make(chan struct{})
+		fastBank := &blockingAcceptBankService{countingBankService: countingBankService{name: "FastBank"}, calls: &calls, release: release}
+		solidBank := &blockingAcceptBankService{countingBankService: countingBankService{name: "SolidBank"}, calls: &calls, release: release}
+		service := newTestApplicationService(db, fastBank, solidBank)
+		applicationID, offers := createCompletedApplicationWithOffers(t, db, "FastBank", "SolidBank")
+
+		results := make(chan error, len(offers))
+		for _, offer := range offers {
+			go func() {
+				_, err := service.AcceptOffer(ctx, applicationID, offer.ID)
+				results <- err
+			}()
+		}
+
+		// The bank holds the winner, so the first result is the loser's.
+		assert.ErrorIs(t, <-results, ErrAcceptanceInProgress)
+
+		_, err := service.CancelApplication(ctx, applicationID)
+		assert.ErrorIs(t, err, ErrAcceptanceInProgress)
+
+		close(release)
+		assert.NoError(t, <-results)
+		assert.Equal(t, int32(1), calls.Load())
+
+		statuses := acceptanceStatuses(t, db, applicationID)
+		assert.ElementsMatch(t, []string{string(dto.OfferAcceptanceAccepted), string(dto.OfferAcceptanceSuperseded)},
+			[]string{statuses["FastBank"], statuses["SolidBank"]})
+
+		application, err := service.GetApplicationStatus(ctx, applicationID)
+		require.NoError(t, err)
+		assert.Equal(t, string(dto.StatusAccepted), application.Status)
+	})
+
+	t.Run("abandoned claim should be taken over", func(t *testing.T) {
+		fastBank := &acceptingBankService{countingBankService: countingBankService{name: "FastBank"}}
+		service := newTestApplicationService(db, fastBank)
+		applicationID, offers := createCompletedApplicationWithOffers(t, db, "FastBank")
+		require.NoError(t, db.Model(&offers[0]).Updates(map[string]any{
+			"acceptance_status":     dto.OfferAcceptancePending,
+			"acceptance_claimed_at": time.Now().Add(-2 * acceptanceClaimTimeout),
+		}).Error)
+
+		application, err := service.AcceptOffer(ctx, applicationID, offers[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, string(dto.StatusAccepted), application.Status)
+	})
+
+	t.Run("unknown offer should not be found", func(t *testing.T) {
+		service := newTestApplicationService(db)
+		applicationID, _ := createCompletedApplicationWithOffers(t, db)
+
+		_, err := service.AcceptOffer(ctx, applicationID, uuid.New())
+		assert.ErrorIs(t, err, ErrOfferNotFound)
+	})
+}
+
+func TestIsBankRefusal(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "offer expired", err: fmt.Errorf("FastBank offer acceptance failed: %w", &HTTPError{StatusCode: 409}), expected: true},
+		{name: "offer withdrawn", err: &HTTPError{StatusCode: 410}, expected: true},
+		{name: "offer rejected", err: &HTTPError{StatusCode: 422}, expected: true},
+		{name: "bad request", err: &HTTPError{StatusCode: 400}, expected: false},
+		{name: "unauthorized", err: &HTTPError{StatusCode: 401}, expected: false},
+		{name: "server error", err: &HTTPError{StatusCode: 500}, expected: false},
+		{name: "cancelled", err: fmt.Errorf("HTTP request failed: %w", context.Canceled), expected: false},
+		{name: "decode error", err: errors.New("failed to decode response"), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isBankRefusal(tt.err))
+		})
+	}
+}
+
+func TestApplicationService_CancelApplication(t *testing.T) {
+	db := testutil.OpenPostgres(t)
+	ctx := context.Background()
+
+	t.Run("cancellation should stop polling and withdraw from banks", func(t *testing.T) {
+		fastBank := &cancellingBankService{countingBankService: countingBankService{name: "FastBank"}}
+		solidBank := &cancellingBankService{
+			countingBankService: countingBankService{name: "SolidBank"},
+			cancelErr:           &HTTPError{StatusCode: 503, Body: "unavailable"},
+		}
+		service := newTestApplicationService(db, fastBank, solidBank)
+
+		applicationID := createProcessingApplication(t, db)
+		createDraftSubmission(t, db, applicationID, "FastBank", time.Now())
+		createDraftSubmission(t, db, applicationID, "SolidBank", time.Now())
+
+		application, err := service.CancelApplication(ctx, applicationID)
+		require.NoError(t, err)
+
+		assert.Equal(t, string(dto.StatusCancelled), application.Status)
+		assert.Len(t, fastBank.cancelled, 1)
+		assert.Len(t, solidBank.cancelled, 1)
+
+		require.Len(t, application.BankSubmissions, 2)
+		for _, submission := range application.BankSubmissions {
+			assert.Equal(t, string(dto.SubmissionStatusCancelled), submission.Status)
+			assert.Nil(t, submission.NextPollAt)
+
+			if submission.BankName == "FastBank" {
+				assert.NotNil(t, submission.WithdrawnAt)
+				assert.Nil(t, submission.WithdrawalError)
+			} else {
+				assert.Nil(t, submission.WithdrawnAt)
+				require.NotNil(t, submission.WithdrawalError)
+				assert.Contains(t, *submission.WithdrawalError, "503")
+			}
+		}
+	})
+
+	t.Run("banks without a cancellation API should be skipped", func(t *testing.T) {
+		bank := &countingBankService{name: "FastBank"}
+		service := newTestApplicationService(db, bank)
+
+		applicationID := createProcessingApplication(t, db)
+		createDraftSubmission(t, db, applicationID, "FastBank", time.Now())
+
+		application, err := service.CancelApplication(ctx, applicationID)
+		require.NoError(t, err)
+
+		require.Len(t, application.BankSubmissions, 1)
+		assert.Equal(t, string(dto.SubmissionStatusCancelled), application.BankSubmissions[0].Status)
+		assert.Nil(t, application.BankSubmissions[0].WithdrawnAt)
+		assert.Nil(t, application.BankSubmissions[0].WithdrawalError)
+	})
+
+	t.Run("accepted application should not be cancelled", func(t *testing.T) {
+		service := newTestApplicationService(db)
+		applicationID := createProcessingApplication(t, db)
+		require.NoError(t, db.Model(&models.Application{}).Where("id = ?", applicationID).Update("status", dto.StatusAccepted).Error)
+
+		_, err := service.CancelApplication(ctx, applicationID)
+		var transitionErr *InvalidTransitionError
+		require.ErrorAs(t, err, &transitionErr)
+		assert.Equal(t, dto.StatusAccepted, transitionErr.From)
+	})
+}
+
+func velocityApplication(email string) *dto.CustomerApplication {
+	return mappers.ToCustomerApplicationFromRequest(&dto.ApplicationRequest{
+		Phone:           "+37120000000",
+		Email:           email,
+		MonthlyIncome:   eur("2000"),
+		MonthlyExpenses: eur("500"),
+		MaritalStatus:   "SINGLE",
+		AgreeToBeScored: true,
+		Amount:          eur("5000"),
+	})
+}
+
+func createVelocityApplication(t *testing.T, db *gorm.DB, email string, status dto.ApplicationStatus) uuid.UUID {
+	t.Helper()
+
+	app := mappers.ToApplicationModel(velocityApplication(email))
+	app.Status = string(status)
+	require.NoError(t, db.Create(app).Error)
+	return app.ID
+}
+
+func TestApplicationService_SubmitApplication_Velocity(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("duplicate of an application in progress should be linked", func(t *testing.T) {
+		db := testutil.OpenPostgres(t)
+		existingID := createVelocityApplication(t, db, "john@example.com", dto.StatusProcessing)
+		service := newVelocityTestApplicationService(db, config.VelocityConfig{LimitAction: config.VelocityActionOff, DuplicateAction: config.VelocityActionLink})
+
+		response, err := service.SubmitApplication(ctx, velocityApplication("John@Example.com"))
+		require.NoError(t, err)
+
+		assert.Equal(t, existingID, response.ID)
+		assert.Equal(t, dto.StatusProcessing, response.Status)
+		assert.Equal(t, dto.LinkReasonDuplicate, response.LinkedReason)
+	})
+
+	t.Run("duplicate of an application in progress should be rejected", func(t *testing.T) {
+		db := testutil.OpenPostgres(t)
+		existingID := createVelocityApplication(t, db, "john@example.com", dto.StatusPending)
+		service := newVelocityTestApplicationService(db, config.VelocityConfig{LimitAction: config.VelocityActionOff, DuplicateAction: config.VelocityActionReject})
+
+		_, err := service.SubmitApplication(ctx, velocityApplication("john@example.com"))
+
+		var duplicate *DuplicateApplicationError
+		require.ErrorAs(t, err, &duplicate)
+		assert.Equal(t, existingID, duplicate.ApplicationID)
+	})
+
+	t.Run("applications over the phone limit should be rejected", func(t *testing.T) {
+		db := testutil.OpenPostgres(t)
+		createVelocityApplication(t, db, "first@example.com", dto.StatusCompleted)
+		createVelocityApplication(t, db, "second@example.com", dto.StatusFailed)
+		service := newVelocityTestApplicationService(db, config.VelocityConfig{
+			WindowMinutes:   60,
+			MaxPerPhone:     2,
+			LimitAction:     config.VelocityActionReject,
+			DuplicateAction: config.VelocityActionReject,
+		})
+
+		_, err := service.SubmitApplication(ctx, velocityApplication("third@example.com"))
+
+		var velocity *VelocityError
+		require.ErrorAs(t, err, &velocity)
+		assert.Equal(t, VelocityRulePhone, velocity.Rule)
+		assert.Equal(t, 2, velocity.Limit)
+		assert.Equal(t, time.Hour, velocity.Window)
+	})
+
+	t.Run("applications over the IP limit should be rejected", func(t *testing.T) {
+		db := testutil.OpenPostgres(t)
+		clientIP := "203.0.113.7"
+		other := mappers.ToApplicationModel(velocityApplication("other@example.com"))
+		other.Phone = "+37120000001"
+		other.ClientIP = &clientIP
+		require.NoError(t, db.Create(other).Error)
+		service := newVelocityTestApplicationService(db, config.VelocityConfig{
+			WindowMinutes:   60,
+			MaxPerIP:        1,
+			LimitAction:     config.VelocityActionReject,
+			DuplicateAction: config.VelocityActionLink,
+		})
+
+		application := velocityApplication("john@example.com")
+		application.ClientIP = clientIP
+		_, err := service.SubmitApplication(ctx, application)
+
+		var velocity *VelocityError
+		require.ErrorAs(t, err, &velocity)
+		assert.Equal(t, VelocityRuleIP, velocity.Rule)
+	})
+
+	t.Run("concurrent applications from one IP should respect the limit", func(t *testing.T) {
+		db := testutil.OpenPostgres(t)
+		service := newVelocityTestApplicationService(db, config.VelocityConfig{
+			WindowMinutes:   60,
+			MaxPerIP:        1,
+			LimitAction:     config.VelocityActionReject,
+			DuplicateAction: config.VelocityActionOff,
+		})
+
+		const requests = 5
+		var wg sync.WaitGroup
+		for i := range requests {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				application := velocityApplication(fmt.Sprintf("customer%d@example.com", i))
+				application.CustomerData.Phone = fmt.Sprintf("+3712000001%d", i)
+				application.ClientIP = "203.0.113.8"
+				_, _ = service.SubmitApplication(ctx, application)
+			}()
+		}
+		wg.Wait()
+
+		var count int64
+		require.NoError(t, db.Model(&models.Application{}).Where("client_ip = ?", "203.0.113.8").Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("concurrent duplicates should create one application", func(t *testing.T) {
+		db := testutil.OpenPostgres(t)
+		service := newVelocityTestApplicationService(db, config.VelocityConfig{LimitAction: config.VelocityActionOff, DuplicateAction: config.VelocityActionLink})
+
+		const requests = 5
+		responses := make([]*dto.ApplicationResponse, requests)
+		var wg sync.WaitGroup
+		for i := range requests {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				response, err := service.SubmitApplication(ctx, velocityApplication("john@example.com"))
+				assert.NoError(t, err)
+				responses[i] = response
+			}()
+		}
+		wg.Wait()
+
+		var count int64
+		require.NoError(t, db.Model(&models.Application{}).Where("phone = ?", "+37120000000").Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+
+		linked := 0
+		for _, response := range responses {
+			require.NotNil(t, response)
+			if response.LinkedReason == dto.LinkReasonDuplicate {
+				linked++
+			}
+		}
+		assert.Equal(t, requests-1, linked)
+	})
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+type InvalidTransitionError struct {
+	From dto.ApplicationStatus
+	To   dto.ApplicationStatus
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("invalid application status transition from %s to %s", e.From, e.To)
+}
+
+var applicationTransitions = map[dto.ApplicationStatus][]dto.ApplicationStatus{
+	dto.StatusPending:    {dto.StatusProcessing, dto.StatusFailed, dto.StatusCancelled, dto.StatusExpired},
+	dto.StatusProcessing: {dto.StatusCompleted, dto.StatusFailed, dto.StatusCancelled, dto.StatusExpired},
+	dto.StatusCompleted:  {dto.StatusCancelled, dto.StatusAccepted},
+}
+
+func CanTransition(from, to dto.ApplicationStatus) bool {
+	return slices.Contains(applicationTransitions[from], to)
+}
+
+// IsProcessingFinished reports whether the banks are done with an
+// application, i.e. it is neither pending nor processing. The status can
+// still change afterwards, e.g. when a completed application is cancelled.
+func IsProcessingFinished(status dto.ApplicationStatus) bool {
+	return status != dto.StatusPending && status != dto.StatusProcessing
+}
+
+type ApplicationStateMachine struct {
+	applicationsRepo *repository.ApplicationsRepository
+	transactor       *repository.Transactor
+	events           *ApplicationEventRecorder
+	logger           *logrus.Logger
+}
+
+func NewApplicationStateMachine(
+	applicationsRepo *repository.ApplicationsRepository,
+	transactor *repository.Transactor,
+	events *ApplicationEventRecorder,
+	logger *logrus.Logger,
+) *ApplicationStateMachine {
+	return &ApplicationStateMachine{
+		applicationsRepo: applicationsRepo,
+		transactor:       transactor,
+		events:           events,
+		logger:           logger,
+	}
+}
+
+// Transition moves the application from one status to another and records
+// the change in its history. Illegal transitions fail with an
+// InvalidTransitionError; false without an error means the application was
+// no longer in the from status.
+func (m *ApplicationStateMachine) Transition(ctx context.Context, applicationID uuid.UUID, from, to dto.ApplicationStatus, actor dto.EventActor, reason string) (bool, error) {
+	if !CanTransition(from, to) {
+		return false, &InvalidTransitionError{From: from, To: to}
+	}
+
+	var updated bool
+	err := m.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		updated, err = m.applicationsRepo.UpdateStatus(ctx, applicationID, string(from), string(to))
+		if err != nil {
+			return fmt.Errorf("failed to update application status to %s: %w", to, err)
+		}
+		if !updated {
+			return nil
+		}
+		return m.events.ApplicationStatusChanged(ctx, applicationID, from, to, actor, reason)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if updated {
+		m.logger.WithFields(logrus.Fields{
+			"application_id": applicationID,
+			"from_status":    from,
+			"to_status":      to,
+			"actor":          actor,
+		}).Info("Application status changed")
+	}
+
+	return updated, nil
+}
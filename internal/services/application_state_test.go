@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     dto.ApplicationStatus
+		to       dto.ApplicationStatus
+		expected bool
+	}{
+		{name: "pending to processing", from: dto.StatusPending, to: dto.StatusProcessing, expected: true},
+		{name: "pending to expired", from: dto.StatusPending, to: dto.StatusExpired, expected: true},
+		{name: "processing to completed", from: dto.StatusProcessing, to: dto.StatusCompleted, expected: true},
+		{name: "processing to failed", from: dto.StatusProcessing, to: dto.StatusFailed, expected: true},
+		{name: "processing to cancelled", from: dto.StatusProcessing, to: dto.StatusCancelled, expected: true},
+		{name: "completed to cancelled", from: dto.StatusCompleted, to: dto.StatusCancelled, expected: true},
+		{name: "completed to accepted", from: dto.StatusCompleted, to: dto.StatusAccepted, expected: true},
+		{name: "processing to accepted", from: dto.StatusProcessing, to: dto.StatusAccepted, expected: false},
+		{name: "accepted is terminal", from: dto.StatusAccepted, to: dto.StatusAccepted, expected: false},
+		{name: "pending to completed", from: dto.StatusPending, to: dto.StatusCompleted, expected: false},
+		{name: "completed to processing", from: dto.StatusCompleted, to: dto.StatusProcessing, expected: false},
+		{name: "failed is terminal", from: dto.StatusFailed, to: dto.StatusProcessing, expected: false},
+		{name: "cancelled is terminal", from: dto.StatusCancelled, to: dto.StatusCompleted, expected: false},
+		{name: "expired is terminal", from: dto.StatusExpired, to: dto.StatusProcessing, expected: false},
+		{name: "same status", from: dto.StatusProcessing, to: dto.StatusProcessing, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CanTransition(tt.from, tt.to))
+		})
+	}
+}
+
+func TestApplicationStateMachine_InvalidTransition(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	machine := NewApplicationStateMachine(nil, nil, nil, logger)
+
+	updated, err := machine.Transition(context.Background(), uuid.New(), dto.StatusFailed, dto.StatusCompleted, dto.EventActorSubmissionProcessor, "")
+	assert.False(t, updated)
+
+	var transitionErr *InvalidTransitionError
+	require.ErrorAs(t, err, &transitionErr)
+	assert.Equal(t, dto.StatusFailed, transitionErr.From)
+	assert.Equal(t, dto.StatusCompleted, transitionErr.To)
+	assert.Equal(t, "invalid application status transition from FAILED to COMPLETED", err.Error())
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	updatesReconnectMinDelay = time.Second
+	updatesReconnectMaxDelay = 30 * time.Second
+)
+
+// ApplicationUpdateHub fans out application change notifications to local
+// subscribers such as open status streams. Changes are announced through
+// Postgres NOTIFY, so subscribers hear about changes made by any replica.
+type ApplicationUpdateHub struct {
+	listener    *repository.NotificationListener
+	logger      *logrus.Logger
+	subscribers map[uuid.UUID]map[chan struct{}]struct{}
+	subMu       sync.Mutex
+	done        chan struct{}
+	ctx         context.Context
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+	running     bool
+	mu          sync.RWMutex
+}
+
+func NewApplicationUpdateHub(listener *repository.NotificationListener, logger *logrus.Logger) *ApplicationUpdateHub {
+	return &ApplicationUpdateHub{
+		listener:    listener,
+		logger:      logger,
+		subscribers: make(map[uuid.UUID]map[chan struct{}]struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+func (h *ApplicationUpdateHub) Start() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.running {
+		return nil
+	}
+
+	h.logger.Info("Starting application update hub")
+
+	h.ctx, h.cancel = context.WithCancel(context.Background())
+	h.running = true
+
+	h.wg.Add(1)
+	go h.run()
+
+	return nil
+}
+
+func (h *ApplicationUpdateHub) Stop() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.running {
+		return nil
+	}
+
+	h.logger.Info("Stopping application update hub")
+
+	h.cancel()
+	h.wg.Wait()
+	h.running = false
+	close(h.done)
+
+	h.logger.Info("Application update hub stopped")
+	return nil
+}
+
+// Subscribe returns a channel that receives a signal whenever the
+// application changes, and a function that ends the subscription. Signals
+// are coalesced, so subscribers should re-read the application on each one.
+func (h *ApplicationUpdateHub) Subscribe(applicationID uuid.UUID) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	h.subMu.Lock()
+	if h.subscribers[applicationID] == nil {
+		h.subscribers[applicationID] = make(map[chan struct{}]struct{})
+	}
+	h.subscribers[applicationID][ch] = struct{}{}
+	h.subMu.Unlock()
+
+	return ch, func() {
+		h.subMu.Lock()
+		defer h.subMu.Unlock()
+
+		delete(h.subscribers[applicationID], ch)
+		if len(h.subscribers[applicationID]) == 0 {
+			delete(h.subscribers, applicationID)
+		}
+	}
+}
+
+// Done is closed when the hub stops, telling subscribers to end their
+// streams so the server can shut down.
+func (h *ApplicationUpdateHub) Done() <-chan struct{} {
+	return h.done
+}
+
+// Notify signals the local subscribers of an application.
+func (h *ApplicationUpdateHub) Notify(applicationID uuid.UUID) {
+	h.subMu.Lock()
+	defer h.subMu.Unlock()
+
+	for ch := range h.subscribers[applicationID] {
+		notifySubscriber(ch)
+	}
+}
+
+func (h *ApplicationUpdateHub) notifyAll() {
+	h.subMu.Lock()
+	defer h.subMu.Unlock()
+
+	for _, subscribers := range h.subscribers {
+		for ch := range subscribers {
+			notifySubscriber(ch)
+		}
+	}
+}
+
+func (h *ApplicationUpdateHub) run() {
+	defer h.wg.Done()
+
+	logger := h.logger.WithField("component", "application_update_hub")
+	delay := updatesReconnectMinDelay
+
+	for {
+		err := h.listener.Listen(h.ctx, repository.ApplicationUpdatesChannel, func() {
+			logger.Info("Listening for application updates")
+			delay = updatesReconnectMinDelay
+			// Changes made while the listener was down were missed.
+			h.notifyAll()
+		}, func(payload string) {
+			applicationID, err := uuid.Parse(payload)
+			if err != nil {
+				logger.WithField("payload", payload).Warn("Ignoring malformed application update")
+				return
+			}
+			h.Notify(applicationID)
+		})
+
+		if h.ctx.Err() != nil {
+			return
+		}
+
+		logger.WithError(err).WithField("retry_in", delay).Warn("Application update listener disconnected")
+
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, updatesReconnectMaxDelay)
+	}
+}
+
+func notifySubscriber(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplicationUpdateHub(t *testing.T) {
+	hub := NewApplicationUpdateHub(nil, logrus.New())
+	applicationID := uuid.New()
+
+	first, unsubscribeFirst := hub.Subscribe(applicationID)
+	second, unsubscribeSecond := hub.Subscribe(applicationID)
+	other, unsubscribeOther := hub.Subscribe(uuid.New())
+	defer unsubscribeOther()
+
+	t.Run("notifications should reach only the application's subscribers and be coalesced", func(t *testing.T) {
+		hub.Notify(applicationID)
+		hub.Notify(applicationID)
+
+		assert.Len(t, first, 1)
+		assert.Len(t, second, 1)
+		assert.Len(t, other, 0)
+
+		<-first
+		<-second
+	})
+
+	t.Run("unsubscribed channels should not be notified", func(t *testing.T) {
+		unsubscribeFirst()
+		hub.Notify(applicationID)
+
+		assert.Len(t, first, 0)
+		assert.Len(t, second, 1)
+
+		unsubscribeSecond()
+		assert.Empty(t, hub.subscribers[applicationID])
+	})
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+type BankServiceFactory func(config config.BankConfig, logger *logrus.Logger) BankService
+
+var bankAdapters = map[string]BankServiceFactory{
+	"fastbank":  NewFastBankService,
+	"solidbank": NewSolidBankService,
+	"json":      NewJSONBankService,
+}
+
+func NewBankServices(banks []config.BankConfig, logger *logrus.Logger) ([]BankService, error) {
+	var bankServices []BankService
+	registered := make(map[string]bool)
+
+	for _, bank := range banks {
+		logger := logger.WithFields(logrus.Fields{
+			"bank":    bank.Name,
+			"adapter": bank.Adapter,
+		})
+
+		if registered[bank.Name] {
+			return nil, fmt.Errorf("bank %s is configured more than once", bank.Name)
+		}
+		registered[bank.Name] = true
+
+		factory, ok := bankAdapters[bank.Adapter]
+		if !ok {
+			return nil, fmt.Errorf("unknown adapter %q for bank %s", bank.Adapter, bank.Name)
+		}
+
+		if !bank.Enabled {
+			logger.Warn("Bank disabled, skipping integration")
+			continue
+		}
+
+		if bank.BaseURL == "" {
+			logger.Warn("Bank BaseURL not configured, skipping integration")
+			continue
+		}
+
+		if bank.Adapter == "json" && bank.Mapping == nil {
+			return nil, fmt.Errorf("bank %s uses the json adapter but has no mapping file", bank.Name)
+		}
+
+		bankService := factory(bank, logger.Logger)
+		if bank.CircuitBreaker.Enabled {
+			bankService = NewCircuitBreakerBankService(bankService, bank.CircuitBreaker, logger.Logger)
+		}
+
+		bankServices = append(bankServices, bankService)
+		logger.Info("Bank service initialized")
+	}
+
+	return bankServices, nil
+}
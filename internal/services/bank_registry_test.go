@@ -0,0 +1,85 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBankServices(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	tests := []struct {
+		name          string
+		banks         []config.BankConfig
+		expectedNames []string
+		expectedError string
+	}{
+		{
+			name: "enabled banks should be built by adapter type",
+			banks: []config.BankConfig{
+				{Name: "FastBank", Adapter: "fastbank", BaseURL: "https://fastbank.example.com", Timeout: 30, Enabled: true},
+				{Name: "SolidBank", Adapter: "solidbank", BaseURL: "https://solidbank.example.com", Timeout: 30, Enabled: true},
+				{Name: "TrustBank", Adapter: "fastbank", BaseURL: "https://trustbank.example.com", Timeout: 30, Enabled: true},
+				{Name: "JsonBank", Adapter: "json", BaseURL: "https://jsonbank.example.com", Timeout: 30, Enabled: true, Mapping: &config.BankMapping{}},
+			},
+			expectedNames: []string{"FastBank", "SolidBank", "TrustBank", "JsonBank"},
+		},
+		{
+			name: "disabled banks and banks without base URL should be skipped",
+			banks: []config.BankConfig{
+				{Name: "FastBank", Adapter: "fastbank", BaseURL: "https://fastbank.example.com", Timeout: 30, Enabled: false},
+				{Name: "SolidBank", Adapter: "solidbank", BaseURL: "", Timeout: 30, Enabled: true},
+				{Name: "TrustBank", Adapter: "solidbank", BaseURL: "https://trustbank.example.com", Timeout: 30, Enabled: true},
+			},
+			expectedNames: []string{"TrustBank"},
+		},
+		{
+			name: "unknown adapter should fail",
+			banks: []config.BankConfig{
+				{Name: "FastBank", Adapter: "unknown", BaseURL: "https://fastbank.example.com", Timeout: 30, Enabled: true},
+			},
+			expectedError: `unknown adapter "unknown" for bank FastBank`,
+		},
+		{
+			name: "json adapter without mapping should fail",
+			banks: []config.BankConfig{
+				{Name: "TrustBank", Adapter: "json", BaseURL: "https://trustbank.example.com", Timeout: 30, Enabled: true},
+			},
+			expectedError: "bank TrustBank uses the json adapter but has no mapping file",
+		},
+		{
+			name: "duplicate bank names should fail",
+			banks: []config.BankConfig{
+				{Name: "FastBank", Adapter: "fastbank", BaseURL: "https://fastbank.example.com", Timeout: 30, Enabled: false},
+				{Name: "FastBank", Adapter: "fastbank", BaseURL: "https://fastbank.example.com", Timeout: 30, Enabled: true},
+			},
+			expectedError: "bank FastBank is configured more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bankServices, err := NewBankServices(tt.banks, logger)
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				assert.Nil(t, bankServices)
+				return
+			}
+
+			require.NoError(t, err)
+
+			names := make([]string, len(bankServices))
+			for i, bankService := range bankServices {
+				names[i] = bankService.GetBankName()
+			}
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+}
@@ -2,12 +2,77 @@ package services
 
 import (
 	"context"
+	"errors"
 
+	"github.com/lielamurs/aggregator/internal/config"
 	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/sirupsen/logrus"
 )
 
+// BankService is a partner bank's API. The idempotencyKey passed to
+// SubmitApplication must stay the same when the same submission is retried
+// or re-run, so a bank that deduplicates by key creates one application.
 type BankService interface {
 	GetBankName() string
-	SubmitApplication(ctx context.Context, req dto.ApplicationRequest) (*dto.BankSubmissionResponse, error)
+	SubmitApplication(ctx context.Context, req dto.ApplicationRequest, idempotencyKey string) (*dto.BankSubmissionResponse, error)
 	GetOffer(ctx context.Context, bankID string) (*dto.Offer, error)
 }
+
+var ErrOfferAcceptanceNotSupported = errors.New("bank does not support offer acceptance")
+
+// OfferAcceptor is implemented by banks that take the customer's acceptance
+// of an offer through their API. AcceptOffer returns an error when the bank
+// refuses to go ahead with the offer.
+type OfferAcceptor interface {
+	AcceptOffer(ctx context.Context, bankID string) error
+}
+
+// acceptOffer forwards the acceptance to the bank, failing with
+// ErrOfferAcceptanceNotSupported if it has no acceptance API.
+func acceptOffer(ctx context.Context, bank BankService, bankID string) error {
+	acceptor, ok := bank.(OfferAcceptor)
+	if !ok {
+		return ErrOfferAcceptanceNotSupported
+	}
+	return acceptor.AcceptOffer(ctx, bankID)
+}
+
+var ErrApplicationCancellationNotSupported = errors.New("bank does not support application cancellation")
+
+// ApplicationCanceller is implemented by banks that let a submitted
+// application be withdrawn through their API.
+type ApplicationCanceller interface {
+	CancelApplication(ctx context.Context, bankID string) error
+}
+
+// cancelApplication withdraws the application from the bank, failing with
+// ErrApplicationCancellationNotSupported if it has no cancellation API.
+func cancelApplication(ctx context.Context, bank BankService, bankID string) error {
+	canceller, ok := bank.(ApplicationCanceller)
+	if !ok {
+		return ErrApplicationCancellationNotSupported
+	}
+	return canceller.CancelApplication(ctx, bankID)
+}
+
+// submitRequestOptions makes POSTs to the bank retryable when it is
+// configured to deduplicate them, sending key in its idempotency header.
+func submitRequestOptions(cfg config.BankConfig, key string) []RequestOption {
+	var opts []RequestOption
+	if cfg.Retry.IdempotentSubmit {
+		opts = append(opts, WithIdempotentRetries())
+	}
+	if cfg.Retry.IdempotencyHeader != "" && key != "" {
+		opts = append(opts, WithIdempotencyKey(cfg.Retry.IdempotencyHeader, key))
+	}
+	return opts
+}
+
+// warnDroppedLoanFields logs the loan parameters the bank's request has no
+// field for, so the application reaches the bank without them.
+func warnDroppedLoanFields(logger *logrus.Entry, dropped []string) {
+	if len(dropped) == 0 {
+		return
+	}
+	logger.WithField("dropped_fields", dropped).Warn("Bank does not support loan parameters, submitting without them")
+}
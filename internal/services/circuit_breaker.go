@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/sirupsen/logrus"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type CircuitBreakerReporter interface {
+	CircuitBreakerStatus() dto.CircuitBreakerStatus
+}
+
+type CircuitBreaker struct {
+	config   config.CircuitBreakerConfig
+	logger   *logrus.Entry
+	now      func() time.Time
+	mu       sync.Mutex
+	state    dto.CircuitState
+	window   []bool
+	next     int
+	count    int
+	failures int
+	openedAt time.Time
+	probes   int
+}
+
+func NewCircuitBreaker(config config.CircuitBreakerConfig, logger *logrus.Entry) *CircuitBreaker {
+	return &CircuitBreaker{
+		config: config,
+		logger: logger,
+		now:    time.Now,
+		state:  dto.CircuitStateClosed,
+		window: make([]bool, max(config.WindowSize, 1)),
+	}
+}
+
+// Allow reports whether a call may proceed. Every allowed call must be
+// followed by exactly one Record.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == dto.CircuitStateOpen {
+		if b.now().Before(b.retryAt()) {
+			return ErrCircuitOpen
+		}
+		b.setState(dto.CircuitStateHalfOpen)
+	}
+
+	if b.state == dto.CircuitStateHalfOpen {
+		if b.probes >= max(b.config.HalfOpenRequests, 1) {
+			return ErrCircuitOpen
+		}
+		b.probes++
+	}
+
+	return nil
+}
+
+func (b *CircuitBreaker) Record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == dto.CircuitStateHalfOpen {
+		b.probes = max(b.probes-1, 0)
+		if !success {
+			b.open()
+			return
+		}
+		if b.probes == 0 {
+			b.resetWindow()
+			b.setState(dto.CircuitStateClosed)
+		}
+		return
+	}
+
+	if b.state != dto.CircuitStateClosed {
+		return
+	}
+
+	if b.count == len(b.window) && !b.window[b.next] {
+		b.failures--
+	}
+	b.window[b.next] = success
+	if !success {
+		b.failures++
+	}
+	b.next = (b.next + 1) % len(b.window)
+	b.count = min(b.count+1, len(b.window))
+
+	if b.count >= b.config.MinRequests && b.failureRate() >= b.config.FailureRate {
+		b.open()
+	}
+}
+
+func (b *CircuitBreaker) Status() dto.CircuitBreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := dto.CircuitBreakerStatus{
+		State:       b.state,
+		Requests:    b.count,
+		Failures:    b.failures,
+		FailureRate: b.failureRate(),
+	}
+
+	if b.state != dto.CircuitStateClosed {
+		openedAt := b.openedAt
+		retryAt := b.retryAt()
+		status.OpenedAt = &openedAt
+		status.RetryAt = &retryAt
+	}
+
+	return status
+}
+
+func (b *CircuitBreaker) open() {
+	b.openedAt = b.now()
+	b.probes = 0
+	b.setState(dto.CircuitStateOpen)
+}
+
+func (b *CircuitBreaker) resetWindow() {
+	clear(b.window)
+	b.next = 0
+	b.count = 0
+	b.failures = 0
+}
+
+func (b *CircuitBreaker) setState(state dto.CircuitState) {
+	if b.state == state {
+		return
+	}
+
+	logger := b.logger.WithFields(logrus.Fields{
+		"from_state":   b.state,
+		"to_state":     state,
+		"failures":     b.failures,
+		"requests":     b.count,
+		"failure_rate": b.failureRate(),
+	})
+	b.state = state
+
+	if state == dto.CircuitStateOpen {
+		logger.WithField("retry_at", b.retryAt()).Warn("Circuit breaker opened")
+	} else {
+		logger.Info("Circuit breaker state changed")
+	}
+}
+
+func (b *CircuitBreaker) failureRate() float64 {
+	if b.count == 0 {
+		return 0
+	}
+	return float64(b.failures) / float64(b.count)
+}
+
+func (b *CircuitBreaker) retryAt() time.Time {
+	return b.openedAt.Add(time.Duration(b.config.CooldownSeconds) * time.Second)
+}
+
+type circuitBreakerBankService struct {
+	BankService
+	breaker *CircuitBreaker
+}
+
+func NewCircuitBreakerBankService(bank BankService, config config.CircuitBreakerConfig, logger *logrus.Logger) BankService {
+	return &circuitBreakerBankService{
+		BankService: bank,
+		breaker:     NewCircuitBreaker(config, logger.WithField("bank", bank.GetBankName())),
+	}
+}
+
+func (s *circuitBreakerBankService) SubmitApplication(ctx context.Context, req dto.ApplicationRequest, idempotencyKey string) (*dto.BankSubmissionResponse, error) {
+	if err := s.breaker.Allow(); err != nil {
+		return nil, fmt.Errorf("%s submission rejected: %w", s.GetBankName(), err)
+	}
+
+	response, err := s.BankService.SubmitApplication(ctx, req, idempotencyKey)
+	s.breaker.Record(!isBankUnavailable(err))
+	return response, err
+}
+
+func (s *circuitBreakerBankService) GetOffer(ctx context.Context, bankID string) (*dto.Offer, error) {
+	if err := s.breaker.Allow(); err != nil {
+		return nil, fmt.Errorf("%s get offer rejected: %w", s.GetBankName(), err)
+	}
+
+	offer, err := s.BankService.GetOffer(ctx, bankID)
+	s.breaker.Record(!isBankUnavailable(err))
+	return offer, err
+}
+
+func (s *circuitBreakerBankService) AcceptOffer(ctx context.Context, bankID string) error {
+	if _, ok := s.BankService.(OfferAcceptor); !ok {
+		return ErrOfferAcceptanceNotSupported
+	}
+	if err := s.breaker.Allow(); err != nil {
+		return fmt.Errorf("%s offer acceptance rejected: %w", s.GetBankName(), err)
+	}
+
+	err := acceptOffer(ctx, s.BankService, bankID)
+	s.breaker.Record(!isBankUnavailable(err))
+	return err
+}
+
+func (s *circuitBreakerBankService) CancelApplication(ctx context.Context, bankID string) error {
+	if _, ok := s.BankService.(ApplicationCanceller); !ok {
+		return ErrApplicationCancellationNotSupported
+	}
+	if err := s.breaker.Allow(); err != nil {
+		return fmt.Errorf("%s cancellation rejected: %w", s.GetBankName(), err)
+	}
+
+	err := cancelApplication(ctx, s.BankService, bankID)
+	s.breaker.Record(!isBankUnavailable(err))
+	return err
+}
+
+func (s *circuitBreakerBankService) CircuitBreakerStatus() dto.CircuitBreakerStatus {
+	return s.breaker.Status()
+}
+
+// isBankUnavailable separates outages (network errors, timeouts, 5xx and
+// 429 responses) from errors the bank answered deliberately, which should
+// not trip the breaker.
+func isBankUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500 || httpErr.StatusCode == 429
+	}
+
+	return isNetworkError(err) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBankService struct {
+	name      string
+	submitErr error
+	offerErr  error
+	offer     *dto.Offer
+	calls     int
+}
+
+func (f *fakeBankService) GetBankName() string {
+	return f.name
+}
+
+func (f *fakeBankService) SubmitApplication(ctx context.Context, req dto.ApplicationRequest, idempotencyKey string) (*dto.BankSubmissionResponse, error) {
+	f.calls++
+	if f.submitErr != nil {
+		return nil, f.submitErr
+	}
+	return &dto.BankSubmissionResponse{ID: "bank-id", Status: "RECEIVED"}, nil
+}
+
+func (f *fakeBankService) GetOffer(ctx context.Context, bankID string) (*dto.Offer, error) {
+	f.calls++
+	return f.offer, f.offerErr
+}
+
+func newTestCircuitBreaker(cfg config.CircuitBreakerConfig) (*CircuitBreaker, *time.Time) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(cfg, logrus.NewEntry(logger))
+	breaker.now = func() time.Time { return now }
+	return breaker, &now
+}
+
+func testCircuitBreakerConfig() config.CircuitBreakerConfig {
+	return config.CircuitBreakerConfig{
+		Enabled:          true,
+		WindowSize:       4,
+		MinRequests:      4,
+		FailureRate:      0.5,
+		CooldownSeconds:  30,
+		HalfOpenRequests: 1,
+	}
+}
+
+func record(t *testing.T, breaker *CircuitBreaker, results ...bool) {
+	t.Helper()
+
+	for _, success := range results {
+		require.NoError(t, breaker.Allow())
+		breaker.Record(success)
+	}
+}
+
+func TestCircuitBreaker_Opens(t *testing.T) {
+	t.Run("breaker should stay closed below min requests", func(t *testing.T) {
+		breaker, _ := newTestCircuitBreaker(testCircuitBreakerConfig())
+
+		record(t, breaker, false, false, false)
+		assert.Equal(t, dto.CircuitStateClosed, breaker.Status().State)
+		assert.NoError(t, breaker.Allow())
+	})
+
+	t.Run("breaker should open when failure rate reaches threshold", func(t *testing.T) {
+		breaker, now := newTestCircuitBreaker(testCircuitBreakerConfig())
+
+		record(t, breaker, true, false, true, false)
+
+		status := breaker.Status()
+		assert.Equal(t, dto.CircuitStateOpen, status.State)
+		assert.Equal(t, 0.5, status.FailureRate)
+		require.NotNil(t, status.OpenedAt)
+		require.NotNil(t, status.RetryAt)
+		assert.Equal(t, *now, *status.OpenedAt)
+		assert.Equal(t, now.Add(30*time.Second), *status.RetryAt)
+		assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+	})
+
+	t.Run("old results should slide out of the window", func(t *testing.T) {
+		breaker, _ := newTestCircuitBreaker(testCircuitBreakerConfig())
+
+		record(t, breaker, false, true, true, true, true)
+
+		status := breaker.Status()
+		assert.Equal(t, dto.CircuitStateClosed, status.State)
+		assert.Equal(t, 4, status.Requests)
+		assert.Equal(t, 0, status.Failures)
+
+		record(t, breaker, false)
+		assert.Equal(t, 1, breaker.Status().Failures)
+	})
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	openBreaker := func(t *testing.T) (*CircuitBreaker, *time.Time) {
+		breaker, now := newTestCircuitBreaker(testCircuitBreakerConfig())
+		record(t, breaker, false, false, false, false)
+		require.Equal(t, dto.CircuitStateOpen, breaker.Status().State)
+		return breaker, now
+	}
+
+	t.Run("breaker should allow a single probe after cooldown", func(t *testing.T) {
+		breaker, now := openBreaker(t)
+
+		*now = now.Add(29 * time.Second)
+		assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+
+		*now = now.Add(time.Second)
+		require.NoError(t, breaker.Allow())
+		assert.Equal(t, dto.CircuitStateHalfOpen, breaker.Status().State)
+		assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+	})
+
+	t.Run("successful probe should close the breaker", func(t *testing.T) {
+		breaker, now := openBreaker(t)
+
+		*now = now.Add(30 * time.Second)
+		require.NoError(t, breaker.Allow())
+		breaker.Record(true)
+
+		status := breaker.Status()
+		assert.Equal(t, dto.CircuitStateClosed, status.State)
+		assert.Equal(t, 0, status.Requests)
+		assert.Nil(t, status.OpenedAt)
+	})
+
+	t.Run("failed probe should reopen the breaker", func(t *testing.T) {
+		breaker, now := openBreaker(t)
+
+		*now = now.Add(30 * time.Second)
+		require.NoError(t, breaker.Allow())
+		breaker.Record(false)
+
+		status := breaker.Status()
+		assert.Equal(t, dto.CircuitStateOpen, status.State)
+		assert.Equal(t, *now, *status.OpenedAt)
+		assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+	})
+}
+
+func TestCircuitBreakerBankService(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	t.Run("outages should open the breaker and fail fast", func(t *testing.T) {
+		bank := &fakeBankService{name: "FastBank", submitErr: &HTTPError{StatusCode: 503}}
+		service := NewCircuitBreakerBankService(bank, testCircuitBreakerConfig(), logger)
+
+		for i := 0; i < 4; i++ {
+			_, err := service.SubmitApplication(context.Background(), dto.ApplicationRequest{}, "")
+			require.Error(t, err)
+			assert.NotErrorIs(t, err, ErrCircuitOpen)
+		}
+
+		_, err := service.GetOffer(context.Background(), "bank-id")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.Equal(t, 4, bank.calls)
+
+		reporter, ok := service.(CircuitBreakerReporter)
+		require.True(t, ok)
+		assert.Equal(t, dto.CircuitStateOpen, reporter.CircuitBreakerStatus().State)
+		assert.Equal(t, "FastBank", service.GetBankName())
+	})
+
+	t.Run("client errors should not open the breaker", func(t *testing.T) {
+		bank := &fakeBankService{name: "FastBank", submitErr: &HTTPError{StatusCode: 400}}
+		service := NewCircuitBreakerBankService(bank, testCircuitBreakerConfig(), logger)
+
+		for i := 0; i < 6; i++ {
+			_, err := service.SubmitApplication(context.Background(), dto.ApplicationRequest{}, "")
+			require.Error(t, err)
+			assert.NotErrorIs(t, err, ErrCircuitOpen)
+		}
+
+		assert.Equal(t, 6, bank.calls)
+		assert.Equal(t, dto.CircuitStateClosed, service.(CircuitBreakerReporter).CircuitBreakerStatus().State)
+	})
+
+	t.Run("optional capabilities should not be supported unless the bank has them", func(t *testing.T) {
+		bank := &fakeBankService{name: "FastBank"}
+		service := NewCircuitBreakerBankService(bank, testCircuitBreakerConfig(), logger)
+
+		err := acceptOffer(context.Background(), service, "bank-id")
+		assert.ErrorIs(t, err, ErrOfferAcceptanceNotSupported)
+		err = cancelApplication(context.Background(), service, "bank-id")
+		assert.ErrorIs(t, err, ErrApplicationCancellationNotSupported)
+		assert.Equal(t, 0, service.(CircuitBreakerReporter).CircuitBreakerStatus().Requests)
+	})
+}
+
+func TestIsBankUnavailable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "no error", err: nil, expected: false},
+		{name: "server error", err: fmt.Errorf("submission failed: %w", &HTTPError{StatusCode: 500}), expected: true},
+		{name: "rate limited", err: &HTTPError{StatusCode: 429}, expected: true},
+		{name: "bad request", err: &HTTPError{StatusCode: 400}, expected: false},
+		{name: "deadline exceeded", err: fmt.Errorf("HTTP request failed: %w", context.DeadlineExceeded), expected: true},
+		{name: "mapping error", err: errors.New("failed to map FastBank application"), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isBankUnavailable(tt.err))
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/mappers"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/money"
+)
+
+const (
+	RuleCurrency      = "CURRENCY"
+	RuleAmountRange   = "AMOUNT_RANGE"
+	RuleMinIncome     = "MIN_INCOME"
+	RuleExpenseRatio  = "EXPENSE_RATIO"
+	RuleMaxDependents = "MAX_DEPENDENTS"
+	RuleMaritalStatus = "MARITAL_STATUS"
+	RuleTermRange     = "TERM_RANGE"
+)
+
+// EligibilityRule is one of a bank's lending criteria. Check returns why the
+// application does not meet it, or an empty string if it does.
+type EligibilityRule struct {
+	Name  string
+	Check func(application *models.Application) string
+}
+
+// IneligibleError reports the first rule an application failed for a bank.
+type IneligibleError struct {
+	Rule   string
+	Reason string
+}
+
+func (e *IneligibleError) Error() string {
+	return e.Rule + ": " + e.Reason
+}
+
+// EligibilityRules holds the rules of every configured bank, keyed by bank
+// name.
+type EligibilityRules map[string][]EligibilityRule
+
+func NewEligibilityRules(banks []config.BankConfig) EligibilityRules {
+	rules := make(EligibilityRules, len(banks))
+	for _, bank := range banks {
+		rules[bank.Name] = NewBankEligibilityRules(bank.Eligibility)
+	}
+	return rules
+}
+
+// Check evaluates the bank's rules in order and returns an IneligibleError
+// for the first one the application fails. Banks without rules accept every
+// application.
+func (r EligibilityRules) Check(bankName string, application *models.Application) error {
+	for _, rule := range r[bankName] {
+		if reason := rule.Check(application); reason != "" {
+			return &IneligibleError{Rule: rule.Name, Reason: reason}
+		}
+	}
+	return nil
+}
+
+// NewBankEligibilityRules builds the rules for the limits set in cfg. The
+// bank only lends in its own currency, so applications in any other currency
+// are ineligible whatever the limits.
+func NewBankEligibilityRules(cfg config.EligibilityConfig) []EligibilityRule {
+	currency := cfg.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+
+	rules := []EligibilityRule{{Name: RuleCurrency, Check: func(application *models.Application) string {
+		if applicationCurrency := mappers.ApplicationCurrency(application); applicationCurrency != currency {
+			return fmt.Sprintf("currency %s is not %s", applicationCurrency, currency)
+		}
+		return ""
+	}}}
+
+	if cfg.MinAmount > 0 || cfg.MaxAmount > 0 {
+		rules = append(rules, EligibilityRule{Name: RuleAmountRange, Check: func(application *models.Application) string {
+			return checkAmountRange(applicationAmount(application, application.Amount), cfg.MinAmount, cfg.MaxAmount, currency)
+		}})
+	}
+
+	if cfg.MinIncome > 0 {
+		rules = append(rules, EligibilityRule{Name: RuleMinIncome, Check: func(application *models.Application) string {
+			income := applicationAmount(application, application.MonthlyIncome)
+			minIncome, cmp, err := compareToLimit(income, cfg.MinIncome, currency)
+			if err != nil {
+				return err.Error()
+			}
+			if cmp < 0 {
+				return fmt.Sprintf("monthly income %s is below %s", income, minIncome)
+			}
+			return ""
+		}})
+	}
+
+	if cfg.MaxExpenseRatio > 0 {
+		rules = append(rules, EligibilityRule{Name: RuleExpenseRatio, Check: func(application *models.Application) string {
+			income, expenses := application.MonthlyIncome.Float64(), application.MonthlyExpenses.Float64()
+			if income <= 0 {
+				return "monthly income must be positive"
+			}
+			if ratio := expenses / income; ratio > cfg.MaxExpenseRatio {
+				return fmt.Sprintf("expenses are %.2f of income, above %.2f", ratio, cfg.MaxExpenseRatio)
+			}
+			return ""
+		}})
+	}
+
+	if cfg.MaxDependents >= 0 {
+		rules = append(rules, EligibilityRule{Name: RuleMaxDependents, Check: func(application *models.Application) string {
+			if application.Dependents > cfg.MaxDependents {
+				return fmt.Sprintf("%d dependents, above %d", application.Dependents, cfg.MaxDependents)
+			}
+			return ""
+		}})
+	}
+
+	if len(cfg.MaritalStatuses) > 0 {
+		rules = append(rules, EligibilityRule{Name: RuleMaritalStatus, Check: func(application *models.Application) string {
+			if !slices.Contains(cfg.MaritalStatuses, application.MaritalStatus) {
+				return fmt.Sprintf("marital status %s is not one of %s", application.MaritalStatus, strings.Join(cfg.MaritalStatuses, ", "))
+			}
+			return ""
+		}})
+	}
+
+	if cfg.MinTermMonths > 0 || cfg.MaxTermMonths > 0 {
+		rules = append(rules, EligibilityRule{Name: RuleTermRange, Check: func(application *models.Application) string {
+			// Without a requested term the bank offers its own.
+			if application.TermMonths == nil {
+				return ""
+			}
+			term := *application.TermMonths
+			if cfg.MinTermMonths > 0 && term < cfg.MinTermMonths {
+				return fmt.Sprintf("term of %d months is below %d", term, cfg.MinTermMonths)
+			}
+			if cfg.MaxTermMonths > 0 && term > cfg.MaxTermMonths {
+				return fmt.Sprintf("term of %d months is above %d", term, cfg.MaxTermMonths)
+			}
+			return ""
+		}})
+	}
+
+	return rules
+}
+
+func checkAmountRange(amount money.Money, minAmount, maxAmount float64, currency money.Currency) string {
+	if minAmount > 0 {
+		limit, cmp, err := compareToLimit(amount, minAmount, currency)
+		if err != nil {
+			return err.Error()
+		}
+		if cmp < 0 {
+			return fmt.Sprintf("amount %s is below %s", amount, limit)
+		}
+	}
+	if maxAmount > 0 {
+		limit, cmp, err := compareToLimit(amount, maxAmount, currency)
+		if err != nil {
+			return err.Error()
+		}
+		if cmp > 0 {
+			return fmt.Sprintf("amount %s is above %s", amount, limit)
+		}
+	}
+	return ""
+}
+
+// compareToLimit compares amount with a limit configured in currency. An
+// amount in another currency cannot be compared and fails the check.
+func compareToLimit(amount money.Money, limit float64, currency money.Currency) (money.Money, int, error) {
+	limitAmount, err := money.FromFloat(limit, currency, money.RoundHalfUp)
+	if err != nil {
+		return money.Money{}, 0, fmt.Errorf("invalid limit %v: %w", limit, err)
+	}
+	cmp, err := amount.Cmp(limitAmount)
+	if err != nil {
+		return limitAmount, 0, err
+	}
+	return limitAmount, cmp, nil
+}
+
+// applicationAmount labels one of the application's amounts with its
+// currency, as amounts read from the database carry none.
+func applicationAmount(application *models.Application, amount money.Money) money.Money {
+	return amount.WithCurrency(mappers.ApplicationCurrency(application))
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eligibilityApplication() *models.Application {
+	term := 24
+	return &models.Application{
+		MonthlyIncome:   eur("2000"),
+		MonthlyExpenses: eur("800"),
+		MaritalStatus:   "MARRIED",
+		Amount:          eur("5000"),
+		Dependents:      2,
+		TermMonths:      &term,
+	}
+}
+
+func TestEligibilityRules_Check(t *testing.T) {
+	limits := config.EligibilityConfig{
+		MinAmount:       1000,
+		MaxAmount:       15000,
+		MinIncome:       1000,
+		MaxExpenseRatio: 0.5,
+		MaxDependents:   3,
+		MaritalStatuses: []string{"MARRIED", "SINGLE"},
+		MinTermMonths:   12,
+		MaxTermMonths:   60,
+	}
+	rules := NewEligibilityRules([]config.BankConfig{{Name: "FastBank", Eligibility: limits}})
+
+	tests := []struct {
+		name     string
+		modify   func(application *models.Application)
+		expected string
+	}{
+		{name: "application in another currency", modify: func(a *models.Application) { a.Currency = "SEK" }, expected: RuleCurrency},
+		{name: "amount above the range", modify: func(a *models.Application) { a.Amount = eur("15000.01") }, expected: RuleAmountRange},
+		{name: "amount below the range", modify: func(a *models.Application) { a.Amount = eur("999.99") }, expected: RuleAmountRange},
+		{name: "income below the minimum", modify: func(a *models.Application) { a.MonthlyIncome, a.MonthlyExpenses = eur("900"), eur("100") }, expected: RuleMinIncome},
+		{name: "expenses too high for the income", modify: func(a *models.Application) { a.MonthlyExpenses = eur("1200") }, expected: RuleExpenseRatio},
+		{name: "too many dependents", modify: func(a *models.Application) { a.Dependents = 4 }, expected: RuleMaxDependents},
+		{name: "marital status not accepted", modify: func(a *models.Application) { a.MaritalStatus = "DIVORCED" }, expected: RuleMaritalStatus},
+		{name: "term above the range", modify: func(a *models.Application) { term := 72; a.TermMonths = &term }, expected: RuleTermRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			application := eligibilityApplication()
+			tt.modify(application)
+
+			err := rules.Check("FastBank", application)
+
+			var ineligible *IneligibleError
+			require.ErrorAs(t, err, &ineligible)
+			assert.Equal(t, tt.expected, ineligible.Rule)
+			assert.Contains(t, err.Error(), tt.expected+": ")
+		})
+	}
+
+	t.Run("application within every limit should be eligible", func(t *testing.T) {
+		assert.NoError(t, rules.Check("FastBank", eligibilityApplication()))
+	})
+
+	t.Run("application without a term should pass the term rule", func(t *testing.T) {
+		application := eligibilityApplication()
+		application.TermMonths = nil
+
+		assert.NoError(t, rules.Check("FastBank", application))
+	})
+
+	t.Run("limits at the boundary should be inclusive", func(t *testing.T) {
+		application := eligibilityApplication()
+		application.Amount = eur("15000")
+		application.MonthlyExpenses = eur("1000")
+		application.Dependents = 3
+
+		assert.NoError(t, rules.Check("FastBank", application))
+	})
+
+	t.Run("bank without limits should accept every application", func(t *testing.T) {
+		unlimited := NewEligibilityRules([]config.BankConfig{{Name: "SolidBank", Eligibility: config.EligibilityConfig{MaxDependents: -1}}})
+		application := eligibilityApplication()
+		application.Amount = eur("1000000")
+
+		assert.NoError(t, unlimited.Check("SolidBank", application))
+		assert.NoError(t, unlimited.Check("UnknownBank", application))
+	})
+
+	t.Run("limits should be in the bank's currency", func(t *testing.T) {
+		sekLimits := limits
+		sekLimits.Currency = "SEK"
+		sekRules := NewEligibilityRules([]config.BankConfig{{Name: "NordBank", Eligibility: sekLimits}})
+
+		application := eligibilityApplication()
+		application.Currency = "SEK"
+		assert.NoError(t, sekRules.Check("NordBank", application))
+
+		err := sekRules.Check("NordBank", eligibilityApplication())
+		var ineligible *IneligibleError
+		require.ErrorAs(t, err, &ineligible)
+		assert.Equal(t, RuleCurrency, ineligible.Rule)
+		assert.Equal(t, "currency EUR is not SEK", ineligible.Reason)
+
+		application.Amount = application.Amount.Mul(4)
+		err = sekRules.Check("NordBank", application)
+		require.ErrorAs(t, err, &ineligible)
+		assert.Equal(t, RuleAmountRange, ineligible.Rule)
+		assert.Equal(t, "amount 20000.00 is above 15000.00", ineligible.Reason)
+	})
+}
@@ -12,35 +12,36 @@ import (
 )
 
 type fastBankService struct {
-	config     config.FastBankConfig
+	config     config.BankConfig
 	httpClient *HTTPClient
 	logger     *logrus.Logger
 }
 
-func NewFastBankService(config config.FastBankConfig, logger *logrus.Logger) BankService {
+func NewFastBankService(config config.BankConfig, logger *logrus.Logger) BankService {
 	return &fastBankService{
 		config:     config,
-		httpClient: NewHTTPClient(time.Duration(config.Timeout)*time.Second, logger),
+		httpClient: NewHTTPClient(time.Duration(config.Timeout)*time.Second, NewRetryPolicy(config.Retry), logger),
 		logger:     logger,
 	}
 }
 
 func (s *fastBankService) GetBankName() string {
-	return "FastBank"
+	return s.config.Name
 }
 
-func (s *fastBankService) SubmitApplication(ctx context.Context, req dto.ApplicationRequest) (*dto.BankSubmissionResponse, error) {
+func (s *fastBankService) SubmitApplication(ctx context.Context, req dto.ApplicationRequest, idempotencyKey string) (*dto.BankSubmissionResponse, error) {
 	logger := s.logger.WithFields(logrus.Fields{
-		"bank":   "FastBank",
+		"bank":   s.config.Name,
 		"phone":  req.Phone,
 		"amount": req.Amount,
 	})
 
+	warnDroppedLoanFields(logger, mappers.DroppedLoanFields(req))
 	fastBankReq := mappers.ToFastBankRequestFromApplicationRequest(req)
 	submitURL := fmt.Sprintf("%s/applications", s.config.BaseURL)
 	var fastBankApp dto.FastBankApplication
 
-	err := s.httpClient.PostJSON(ctx, submitURL, fastBankReq, &fastBankApp)
+	err := s.httpClient.PostJSON(ctx, submitURL, fastBankReq, &fastBankApp, submitRequestOptions(s.config, idempotencyKey)...)
 	if err != nil {
 		logger.WithError(err).Error("Failed to submit application to FastBank")
 		return nil, fmt.Errorf("FastBank submission failed: %w", err)
@@ -59,7 +60,7 @@ func (s *fastBankService) SubmitApplication(ctx context.Context, req dto.Applica
 
 func (s *fastBankService) GetOffer(ctx context.Context, bankID string) (*dto.Offer, error) {
 	logger := s.logger.WithFields(logrus.Fields{
-		"bank":    "FastBank",
+		"bank":    s.config.Name,
 		"bank_id": bankID,
 	})
 
@@ -90,7 +91,7 @@ func (s *fastBankService) handleProcessedApplication(fastBankApp dto.FastBankApp
 	} else {
 		logger.Info("FastBank application already processed but rejected")
 	}
-	offer := mappers.ToOfferFromFastBankApplication(fastBankApp, bankName)
+	offer := mappers.ToOfferFromFastBankApplication(fastBankApp, bankName, s.config.Currency)
 	if offer == nil {
 		logger.Error("FastBank application mapping returned nil offer")
 		return nil, fmt.Errorf("failed to map FastBank application")
@@ -4,59 +4,228 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
 	"time"
 
+	"github.com/lielamurs/aggregator/internal/config"
 	"github.com/sirupsen/logrus"
 )
 
 type HTTPError struct {
 	StatusCode int
 	Body       string
+	RetryAfter time.Duration
 }
 
 func (e *HTTPError) Error() string {
 	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
 }
 
+type RetryPolicy struct {
+	MaxAttempts          int
+	BaseDelay            time.Duration
+	MaxDelay             time.Duration
+	Jitter               float64
+	RetryableStatusCodes map[int]bool
+	RetryNetworkErrors   bool
+}
+
+func NewRetryPolicy(cfg config.RetryConfig) RetryPolicy {
+	statusCodes := make(map[int]bool, len(cfg.RetryableStatusCodes))
+	for _, code := range cfg.RetryableStatusCodes {
+		statusCodes[code] = true
+	}
+
+	return RetryPolicy{
+		MaxAttempts:          max(cfg.MaxAttempts, 1),
+		BaseDelay:            time.Duration(cfg.BaseDelayMs) * time.Millisecond,
+		MaxDelay:             time.Duration(cfg.MaxDelayMs) * time.Millisecond,
+		Jitter:               cfg.Jitter,
+		RetryableStatusCodes: statusCodes,
+		RetryNetworkErrors:   cfg.RetryNetworkErrors,
+	}
+}
+
+// Backoff returns the delay before the given retry (1 for the first retry):
+// the base delay doubled per attempt, capped at MaxDelay and spread by
+// +/- Jitter of its value.
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		spread := float64(delay) * p.Jitter
+		delay = time.Duration(float64(delay) - spread + rand.Float64()*2*spread)
+	}
+
+	return max(delay, 0)
+}
+
+// Delay returns the wait before the given retry of a request that failed
+// with err. A longer Retry-After from the bank is honored up to MaxDelay, so
+// a bank asking for hours cannot stall the caller; without a MaxDelay it is
+// ignored.
+func (p RetryPolicy) Delay(retry int, err error) time.Duration {
+	delay := p.Backoff(retry)
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.RetryAfter > delay && p.MaxDelay > 0 {
+		delay = max(delay, min(httpErr.RetryAfter, p.MaxDelay))
+	}
+	return delay
+}
+
+func (p RetryPolicy) isRetryable(err error) bool {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return p.RetryableStatusCodes[httpErr.StatusCode]
+	}
+
+	return p.RetryNetworkErrors && isNetworkError(err)
+}
+
+func isNetworkError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	idempotent     bool
+	idempotencyKey string
+	headers        map[string]string
+}
+
+// WithIdempotentRetries marks a POST as safe to repeat, allowing retries.
+func WithIdempotentRetries() RequestOption {
+	return func(o *requestOptions) {
+		o.idempotent = true
+	}
+}
+
+// WithIdempotencyKey sends the key in the given header on every attempt,
+// which lets the bank deduplicate a retried POST.
+func WithIdempotencyKey(header, key string) RequestOption {
+	return func(o *requestOptions) {
+		o.idempotencyKey = key
+		o.headers[header] = key
+	}
+}
+
 type HTTPClient struct {
-	client *http.Client
-	logger *logrus.Logger
+	client      *http.Client
+	retryPolicy RetryPolicy
+	logger      *logrus.Logger
 }
 
-func NewHTTPClient(timeout time.Duration, logger *logrus.Logger) *HTTPClient {
+func NewHTTPClient(timeout time.Duration, retryPolicy RetryPolicy, logger *logrus.Logger) *HTTPClient {
 	return &HTTPClient{
 		client: &http.Client{
 			Timeout: timeout,
 		},
-		logger: logger,
+		retryPolicy: retryPolicy,
+		logger:      logger,
 	}
 }
 
-func (c *HTTPClient) PostJSON(ctx context.Context, url string, payload any, response any) error {
-	return c.makeJSONRequest(ctx, "POST", url, payload, response)
+func (c *HTTPClient) PostJSON(ctx context.Context, url string, payload any, response any, opts ...RequestOption) error {
+	return c.makeJSONRequest(ctx, "POST", url, payload, response, opts...)
 }
 
-func (c *HTTPClient) GetJSON(ctx context.Context, url string, response any) error {
-	return c.makeJSONRequest(ctx, "GET", url, nil, response)
+func (c *HTTPClient) GetJSON(ctx context.Context, url string, response any, opts ...RequestOption) error {
+	return c.makeJSONRequest(ctx, "GET", url, nil, response, append(opts, WithIdempotentRetries())...)
 }
 
-func (c *HTTPClient) makeJSONRequest(ctx context.Context, method, url string, payload any, response any) error {
+func (c *HTTPClient) makeJSONRequest(ctx context.Context, method, url string, payload any, response any, opts ...RequestOption) error {
 	logger := c.logger.WithFields(logrus.Fields{
 		"method": method,
 		"url":    url,
 	})
 
-	var requestBody io.Reader
+	options := &requestOptions{headers: make(map[string]string)}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	var jsonData []byte
 	if payload != nil {
-		jsonData, err := json.Marshal(payload)
+		var err error
+		jsonData, err = json.Marshal(payload)
 		if err != nil {
 			logger.WithError(err).Error("Failed to marshal request payload")
 			return fmt.Errorf("failed to marshal request payload: %w", err)
 		}
-		requestBody = bytes.NewBuffer(jsonData)
+	}
+
+	maxAttempts := 1
+	if options.idempotent || options.idempotencyKey != "" {
+		maxAttempts = c.retryPolicy.MaxAttempts
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = c.doJSONRequest(ctx, method, url, jsonData, response, options.headers)
+		if err == nil || attempt >= maxAttempts || !c.retryPolicy.isRetryable(err) {
+			break
+		}
+
+		delay := c.retryPolicy.Delay(attempt, err)
+
+		logger.WithError(err).WithFields(logrus.Fields{
+			"attempt": attempt,
+			"delay":   delay,
+		}).Warn("HTTP request failed, retrying")
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("HTTP request retry aborted: %w", ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+
+	return err
+}
+
+func (c *HTTPClient) doJSONRequest(ctx context.Context, method, url string, jsonData []byte, response any, headers map[string]string) error {
+	logger := c.logger.WithFields(logrus.Fields{
+		"method": method,
+		"url":    url,
+	})
+
+	var requestBody io.Reader
+	if jsonData != nil {
+		requestBody = bytes.NewReader(jsonData)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, url, requestBody)
@@ -67,6 +236,9 @@ func (c *HTTPClient) makeJSONRequest(ctx context.Context, method, url string, pa
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
+	for header, value := range headers {
+		req.Header.Set(header, value)
+	}
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -80,6 +252,7 @@ func (c *HTTPClient) makeJSONRequest(ctx context.Context, method, url string, pa
 		return &HTTPError{
 			StatusCode: resp.StatusCode,
 			Body:       string(bodyBytes),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
 		}
 	}
 
@@ -91,3 +264,19 @@ func (c *HTTPClient) makeJSONRequest(ctx context.Context, method, url string, pa
 
 	return nil
 }
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+
+	return 0
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHTTPClient(retry config.RetryConfig) *HTTPClient {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	return NewHTTPClient(5*time.Second, NewRetryPolicy(retry), logger)
+}
+
+func testRetryConfig() config.RetryConfig {
+	return config.RetryConfig{
+		MaxAttempts:          3,
+		BaseDelayMs:          1,
+		MaxDelayMs:           5,
+		RetryableStatusCodes: []int{502, 503},
+		RetryNetworkErrors:   true,
+	}
+}
+
+func flakyServer(t *testing.T, failures int32, status int, headers map[string]string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			for header, value := range headers {
+				w.Header().Set(header, value)
+			}
+			w.WriteHeader(status)
+			w.Write([]byte("unavailable"))
+			return
+		}
+		w.Write([]byte(`{"id": "app-1"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func TestHTTPClient_GetJSON_Retries(t *testing.T) {
+	t.Run("retryable status should be retried until success", func(t *testing.T) {
+		server, calls := flakyServer(t, 2, http.StatusBadGateway, nil)
+		client := newTestHTTPClient(testRetryConfig())
+
+		var response map[string]string
+		err := client.GetJSON(context.Background(), server.URL, &response)
+		require.NoError(t, err)
+		assert.Equal(t, "app-1", response["id"])
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("attempts should be limited by max attempts", func(t *testing.T) {
+		server, calls := flakyServer(t, 5, http.StatusServiceUnavailable, nil)
+		client := newTestHTTPClient(testRetryConfig())
+
+		err := client.GetJSON(context.Background(), server.URL, nil)
+		require.Error(t, err)
+
+		var httpErr *HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("non-retryable status should fail immediately", func(t *testing.T) {
+		server, calls := flakyServer(t, 5, http.StatusBadRequest, nil)
+		client := newTestHTTPClient(testRetryConfig())
+
+		err := client.GetJSON(context.Background(), server.URL, nil)
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("retry after header should be honored", func(t *testing.T) {
+		server, calls := flakyServer(t, 1, http.StatusServiceUnavailable, map[string]string{"Retry-After": "1"})
+		cfg := testRetryConfig()
+		cfg.MaxDelayMs = 2000
+		client := newTestHTTPClient(cfg)
+
+		start := time.Now()
+		err := client.GetJSON(context.Background(), server.URL, nil)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), time.Second)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("retry after header should be capped by the max delay", func(t *testing.T) {
+		server, calls := flakyServer(t, 1, http.StatusServiceUnavailable, map[string]string{"Retry-After": "86400"})
+		client := newTestHTTPClient(testRetryConfig())
+
+		start := time.Now()
+		err := client.GetJSON(context.Background(), server.URL, nil)
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("cancelled context should stop retrying", func(t *testing.T) {
+		server, calls := flakyServer(t, 5, http.StatusServiceUnavailable, map[string]string{"Retry-After": "30"})
+		cfg := testRetryConfig()
+		cfg.MaxDelayMs = 60000
+		client := newTestHTTPClient(cfg)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := client.GetJSON(ctx, server.URL, nil)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestHTTPClient_PostJSON_Retries(t *testing.T) {
+	t.Run("post without idempotency should not be retried", func(t *testing.T) {
+		server, calls := flakyServer(t, 1, http.StatusBadGateway, nil)
+		client := newTestHTTPClient(testRetryConfig())
+
+		err := client.PostJSON(context.Background(), server.URL, map[string]string{}, nil)
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("post declared idempotent should be retried", func(t *testing.T) {
+		server, calls := flakyServer(t, 1, http.StatusBadGateway, nil)
+		client := newTestHTTPClient(testRetryConfig())
+
+		err := client.PostJSON(context.Background(), server.URL, map[string]string{}, nil, WithIdempotentRetries())
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("post with idempotency key should be retried with the same key", func(t *testing.T) {
+		var keys []string
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			keys = append(keys, r.Header.Get("Idempotency-Key"))
+			body, _ := io.ReadAll(r.Body)
+			assert.JSONEq(t, `{"amount": 100}`, string(body))
+
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		client := newTestHTTPClient(testRetryConfig())
+		err := client.PostJSON(context.Background(), server.URL, map[string]int{"amount": 100}, nil, WithIdempotencyKey("Idempotency-Key", "key-1"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"key-1", "key-1"}, keys)
+	})
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := NewRetryPolicy(config.RetryConfig{
+		MaxAttempts: 5,
+		BaseDelayMs: 100,
+		MaxDelayMs:  500,
+	})
+
+	assert.Equal(t, 100*time.Millisecond, policy.Backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.Backoff(2))
+	assert.Equal(t, 400*time.Millisecond, policy.Backoff(3))
+	assert.Equal(t, 500*time.Millisecond, policy.Backoff(4))
+	assert.Equal(t, 500*time.Millisecond, policy.Backoff(50))
+
+	policy.Jitter = 0.2
+	for i := 0; i < 100; i++ {
+		delay := policy.Backoff(2)
+		assert.GreaterOrEqual(t, delay, 160*time.Millisecond)
+		assert.LessOrEqual(t, delay, 240*time.Millisecond)
+	}
+}
+
+func TestRetryPolicy_IsRetryable(t *testing.T) {
+	policy := NewRetryPolicy(testRetryConfig())
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "retryable status", err: &HTTPError{StatusCode: 502}, expected: true},
+		{name: "wrapped retryable status", err: fmt.Errorf("bank failed: %w", &HTTPError{StatusCode: 503}), expected: true},
+		{name: "client error status", err: &HTTPError{StatusCode: 422}, expected: false},
+		{name: "connection reset", err: fmt.Errorf("HTTP request failed: %w", syscall.ECONNRESET), expected: true},
+		{name: "unexpected eof", err: io.ErrUnexpectedEOF, expected: true},
+		{name: "context cancelled", err: fmt.Errorf("HTTP request failed: %w", context.Canceled), expected: false},
+		{name: "other error", err: errors.New("failed to decode response"), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, policy.isRetryable(tt.err))
+		})
+	}
+
+	policy.RetryNetworkErrors = false
+	assert.False(t, policy.isRetryable(syscall.ECONNRESET))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), parseRetryAfter(""))
+	assert.Equal(t, 5*time.Second, parseRetryAfter("5"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon"))
+
+	date := time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)
+	delay := parseRetryAfter(date)
+	assert.Greater(t, delay, 8*time.Second)
+	assert.LessOrEqual(t, delay, 10*time.Second)
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/repository"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is still in progress")
+)
+
+const idempotencyKeyLockDuration = time.Minute
+
+type IdempotentResponse struct {
+	StatusCode int
+	Body       []byte
+}
+
+type IdempotencyService interface {
+	// Begin reserves the key for a request with the given hash. It returns
+	// the stored response when the request was already answered, and
+	// otherwise a lock token the caller passes to Complete or Release once
+	// it has handled the request.
+	Begin(ctx context.Context, key, requestHash string) (*IdempotentResponse, string, error)
+	// Complete and Release fail with repository.ErrLeaseLost when the lock
+	// expired and a retry took the key over.
+	Complete(ctx context.Context, key, lockToken string, response IdempotentResponse) error
+	Release(ctx context.Context, key, lockToken string) error
+}
+
+type idempotencyService struct {
+	idempotencyKeysRepo *repository.IdempotencyKeysRepository
+	config              config.IdempotencyConfig
+	logger              *logrus.Logger
+}
+
+func NewIdempotencyService(
+	idempotencyKeysRepo *repository.IdempotencyKeysRepository,
+	config config.IdempotencyConfig,
+	logger *logrus.Logger,
+) IdempotencyService {
+	return &idempotencyService{
+		idempotencyKeysRepo: idempotencyKeysRepo,
+		config:              config,
+		logger:              logger,
+	}
+}
+
+func (s *idempotencyService) Begin(ctx context.Context, key, requestHash string) (*IdempotentResponse, string, error) {
+	now := time.Now()
+	if err := s.idempotencyKeysRepo.DeleteExpired(ctx, now); err != nil {
+		return nil, "", fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	lockToken := uuid.NewString()
+	lockedUntil := now.Add(idempotencyKeyLockDuration)
+	created, err := s.idempotencyKeysRepo.Create(ctx, &models.IdempotencyKey{
+		Key:         key,
+		RequestHash: requestHash,
+		Status:      string(dto.IdempotencyKeyInProgress),
+		LockedBy:    &lockToken,
+		LockedUntil: &lockedUntil,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Duration(s.config.KeyTTLHours) * time.Hour),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to store idempotency key: %w", err)
+	}
+	if created {
+		return nil, lockToken, nil
+	}
+
+	existing, err := s.idempotencyKeysRepo.Get(ctx, key)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", ErrIdempotencyKeyInProgress
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	if existing.RequestHash != requestHash {
+		return nil, "", ErrIdempotencyKeyReused
+	}
+
+	if existing.Status == string(dto.IdempotencyKeyInProgress) {
+		relocked, err := s.idempotencyKeysRepo.Relock(ctx, key, lockToken, lockedUntil)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to take over idempotency key: %w", err)
+		}
+		if relocked {
+			s.logger.WithField("idempotency_key", key).Warn("Took over abandoned idempotency key")
+			return nil, lockToken, nil
+		}
+		return nil, "", ErrIdempotencyKeyInProgress
+	}
+
+	response := &IdempotentResponse{Body: existing.ResponseBody}
+	if existing.ResponseStatus != nil {
+		response.StatusCode = *existing.ResponseStatus
+	}
+	return response, "", nil
+}
+
+func (s *idempotencyService) Complete(ctx context.Context, key, lockToken string, response IdempotentResponse) error {
+	if err := s.idempotencyKeysRepo.Complete(ctx, key, lockToken, response.StatusCode, response.Body); err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+func (s *idempotencyService) Release(ctx context.Context, key, lockToken string) error {
+	if err := s.idempotencyKeysRepo.Delete(ctx, key, lockToken); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
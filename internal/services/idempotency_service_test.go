@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/repository"
+	"github.com/lielamurs/aggregator/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyService(t *testing.T) {
+	db := testutil.OpenPostgres(t)
+	ctx := context.Background()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	repo := repository.NewIdempotencyKeysRepository(db)
+	service := NewIdempotencyService(repo, config.IdempotencyConfig{KeyTTLHours: 24}, logger)
+
+	stored, lockToken, err := service.Begin(ctx, "key-1", "hash-1")
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+	assert.NotEmpty(t, lockToken)
+
+	_, _, err = service.Begin(ctx, "key-1", "hash-1")
+	assert.ErrorIs(t, err, ErrIdempotencyKeyInProgress)
+
+	require.NoError(t, service.Complete(ctx, "key-1", lockToken, IdempotentResponse{StatusCode: 201, Body: []byte(`{"id":"1"}`)}))
+
+	stored, _, err = service.Begin(ctx, "key-1", "hash-1")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, 201, stored.StatusCode)
+	assert.JSONEq(t, `{"id":"1"}`, string(stored.Body))
+
+	_, _, err = service.Begin(ctx, "key-1", "hash-2")
+	assert.ErrorIs(t, err, ErrIdempotencyKeyReused)
+
+	t.Run("abandoned key should be taken over", func(t *testing.T) {
+		_, abandoned, err := service.Begin(ctx, "key-2", "hash-1")
+		require.NoError(t, err)
+		require.NoError(t, db.Exec("UPDATE idempotency_keys SET locked_until = ? WHERE key = ?", time.Now().Add(-time.Second), "key-2").Error)
+
+		stored, lockToken, err := service.Begin(ctx, "key-2", "hash-1")
+		require.NoError(t, err)
+		assert.Nil(t, stored)
+		assert.NotEqual(t, abandoned, lockToken)
+
+		response := IdempotentResponse{StatusCode: 201, Body: []byte(`{"id":"2"}`)}
+		assert.ErrorIs(t, service.Complete(ctx, "key-2", abandoned, response), repository.ErrLeaseLost)
+		assert.ErrorIs(t, service.Release(ctx, "key-2", abandoned), repository.ErrLeaseLost)
+		require.NoError(t, service.Complete(ctx, "key-2", lockToken, response))
+	})
+
+	t.Run("expired key should be reusable", func(t *testing.T) {
+		require.NoError(t, db.Exec("UPDATE idempotency_keys SET expires_at = ? WHERE key = ?", time.Now().Add(-time.Second), "key-1").Error)
+
+		stored, _, err := service.Begin(ctx, "key-1", "hash-2")
+		require.NoError(t, err)
+		assert.Nil(t, stored)
+	})
+}
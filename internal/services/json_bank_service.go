@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/mappers"
+	"github.com/sirupsen/logrus"
+)
+
+type jsonBankService struct {
+	config     config.BankConfig
+	mapping    config.BankMapping
+	httpClient *HTTPClient
+	logger     *logrus.Logger
+}
+
+func NewJSONBankService(config config.BankConfig, logger *logrus.Logger) BankService {
+	return &jsonBankService{
+		config:     config,
+		mapping:    *config.Mapping,
+		httpClient: NewHTTPClient(time.Duration(config.Timeout)*time.Second, NewRetryPolicy(config.Retry), logger),
+		logger:     logger,
+	}
+}
+
+func (s *jsonBankService) GetBankName() string {
+	return s.config.Name
+}
+
+func (s *jsonBankService) SubmitApplication(ctx context.Context, req dto.ApplicationRequest, idempotencyKey string) (*dto.BankSubmissionResponse, error) {
+	logger := s.logger.WithFields(logrus.Fields{
+		"bank":   s.config.Name,
+		"phone":  req.Phone,
+		"amount": req.Amount,
+	})
+
+	warnDroppedLoanFields(logger, mappers.DroppedLoanFields(req, slices.Collect(maps.Values(s.mapping.Request))...))
+
+	bankReq, err := mappers.ToJSONBankRequestFromApplicationRequest(req, s.mapping.Request)
+	if err != nil {
+		logger.WithError(err).Error("Failed to map application request")
+		return nil, fmt.Errorf("%s request mapping failed: %w", s.config.Name, err)
+	}
+
+	submitURL := s.config.BaseURL + s.mapping.SubmitPath
+	var bankApp map[string]any
+
+	if err := s.httpClient.PostJSON(ctx, submitURL, bankReq, &bankApp, submitRequestOptions(s.config, idempotencyKey)...); err != nil {
+		logger.WithError(err).Error("Failed to submit application to bank")
+		return nil, fmt.Errorf("%s submission failed: %w", s.config.Name, err)
+	}
+
+	response := mappers.ToBankSubmissionResponseFromJSONBankApplication(bankApp, s.mapping.Response)
+	if response == nil {
+		logger.Error("Bank response does not contain an application ID")
+		return nil, fmt.Errorf("%s submission response missing %q", s.config.Name, s.mapping.Response.ID)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"application_id": response.ID,
+		"status":         response.Status,
+	}).Info("Bank application submitted")
+
+	return response, nil
+}
+
+func (s *jsonBankService) GetOffer(ctx context.Context, bankID string) (*dto.Offer, error) {
+	logger := s.logger.WithFields(logrus.Fields{
+		"bank":    s.config.Name,
+		"bank_id": bankID,
+	})
+
+	pollURL := s.config.BaseURL + strings.ReplaceAll(s.mapping.PollPath, "{id}", url.PathEscape(bankID))
+	var bankApp map[string]any
+
+	if err := s.httpClient.GetJSON(ctx, pollURL, &bankApp); err != nil {
+		logger.WithError(err).Error("Failed to get bank application")
+		return nil, fmt.Errorf("%s get application failed: %w", s.config.Name, err)
+	}
+
+	offer := mappers.ToOfferFromJSONBankApplication(bankApp, s.mapping.Response, s.config.Name, s.config.Currency)
+
+	logger.WithField("processed", offer != nil).Info("Bank application status retrieved")
+
+	return offer, nil
+}
+
+func (s *jsonBankService) AcceptOffer(ctx context.Context, bankID string) error {
+	if s.mapping.AcceptPath == "" {
+		return ErrOfferAcceptanceNotSupported
+	}
+
+	logger := s.logger.WithFields(logrus.Fields{
+		"bank":    s.config.Name,
+		"bank_id": bankID,
+	})
+
+	acceptURL := s.config.BaseURL + strings.ReplaceAll(s.mapping.AcceptPath, "{id}", url.PathEscape(bankID))
+	if err := s.httpClient.PostJSON(ctx, acceptURL, nil, nil, submitRequestOptions(s.config, "accept:"+bankID)...); err != nil {
+		logger.WithError(err).Error("Failed to accept offer at bank")
+		return fmt.Errorf("%s offer acceptance failed: %w", s.config.Name, err)
+	}
+
+	logger.Info("Offer accepted at bank")
+
+	return nil
+}
+
+func (s *jsonBankService) CancelApplication(ctx context.Context, bankID string) error {
+	if s.mapping.CancelPath == "" {
+		return ErrApplicationCancellationNotSupported
+	}
+
+	logger := s.logger.WithFields(logrus.Fields{
+		"bank":    s.config.Name,
+		"bank_id": bankID,
+	})
+
+	cancelURL := s.config.BaseURL + strings.ReplaceAll(s.mapping.CancelPath, "{id}", url.PathEscape(bankID))
+	if err := s.httpClient.PostJSON(ctx, cancelURL, nil, nil, submitRequestOptions(s.config, "cancel:"+bankID)...); err != nil {
+		logger.WithError(err).Error("Failed to cancel application at bank")
+		return fmt.Errorf("%s cancellation failed: %w", s.config.Name, err)
+	}
+
+	logger.Info("Application cancelled at bank")
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestJSONBankService(t *testing.T, handler http.HandlerFunc, configure ...func(*config.BankConfig)) BankService {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	mapping, err := config.ParseBankMapping([]byte(`{
+		"submitPath": "/v2/loans",
+		"pollPath": "/v2/loans/{id}",
+		"acceptPath": "/v2/loans/{id}/acceptance",
+		"cancelPath": "/v2/loans/{id}/withdrawal",
+		"request": {"applicant.mobile": "phone", "loanAmount": "amount"},
+		"response": {
+			"id": "loanId",
+			"status": "state",
+			"processedStatuses": ["DECIDED"],
+			"offer": "offer",
+			"offerFields": {"annualPercentageRate": "apr", "numberOfPayments": "terms"}
+		}
+	}`))
+	require.NoError(t, err)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	bankConfig := config.BankConfig{
+		Name:    "TrustBank",
+		Adapter: "json",
+		BaseURL: server.URL,
+		Timeout: 5,
+		Enabled: true,
+		Mapping: mapping,
+	}
+	for _, fn := range configure {
+		fn(&bankConfig)
+	}
+	return NewJSONBankService(bankConfig, logger)
+}
+
+func TestJSONBankService_SubmitApplication(t *testing.T) {
+	var received map[string]any
+
+	service := newTestJSONBankService(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v2/loans", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"loanId": "loan-1", "state": "RECEIVED"}`))
+	})
+
+	response, err := service.SubmitApplication(context.Background(), dto.ApplicationRequest{
+		Phone:  "+37126000000",
+		Amount: eur("8000"),
+	}, "job-1")
+	require.NoError(t, err)
+	assert.Equal(t, &dto.BankSubmissionResponse{ID: "loan-1", Status: "RECEIVED"}, response)
+	assert.Equal(t, map[string]any{
+		"applicant":  map[string]any{"mobile": "+37126000000"},
+		"loanAmount": 8000.0,
+	}, received)
+}
+
+func TestJSONBankService_SubmitApplication_IdempotencyKey(t *testing.T) {
+	var keys []string
+	service := newTestJSONBankService(t, func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"loanId": "loan-1", "state": "RECEIVED"}`))
+	}, func(cfg *config.BankConfig) {
+		cfg.Retry = config.RetryConfig{MaxAttempts: 1, IdempotencyHeader: "Idempotency-Key"}
+	})
+
+	_, err := service.SubmitApplication(context.Background(), dto.ApplicationRequest{Amount: eur("8000")}, "job-1")
+	require.Error(t, err)
+
+	_, err = service.SubmitApplication(context.Background(), dto.ApplicationRequest{Amount: eur("8000")}, "job-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"job-1", "job-1"}, keys)
+}
+
+func TestJSONBankService_SubmitApplication_Errors(t *testing.T) {
+	t.Run("bank error should be returned", func(t *testing.T) {
+		service := newTestJSONBankService(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`invalid`))
+		})
+
+		response, err := service.SubmitApplication(context.Background(), dto.ApplicationRequest{}, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TrustBank submission failed")
+		assert.Nil(t, response)
+	})
+
+	t.Run("response without id should fail", func(t *testing.T) {
+		service := newTestJSONBankService(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"state": "RECEIVED"}`))
+		})
+
+		response, err := service.SubmitApplication(context.Background(), dto.ApplicationRequest{}, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `submission response missing "loanId"`)
+		assert.Nil(t, response)
+	})
+}
+
+func TestJSONBankService_GetOffer(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus dto.OfferStatus
+		expectOffer    bool
+	}{
+		{
+			name:           "decided application with offer should return approved offer",
+			body:           `{"loanId": "loan-1", "state": "DECIDED", "offer": {"apr": 11.5, "terms": 24}}`,
+			expectedStatus: dto.OfferStatusApproved,
+			expectOffer:    true,
+		},
+		{
+			name:           "decided application without offer should return rejected offer",
+			body:           `{"loanId": "loan-1", "state": "DECIDED"}`,
+			expectedStatus: dto.OfferStatusRejected,
+			expectOffer:    true,
+		},
+		{
+			name:        "undecided application should return nil offer",
+			body:        `{"loanId": "loan-1", "state": "IN_REVIEW"}`,
+			expectOffer: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestJSONBankService(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/v2/loans/loan-1", r.URL.Path)
+				w.Write([]byte(tt.body))
+			})
+
+			offer, err := service.GetOffer(context.Background(), "loan-1")
+			require.NoError(t, err)
+
+			if !tt.expectOffer {
+				assert.Nil(t, offer)
+				return
+			}
+
+			require.NotNil(t, offer)
+			assert.Equal(t, "TrustBank", offer.BankName)
+			assert.Equal(t, tt.expectedStatus, offer.Status)
+
+			if tt.expectedStatus == dto.OfferStatusApproved {
+				require.NotNil(t, offer.AnnualPercentageRate)
+				require.NotNil(t, offer.NumberOfPayments)
+				assert.Equal(t, 11.5, *offer.AnnualPercentageRate)
+				assert.Equal(t, 24, *offer.NumberOfPayments)
+			}
+		})
+	}
+}
+
+func TestJSONBankService_AcceptOffer(t *testing.T) {
+	t.Run("acceptance should be posted to the accept path", func(t *testing.T) {
+		var path string
+		service := newTestJSONBankService(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			path = r.URL.Path
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		require.NoError(t, acceptOffer(context.Background(), service, "loan-1"))
+		assert.Equal(t, "/v2/loans/loan-1/acceptance", path)
+	})
+
+	t.Run("bank refusal should be returned", func(t *testing.T) {
+		service := newTestJSONBankService(t, func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "offer expired", http.StatusConflict)
+		})
+
+		err := acceptOffer(context.Background(), service, "loan-1")
+		var httpErr *HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusConflict, httpErr.StatusCode)
+	})
+}
+
+func TestJSONBankService_CancelApplication(t *testing.T) {
+	var path string
+	service := newTestJSONBankService(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		path = r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	require.NoError(t, cancelApplication(context.Background(), service, "loan-1"))
+	assert.Equal(t, "/v2/loans/loan-1/withdrawal", path)
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/mappers"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/money"
+	"github.com/lielamurs/aggregator/internal/offermath"
+)
+
+var ErrOfferNotVerifiable = errors.New("offer figures cannot be verified")
+
+// offerLoan reduces an approved offer to its cash flows. The amount is drawn
+// on the day the offer was received.
+func offerLoan(offer *models.Offer, amount money.Money) (offermath.Loan, error) {
+	if offer.Status != string(dto.OfferStatusApproved) {
+		return offermath.Loan{}, fmt.Errorf("%w: offer is %s", ErrOfferNotVerifiable, offer.Status)
+	}
+	if offer.MonthlyPaymentAmount == nil || offer.NumberOfPayments == nil || offer.FirstRepaymentDate == nil {
+		return offermath.Loan{}, fmt.Errorf("%w: monthly payment, number of payments and first repayment date are required", ErrOfferNotVerifiable)
+	}
+
+	firstRepaymentDate, err := offermath.ParseDate(*offer.FirstRepaymentDate)
+	if err != nil {
+		return offermath.Loan{}, fmt.Errorf("%w: invalid first repayment date %q", ErrOfferNotVerifiable, *offer.FirstRepaymentDate)
+	}
+
+	return offermath.Loan{
+		Principal:          amount,
+		Payments:           *offer.NumberOfPayments,
+		MonthlyPayment:     *offer.MonthlyPaymentAmount,
+		DrawdownDate:       offermath.StartOfDay(offer.CreatedAt),
+		FirstRepaymentDate: firstRepaymentDate,
+	}, nil
+}
+
+// verifyOffer recomputes the offer's APR and total repayment and records
+// the stated figures that deviate beyond the tolerance on the offer.
+func verifyOffer(offer *models.Offer, amount money.Money, cfg config.OfferVerificationConfig) error {
+	loan, err := offerLoan(offer, amount)
+	if err != nil {
+		return err
+	}
+
+	amountTolerance, err := money.FromFloat(cfg.AmountTolerance, amount.Currency(), money.RoundHalfUp)
+	if err != nil {
+		return fmt.Errorf("invalid amount tolerance: %w", err)
+	}
+
+	verification, err := offermath.Verify(loan, offer.AnnualPercentageRate, offer.TotalRepaymentAmount, offermath.Tolerance{
+		APR:    cfg.APRTolerance,
+		Amount: amountTolerance,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOfferNotVerifiable, err)
+	}
+
+	discrepancies := make([]dto.OfferDiscrepancy, len(verification.Discrepancies))
+	for i, discrepancy := range verification.Discrepancies {
+		discrepancies[i] = dto.OfferDiscrepancy(discrepancy)
+	}
+	offer.VerifiedAnnualPercentageRate = &verification.AnnualPercentageRate
+	offer.Discrepancies = mappers.JoinOfferDiscrepancies(discrepancies)
+	return nil
+}
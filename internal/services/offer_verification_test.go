@@ -0,0 +1,75 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eur(value string) money.Money {
+	return money.MustParse(value, money.EUR)
+}
+
+func verifiableOffer(apr float64, total string) *models.Offer {
+	payment, totalRepayment := eur("230.72"), eur(total)
+	payments := 24
+	firstRepaymentDate := "2025-02-15"
+	return &models.Offer{
+		BankName:             "FastBank",
+		Status:               string(dto.OfferStatusApproved),
+		MonthlyPaymentAmount: &payment,
+		NumberOfPayments:     &payments,
+		FirstRepaymentDate:   &firstRepaymentDate,
+		AnnualPercentageRate: &apr,
+		TotalRepaymentAmount: &totalRepayment,
+		CreatedAt:            time.Date(2025, 1, 15, 14, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestVerifyOffer(t *testing.T) {
+	cfg := config.OfferVerificationConfig{APRTolerance: 0.1, AmountTolerance: 1}
+
+	t.Run("consistent offer should not be flagged", func(t *testing.T) {
+		offer := verifiableOffer(10.5, "5537.28")
+
+		require.NoError(t, verifyOffer(offer, eur("5000"), cfg))
+		require.NotNil(t, offer.VerifiedAnnualPercentageRate)
+		assert.Equal(t, 10.49, *offer.VerifiedAnnualPercentageRate)
+		assert.Empty(t, offer.Discrepancies)
+	})
+
+	t.Run("deviating figures should be flagged", func(t *testing.T) {
+		offer := verifiableOffer(7.9, "5300")
+
+		require.NoError(t, verifyOffer(offer, eur("5000"), cfg))
+		assert.Equal(t, "APR_MISMATCH,TOTAL_REPAYMENT_MISMATCH", offer.Discrepancies)
+	})
+
+	t.Run("offers without a schedule should not be verifiable", func(t *testing.T) {
+		offer := verifiableOffer(10.5, "5537.28")
+		offer.FirstRepaymentDate = nil
+
+		assert.ErrorIs(t, verifyOffer(offer, eur("5000"), cfg), ErrOfferNotVerifiable)
+		assert.Nil(t, offer.VerifiedAnnualPercentageRate)
+	})
+
+	t.Run("rejected offers should not be verifiable", func(t *testing.T) {
+		offer := &models.Offer{Status: string(dto.OfferStatusRejected)}
+
+		assert.ErrorIs(t, verifyOffer(offer, eur("5000"), cfg), ErrOfferNotVerifiable)
+	})
+
+	t.Run("first repayment before the offer should not be verifiable", func(t *testing.T) {
+		offer := verifiableOffer(10.5, "5537.28")
+		firstRepaymentDate := "2024-02-01"
+		offer.FirstRepaymentDate = &firstRepaymentDate
+
+		assert.ErrorIs(t, verifyOffer(offer, eur("5000"), cfg), ErrOfferNotVerifiable)
+	})
+}
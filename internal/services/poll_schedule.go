@@ -0,0 +1,64 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"github.com/lielamurs/aggregator/internal/config"
+)
+
+type PollSchedule struct {
+	InitialDelay     time.Duration
+	Multiplier       float64
+	MaxInterval      time.Duration
+	DecisionDeadline time.Duration
+}
+
+func NewPollSchedule(cfg config.PollConfig) PollSchedule {
+	return PollSchedule{
+		InitialDelay:     time.Duration(cfg.InitialDelaySeconds) * time.Second,
+		Multiplier:       cfg.BackoffMultiplier,
+		MaxInterval:      time.Duration(cfg.MaxIntervalSeconds) * time.Second,
+		DecisionDeadline: time.Duration(cfg.DecisionDeadlineSeconds) * time.Second,
+	}
+}
+
+// Next returns when a submission that has been polled attempts times should
+// be polled again. The first poll is InitialDelay after submission.
+func (s PollSchedule) Next(now time.Time, attempts int) time.Time {
+	delay := float64(s.InitialDelay) * math.Pow(max(s.Multiplier, 1), float64(max(attempts, 0)))
+	if s.MaxInterval > 0 && delay > float64(s.MaxInterval) {
+		delay = float64(s.MaxInterval)
+	}
+	return now.Add(time.Duration(delay))
+}
+
+// Deadline returns when a submission the bank accepted at submittedAt times
+// out, and false if the bank has no decision deadline or the submission has
+// not reached the bank yet. Rows stored before unsubmitted submissions were
+// saved without a timestamp carry the zero time, which counts as unsubmitted.
+func (s PollSchedule) Deadline(submittedAt *time.Time) (time.Time, bool) {
+	if s.DecisionDeadline <= 0 || submittedAt == nil || submittedAt.IsZero() {
+		return time.Time{}, false
+	}
+	return submittedAt.Add(s.DecisionDeadline), true
+}
+
+type PollSchedules map[string]PollSchedule
+
+func NewPollSchedules(banks []config.BankConfig) PollSchedules {
+	schedules := make(PollSchedules, len(banks))
+	for _, bank := range banks {
+		schedules[bank.Name] = NewPollSchedule(bank.Poll)
+	}
+	return schedules
+}
+
+// For returns the bank's schedule, or the default schedule for banks that
+// are no longer configured, so their submissions still back off and time out.
+func (s PollSchedules) For(bankName string) PollSchedule {
+	if schedule, ok := s[bankName]; ok {
+		return schedule
+	}
+	return NewPollSchedule(config.DefaultPollConfig)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollSchedule_Next(t *testing.T) {
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	schedule := NewPollSchedule(config.PollConfig{
+		InitialDelaySeconds: 5,
+		BackoffMultiplier:   2,
+		MaxIntervalSeconds:  60,
+	})
+
+	tests := []struct {
+		name     string
+		attempts int
+		expected time.Duration
+	}{
+		{name: "first poll should use the initial delay", attempts: 0, expected: 5 * time.Second},
+		{name: "second poll should back off", attempts: 1, expected: 10 * time.Second},
+		{name: "third poll should back off further", attempts: 2, expected: 20 * time.Second},
+		{name: "later polls should be capped", attempts: 10, expected: 60 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, now.Add(tt.expected), schedule.Next(now, tt.attempts))
+		})
+	}
+
+	t.Run("multiplier below one should keep a fixed interval", func(t *testing.T) {
+		fixed := NewPollSchedule(config.PollConfig{InitialDelaySeconds: 30, BackoffMultiplier: 0.5})
+		assert.Equal(t, now.Add(30*time.Second), fixed.Next(now, 4))
+	})
+}
+
+func TestPollSchedule_Deadline(t *testing.T) {
+	submittedAt := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	schedule := NewPollSchedule(config.PollConfig{DecisionDeadlineSeconds: 3600})
+	deadline, ok := schedule.Deadline(&submittedAt)
+	assert.True(t, ok)
+	assert.Equal(t, submittedAt.Add(time.Hour), deadline)
+
+	_, ok = schedule.Deadline(nil)
+	assert.False(t, ok, "submissions not yet at the bank should have no deadline")
+
+	_, ok = schedule.Deadline(&time.Time{})
+	assert.False(t, ok, "a zero submission time should count as not submitted")
+
+	_, ok = NewPollSchedule(config.PollConfig{}).Deadline(&submittedAt)
+	assert.False(t, ok)
+}
+
+func TestPollSchedules_For(t *testing.T) {
+	schedules := NewPollSchedules([]config.BankConfig{
+		{Name: "FastBank", Poll: config.PollConfig{InitialDelaySeconds: 1, BackoffMultiplier: 2, MaxIntervalSeconds: 10}},
+		{Name: "SolidBank", Poll: config.PollConfig{InitialDelaySeconds: 30, BackoffMultiplier: 1, MaxIntervalSeconds: 30}},
+	})
+
+	assert.Equal(t, time.Second, schedules.For("FastBank").InitialDelay)
+	assert.Equal(t, 30*time.Second, schedules.For("SolidBank").InitialDelay)
+	assert.Equal(t, NewPollSchedule(config.DefaultPollConfig), schedules.For("UnknownBank"))
+	assert.Equal(t, time.Hour, schedules.For("UnknownBank").DecisionDeadline)
+}
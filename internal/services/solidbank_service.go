@@ -12,35 +12,36 @@ import (
 )
 
 type solidBankService struct {
-	config     config.SolidBankConfig
+	config     config.BankConfig
 	httpClient *HTTPClient
 	logger     *logrus.Logger
 }
 
-func NewSolidBankService(config config.SolidBankConfig, logger *logrus.Logger) BankService {
+func NewSolidBankService(config config.BankConfig, logger *logrus.Logger) BankService {
 	return &solidBankService{
 		config:     config,
-		httpClient: NewHTTPClient(time.Duration(config.Timeout)*time.Second, logger),
+		httpClient: NewHTTPClient(time.Duration(config.Timeout)*time.Second, NewRetryPolicy(config.Retry), logger),
 		logger:     logger,
 	}
 }
 
 func (s *solidBankService) GetBankName() string {
-	return "SolidBank"
+	return s.config.Name
 }
 
-func (s *solidBankService) SubmitApplication(ctx context.Context, req dto.ApplicationRequest) (*dto.BankSubmissionResponse, error) {
+func (s *solidBankService) SubmitApplication(ctx context.Context, req dto.ApplicationRequest, idempotencyKey string) (*dto.BankSubmissionResponse, error) {
 	logger := s.logger.WithFields(logrus.Fields{
-		"bank":   "SolidBank",
+		"bank":   s.config.Name,
 		"phone":  req.Phone,
 		"amount": req.Amount,
 	})
 
+	warnDroppedLoanFields(logger, mappers.DroppedLoanFields(req))
 	solidBankReq := mappers.ToSolidBankRequestFromApplicationRequest(req)
 	submitURL := fmt.Sprintf("%s/applications", s.config.BaseURL)
 	var solidBankApp dto.SolidBankApplication
 
-	err := s.httpClient.PostJSON(ctx, submitURL, solidBankReq, &solidBankApp)
+	err := s.httpClient.PostJSON(ctx, submitURL, solidBankReq, &solidBankApp, submitRequestOptions(s.config, idempotencyKey)...)
 	if err != nil {
 		logger.WithError(err).Error("Failed to submit application to SolidBank")
 		return nil, fmt.Errorf("SolidBank submission failed: %w", err)
@@ -59,7 +60,7 @@ func (s *solidBankService) SubmitApplication(ctx context.Context, req dto.Applic
 
 func (s *solidBankService) GetOffer(ctx context.Context, bankID string) (*dto.Offer, error) {
 	logger := s.logger.WithFields(logrus.Fields{
-		"bank":    "SolidBank",
+		"bank":    s.config.Name,
 		"bank_id": bankID,
 	})
 
@@ -90,7 +91,7 @@ func (s *solidBankService) handleProcessedApplication(solidBankApp dto.SolidBank
 	} else {
 		logger.Info("SolidBank application already processed but rejected")
 	}
-	offer := mappers.ToOfferFromSolidBankApplication(solidBankApp, bankName)
+	offer := mappers.ToOfferFromSolidBankApplication(solidBankApp, bankName, s.config.Currency)
 	if offer == nil {
 		logger.Error("SolidBank application mapping returned nil offer")
 		return nil, fmt.Errorf("failed to map SolidBank application")
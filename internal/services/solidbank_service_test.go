@@ -3,7 +3,9 @@ package services
 import (
 	"testing"
 
+	"github.com/lielamurs/aggregator/internal/config"
 	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/money"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,7 +16,7 @@ func TestSolidBankService_HandleProcessedApplication(t *testing.T) {
 	logger.SetLevel(logrus.FatalLevel)
 	logEntry := logrus.NewEntry(logger)
 
-	service := &solidBankService{}
+	service := &solidBankService{config: config.BankConfig{Currency: money.EUR}}
 
 	tests := []struct {
 		name            string
@@ -98,8 +100,8 @@ func TestSolidBankService_HandleProcessedApplication(t *testing.T) {
 					require.NotNil(t, offer.AnnualPercentageRate)
 					require.NotNil(t, offer.FirstRepaymentDate)
 
-					assert.Equal(t, tt.application.Offer.MonthlyPaymentAmount, *offer.MonthlyPaymentAmount)
-					assert.Equal(t, tt.application.Offer.TotalRepaymentAmount, *offer.TotalRepaymentAmount)
+					assert.Equal(t, tt.application.Offer.MonthlyPaymentAmount, offer.MonthlyPaymentAmount.Float64())
+					assert.Equal(t, tt.application.Offer.TotalRepaymentAmount, offer.TotalRepaymentAmount.Float64())
 					assert.Equal(t, tt.application.Offer.NumberOfPayments, *offer.NumberOfPayments)
 					assert.Equal(t, tt.application.Offer.AnnualPercentageRate, *offer.AnnualPercentageRate)
 					assert.Equal(t, tt.application.Offer.FirstRepaymentDate, *offer.FirstRepaymentDate)
@@ -123,7 +125,7 @@ func TestSolidBankService_HandleProcessedApplication_EdgeCases(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.FatalLevel)
 	logEntry := logrus.NewEntry(logger)
-	service := &solidBankService{}
+	service := &solidBankService{config: config.BankConfig{Currency: money.EUR}}
 
 	t.Run("zero values in offer should be preserved", func(t *testing.T) {
 		app := dto.SolidBankApplication{
@@ -149,8 +151,8 @@ func TestSolidBankService_HandleProcessedApplication_EdgeCases(t *testing.T) {
 		require.NotNil(t, offer.AnnualPercentageRate)
 		require.NotNil(t, offer.FirstRepaymentDate)
 
-		assert.Equal(t, 0.0, *offer.MonthlyPaymentAmount)
-		assert.Equal(t, 0.0, *offer.TotalRepaymentAmount)
+		assert.Equal(t, eur("0.0"), *offer.MonthlyPaymentAmount)
+		assert.Equal(t, eur("0.0"), *offer.TotalRepaymentAmount)
 		assert.Equal(t, 0, *offer.NumberOfPayments)
 		assert.Equal(t, 0.0, *offer.AnnualPercentageRate)
 		assert.Equal(t, "", *offer.FirstRepaymentDate)
@@ -186,7 +188,7 @@ func TestSolidBankService_HandleProcessedApplication_LogMessages(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.InfoLevel)
 	logEntry := logrus.NewEntry(logger)
-	service := &solidBankService{}
+	service := &solidBankService{config: config.BankConfig{Currency: money.EUR}}
 
 	t.Run("approved application should log correct message", func(t *testing.T) {
 		app := dto.SolidBankApplication{
@@ -225,7 +227,7 @@ func TestSolidBankService_HandleProcessedApplication_OfferValues(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.FatalLevel)
 	logEntry := logrus.NewEntry(logger)
-	service := &solidBankService{}
+	service := &solidBankService{config: config.BankConfig{Currency: money.EUR}}
 
 	t.Run("high value offer should work", func(t *testing.T) {
 		app := dto.SolidBankApplication{
@@ -245,8 +247,8 @@ func TestSolidBankService_HandleProcessedApplication_OfferValues(t *testing.T) {
 		require.NotNil(t, offer)
 		assert.Equal(t, dto.OfferStatusApproved, offer.Status)
 
-		assert.Equal(t, 9999.99, *offer.MonthlyPaymentAmount)
-		assert.Equal(t, 119999.88, *offer.TotalRepaymentAmount)
+		assert.Equal(t, eur("9999.99"), *offer.MonthlyPaymentAmount)
+		assert.Equal(t, eur("119999.88"), *offer.TotalRepaymentAmount)
 		assert.Equal(t, 120, *offer.NumberOfPayments)
 		assert.Equal(t, 25.99, *offer.AnnualPercentageRate)
 		assert.Equal(t, "2024-12-31", *offer.FirstRepaymentDate)
@@ -270,8 +272,8 @@ func TestSolidBankService_HandleProcessedApplication_OfferValues(t *testing.T) {
 		require.NotNil(t, offer)
 		assert.Equal(t, dto.OfferStatusApproved, offer.Status)
 
-		assert.Equal(t, 0.01, *offer.MonthlyPaymentAmount)
-		assert.Equal(t, 0.12, *offer.TotalRepaymentAmount)
+		assert.Equal(t, eur("0.01"), *offer.MonthlyPaymentAmount)
+		assert.Equal(t, eur("0.12"), *offer.TotalRepaymentAmount)
 		assert.Equal(t, 1, *offer.NumberOfPayments)
 		assert.Equal(t, 0.01, *offer.AnnualPercentageRate)
 		assert.Equal(t, "2024-01-01", *offer.FirstRepaymentDate)
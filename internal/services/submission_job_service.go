@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/mappers"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/repository"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type SubmissionJobService interface {
+	ClaimJobs(ctx context.Context, limit int) ([]models.SubmissionJob, error)
+	ProcessJob(ctx context.Context, job *models.SubmissionJob)
+	ReleaseJob(ctx context.Context, job *models.SubmissionJob)
+}
+
+type submissionJobService struct {
+	applicationsRepo    *repository.ApplicationsRepository
+	bankSubmissionsRepo *repository.BankSubmissionsRepository
+	submissionJobsRepo  *repository.SubmissionJobsRepository
+	transactor          *repository.Transactor
+	stateMachine        *ApplicationStateMachine
+	events              *ApplicationEventRecorder
+	bankServices        []BankService
+	pollSchedules       PollSchedules
+	eligibility         EligibilityRules
+	config              config.SubmissionWorkersConfig
+	retryPolicy         RetryPolicy
+	workerID            string
+	logger              *logrus.Logger
+}
+
+func NewSubmissionJobService(
+	applicationsRepo *repository.ApplicationsRepository,
+	bankSubmissionsRepo *repository.BankSubmissionsRepository,
+	submissionJobsRepo *repository.SubmissionJobsRepository,
+	transactor *repository.Transactor,
+	stateMachine *ApplicationStateMachine,
+	events *ApplicationEventRecorder,
+	bankServices []BankService,
+	pollSchedules PollSchedules,
+	eligibility EligibilityRules,
+	config config.SubmissionWorkersConfig,
+	logger *logrus.Logger,
+) SubmissionJobService {
+	return &submissionJobService{
+		applicationsRepo:    applicationsRepo,
+		bankSubmissionsRepo: bankSubmissionsRepo,
+		submissionJobsRepo:  submissionJobsRepo,
+		transactor:          transactor,
+		stateMachine:        stateMachine,
+		events:              events,
+		bankServices:        bankServices,
+		pollSchedules:       pollSchedules,
+		eligibility:         eligibility,
+		config:              config,
+		retryPolicy: RetryPolicy{
+			BaseDelay: 5 * time.Second,
+			MaxDelay:  5 * time.Minute,
+			Jitter:    0.2,
+		},
+		workerID: newWorkerID(),
+		logger:   logger,
+	}
+}
+
+func (s *submissionJobService) ClaimJobs(ctx context.Context, limit int) ([]models.SubmissionJob, error) {
+	lease := time.Duration(s.config.LeaseSeconds) * time.Second
+	return s.submissionJobsRepo.ClaimDue(ctx, s.workerID, limit, lease)
+}
+
+func (s *submissionJobService) ProcessJob(ctx context.Context, job *models.SubmissionJob) {
+	logger := s.logger.WithFields(logrus.Fields{
+		"application_id": job.ApplicationID,
+		"bank":           job.BankName,
+		"job_id":         job.ID,
+		"attempt":        job.Attempts,
+	})
+
+	submitCtx, cancel := context.WithTimeout(ctx, s.jobTimeout())
+	err := s.submitToBank(submitCtx, job, logger)
+	cancel()
+	if err == nil {
+		return
+	}
+
+	errorMsg := err.Error()
+	if errors.Is(err, repository.ErrLeaseLost) {
+		logger.WithError(err).Warn("Submission job lease lost, another worker owns the job")
+		return
+	}
+
+	if job.Attempts >= s.config.MaxAttempts {
+		logger.WithError(err).Error("Submission job failed permanently")
+
+		finishErr := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.saveBankSubmission(ctx, job.ApplicationID, job.BankName, dto.SubmissionStatusFailed, "", err); err != nil {
+				return err
+			}
+			return s.submissionJobsRepo.Finish(ctx, job, dto.JobStatusFailed, &errorMsg)
+		})
+		if finishErr != nil {
+			logger.WithError(finishErr).Error("Failed to mark submission job as failed")
+		}
+		return
+	}
+
+	runAt := time.Now().Add(s.retryPolicy.Backoff(job.Attempts))
+	logger.WithError(err).WithField("run_at", runAt).Warn("Submission job failed, rescheduling")
+
+	if rescheduleErr := s.submissionJobsRepo.Reschedule(ctx, job, runAt, errorMsg); rescheduleErr != nil {
+		logger.WithError(rescheduleErr).Error("Failed to reschedule submission job")
+	}
+}
+
+// ReleaseJob hands a claimed job that was never started back to the queue,
+// e.g. when the pool shuts down before a worker got to it.
+func (s *submissionJobService) ReleaseJob(ctx context.Context, job *models.SubmissionJob) {
+	if err := s.submissionJobsRepo.Release(ctx, job); err != nil && !errors.Is(err, repository.ErrLeaseLost) {
+		s.logger.WithError(err).WithField("job_id", job.ID).Error("Failed to release submission job")
+	}
+}
+
+// jobTimeout bounds a single attempt so it finishes, and records its
+// outcome, while the job's lease is still held.
+func (s *submissionJobService) jobTimeout() time.Duration {
+	lease := time.Duration(s.config.LeaseSeconds) * time.Second
+	return lease - lease/5
+}
+
+// submitToBank sends the application to the job's bank and records the
+// outcome as a bank submission. Errors returned are internal failures that
+// warrant another attempt; bank rejections are recorded, not returned.
+func (s *submissionJobService) submitToBank(ctx context.Context, job *models.SubmissionJob, logger *logrus.Entry) error {
+	application, err := s.applicationsRepo.GetByID(ctx, job.ApplicationID)
+	if err != nil {
+		return fmt.Errorf("failed to get application: %w", err)
+	}
+
+	switch dto.ApplicationStatus(application.Status) {
+	case dto.StatusPending:
+		if _, err := s.stateMachine.Transition(ctx, application.ID, dto.StatusPending, dto.StatusProcessing, dto.EventActorSubmissionWorker, ""); err != nil {
+			return err
+		}
+	case dto.StatusProcessing:
+	default:
+		logger.WithField("status", application.Status).Info("Application no longer active, skipping submission")
+		return s.submissionJobsRepo.Finish(ctx, job, dto.JobStatusDone, nil)
+	}
+
+	_, err = s.bankSubmissionsRepo.GetByApplicationAndBank(ctx, job.ApplicationID, job.BankName)
+	if err == nil {
+		logger.Info("Bank submission already recorded, completing job")
+		return s.submissionJobsRepo.Finish(ctx, job, dto.JobStatusDone, nil)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to check existing bank submission: %w", err)
+	}
+
+	var status dto.BankSubmissionStatus
+	var bankID string
+	submissionErr := s.eligibility.Check(job.BankName, application)
+	if submissionErr != nil {
+		logger.WithField("reason", submissionErr.Error()).Info("Application not eligible at bank, skipping submission")
+		status = dto.SubmissionStatusSkipped
+	} else {
+		status, bankID, submissionErr = s.callBank(ctx, job.BankName, application, job.ID.String(), logger)
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.saveBankSubmission(ctx, job.ApplicationID, job.BankName, status, bankID, submissionErr); err != nil {
+			return fmt.Errorf("failed to save bank submission: %w", err)
+		}
+		return s.submissionJobsRepo.Finish(ctx, job, dto.JobStatusDone, nil)
+	})
+}
+
+// callBank submits the application to the bank. The job ID is the
+// idempotency key, so a job re-run after a crash is recognised by banks that
+// deduplicate submissions.
+func (s *submissionJobService) callBank(ctx context.Context, bankName string, application *models.Application, idempotencyKey string, logger *logrus.Entry) (dto.BankSubmissionStatus, string, error) {
+	var bank BankService
+	for _, bankService := range s.bankServices {
+		if bankService.GetBankName() == bankName {
+			bank = bankService
+			break
+		}
+	}
+
+	if bank == nil {
+		logger.Error("Bank service not found")
+		return dto.SubmissionStatusFailed, "", fmt.Errorf("bank service not found for %s", bankName)
+	}
+
+	logger.Info("Submitting application to bank")
+
+	response, err := bank.SubmitApplication(ctx, mappers.ToApplicationRequestFromModel(application), idempotencyKey)
+	if errors.Is(err, ErrCircuitOpen) {
+		logger.WithError(err).Warn("Bank circuit breaker open, submission scheduled for retry")
+		return dto.SubmissionStatusRetry, "", err
+	}
+
+	if err != nil {
+		logger.WithError(err).Error("Bank submission failed")
+		return dto.SubmissionStatusFailed, "", err
+	}
+
+	logger.WithField("submission_id", response.ID).Info("Bank submission successful")
+	return dto.SubmissionStatusDraft, response.ID, nil
+}
+
+func (s *submissionJobService) saveBankSubmission(ctx context.Context, applicationID uuid.UUID, bankName string, status dto.BankSubmissionStatus, bankID string, submissionErr error) error {
+	now := time.Now()
+	bankSubmission := &dto.BankSubmission{
+		ID:        uuid.New(),
+		BankName:  bankName,
+		Status:    status,
+		BankID:    bankID,
+		CreatedAt: now,
+	}
+
+	if status == dto.SubmissionStatusDraft {
+		bankSubmission.SubmittedAt = now
+	}
+
+	if status == dto.SubmissionStatusDraft || status == dto.SubmissionStatusRetry {
+		nextPollAt := s.pollSchedules.For(bankName).Next(now, 0)
+		bankSubmission.NextPollAt = &nextPollAt
+	}
+
+	if submissionErr != nil {
+		errorMsg := submissionErr.Error()
+		bankSubmission.ErrorMessage = &errorMsg
+	}
+
+	var ineligible *IneligibleError
+	if errors.As(submissionErr, &ineligible) {
+		bankSubmission.Error = ineligible.Rule
+	}
+
+	submission := mappers.ToBankSubmissionModel(bankSubmission)
+	if submission == nil {
+		return fmt.Errorf("failed to convert bank submission to model")
+	}
+
+	submission.ApplicationID = applicationID
+	if err := s.bankSubmissionsRepo.Create(ctx, submission); err != nil {
+		return err
+	}
+	return s.events.SubmissionStatusChanged(ctx, submission, "", dto.EventActorSubmissionWorker)
+}
@@ -2,10 +2,12 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/config"
 	"github.com/lielamurs/aggregator/internal/dto"
 	"github.com/lielamurs/aggregator/internal/mappers"
 	"github.com/lielamurs/aggregator/internal/models"
@@ -13,15 +15,27 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const decisionDeadlineExceeded = "DECISION_DEADLINE_EXCEEDED"
+
 type SubmissionService interface {
 	ProcessSubmissions(ctx context.Context) error
+	CancelSubmissions(ctx context.Context, applicationID uuid.UUID, actor dto.EventActor) error
+	WithdrawSubmissions(ctx context.Context, applicationID uuid.UUID) error
 }
 
 type submissionService struct {
 	applicationsRepo    *repository.ApplicationsRepository
 	offersRepo          *repository.OffersRepository
 	bankSubmissionsRepo *repository.BankSubmissionsRepository
+	transactor          *repository.Transactor
+	stateMachine        *ApplicationStateMachine
+	events              *ApplicationEventRecorder
 	bankServices        []BankService
+	pollSchedules       PollSchedules
+	config              config.SubmissionProcessorConfig
+	verification        config.OfferVerificationConfig
+	affordability       config.AffordabilityConfig
+	workerID            string
 	logger              *logrus.Logger
 }
 
@@ -29,142 +43,474 @@ func NewSubmissionService(
 	applicationsRepo *repository.ApplicationsRepository,
 	offersRepo *repository.OffersRepository,
 	bankSubmissionsRepo *repository.BankSubmissionsRepository,
+	transactor *repository.Transactor,
+	stateMachine *ApplicationStateMachine,
+	events *ApplicationEventRecorder,
 	bankServices []BankService,
+	pollSchedules PollSchedules,
+	config config.SubmissionProcessorConfig,
+	verification config.OfferVerificationConfig,
+	affordability config.AffordabilityConfig,
 	logger *logrus.Logger,
 ) SubmissionService {
 	return &submissionService{
 		applicationsRepo:    applicationsRepo,
 		offersRepo:          offersRepo,
 		bankSubmissionsRepo: bankSubmissionsRepo,
+		transactor:          transactor,
+		stateMachine:        stateMachine,
+		events:              events,
 		bankServices:        bankServices,
+		pollSchedules:       pollSchedules,
+		config:              config,
+		verification:        verification,
+		affordability:       affordability,
+		workerID:            newWorkerID(),
 		logger:              logger,
 	}
 }
 
 func (s *submissionService) ProcessSubmissions(ctx context.Context) error {
-	logger := s.logger.WithField("component", "submission_processor")
+	logger := s.logger.WithFields(logrus.Fields{
+		"component": "submission_processor",
+		"worker_id": s.workerID,
+	})
 	logger.Info("Starting submission processing cycle")
 
-	processingApplications, err := s.applicationsRepo.GetProcessingApplicationsWithBankSubmissions(ctx)
+	// The batch is polled one submission at a time under a single lease, so
+	// bank calls are cut off in time for every outcome to be recorded while
+	// the lease is still held, like submission jobs are.
+	lease := time.Duration(s.config.LeaseSeconds) * time.Second
+	deadline := time.Now().Add(lease - lease/5)
+	submissions, err := s.bankSubmissionsRepo.ClaimDue(ctx, s.workerID, max(s.config.BatchSize, 1), lease)
 	if err != nil {
-		logger.WithError(err).Error("Failed to get processing applications")
-		return fmt.Errorf("failed to get processing applications: %w", err)
+		logger.WithError(err).Error("Failed to claim bank submissions")
+		return fmt.Errorf("failed to claim bank submissions: %w", err)
 	}
 
-	logger.WithField("count", len(processingApplications)).Info("Found processing applications")
+	logger.WithField("count", len(submissions)).Info("Claimed bank submissions")
 
-	for _, app := range processingApplications {
-		if err := s.processApplication(ctx, &app); err != nil {
-			logger.WithError(err).WithField("application_id", app.ID).Error("Failed to process application")
+	for i, submission := range submissions {
+		if !time.Now().Before(deadline) {
+			s.handBackSubmissions(ctx, submissions[i:], logger)
+			break
 		}
+
+		var err error
+		switch {
+		case s.decisionDeadlinePassed(&submission):
+			err = s.timeOutSubmission(ctx, &submission)
+		case submission.Status == string(dto.SubmissionStatusRetry):
+			err = s.resubmitApplication(ctx, &submission, deadline)
+		default:
+			err = s.processSubmission(ctx, &submission, deadline)
+		}
+
+		if errors.Is(err, repository.ErrLeaseLost) {
+			logger.WithError(err).WithField("submission_id", submission.ID).Warn("Bank submission lease lost, another replica owns it")
+		} else if err != nil {
+			logger.WithError(err).WithFields(logrus.Fields{
+				"application_id": submission.ApplicationID,
+				"bank":           submission.BankName,
+			}).Error("Failed to process submission")
+		}
+	}
+
+	if err := s.finishSettledApplications(ctx, logger); err != nil {
+		return err
+	}
+
+	if err := s.expireStaleApplications(ctx, logger); err != nil {
+		return err
 	}
 
 	logger.Info("Submission processing cycle completed")
 	return nil
 }
 
-func (s *submissionService) processApplication(ctx context.Context, app *models.Application) error {
-	logger := s.logger.WithField("application_id", app.ID)
-	logger.Info("Processing application submissions")
+// handBackSubmissions gives up the lease on submissions the cycle ran out of
+// time for, so the next cycle can claim them without waiting for the lease
+// to lapse.
+func (s *submissionService) handBackSubmissions(ctx context.Context, submissions []models.BankSubmission, logger *logrus.Entry) {
+	logger.WithField("count", len(submissions)).Warn("Lease running out, handing back unprocessed bank submissions")
 
-	draftSubmissions := make([]models.BankSubmission, 0)
-	for _, submission := range app.BankSubmissions {
-		if submission.Status == string(dto.SubmissionStatusDraft) {
-			draftSubmissions = append(draftSubmissions, submission)
+	for i := range submissions {
+		err := s.bankSubmissionsRepo.Release(ctx, &submissions[i], s.workerID)
+		if err != nil && !errors.Is(err, repository.ErrLeaseLost) {
+			logger.WithError(err).WithField("submission_id", submissions[i].ID).Error("Failed to hand back bank submission")
 		}
 	}
+}
 
-	if len(draftSubmissions) == 0 {
-		logger.Info("No draft submissions found for application")
-		return nil
+// pollCutOff reports whether a bank call failed because the batch ran out of
+// lease rather than because of the bank.
+func pollCutOff(ctx, pollCtx context.Context, err error) bool {
+	return err != nil && pollCtx.Err() != nil && ctx.Err() == nil
+}
+
+// finishSettledApplications moves applications whose banks have all
+// answered to COMPLETED, or to FAILED when no bank decided. Transitions
+// are conditional, so replicas racing on the same application finish it
+// only once.
+func (s *submissionService) finishSettledApplications(ctx context.Context, logger *logrus.Entry) error {
+	apps, err := s.applicationsRepo.GetSettledApplications(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get settled applications")
+		return fmt.Errorf("failed to get settled applications: %w", err)
 	}
 
-	logger.WithField("draft_count", len(draftSubmissions)).Info("Found draft submissions")
+	for _, app := range apps {
+		id, current := app.ID, dto.ApplicationStatus(app.Status)
+
+		// A pending application never reached a bank: every job failed
+		// before the first one could move it to processing.
+		allFailed := current == dto.StatusPending
+		if !allFailed {
+			allFailed, err = s.bankSubmissionsRepo.AllFailed(ctx, id)
+			if err != nil {
+				logger.WithError(err).WithField("application_id", id).Error("Failed to check bank submissions")
+				continue
+			}
+		}
+
+		status := dto.StatusCompleted
+		if allFailed {
+			status = dto.StatusFailed
+		}
 
-	allCompleted := true
-	for _, submission := range draftSubmissions {
-		if err := s.processSubmission(ctx, app.ID, &submission); err != nil {
-			logger.WithError(err).WithField("bank", submission.BankName).Error("Failed to process submission")
-			allCompleted = false
+		reason := "all bank submissions finished"
+		if allFailed {
+			reason = "no bank decided on the application"
 		}
+
+		if _, err := s.stateMachine.Transition(ctx, id, current, status, dto.EventActorSubmissionProcessor, reason); err != nil {
+			logger.WithError(err).WithField("application_id", id).Error("Failed to finish application")
+		}
+	}
+
+	return nil
+}
+
+func (s *submissionService) expireStaleApplications(ctx context.Context, logger *logrus.Entry) error {
+	if s.config.ApplicationExpiryHours <= 0 {
+		return nil
 	}
 
-	if allCompleted {
-		if err := s.updateApplicationStatus(ctx, app.ID, dto.StatusCompleted); err != nil {
-			logger.WithError(err).Error("Failed to update application status to completed")
-			return fmt.Errorf("failed to update application status: %w", err)
+	before := time.Now().Add(-time.Duration(s.config.ApplicationExpiryHours) * time.Hour)
+	apps, err := s.applicationsRepo.GetActiveApplicationsCreatedBefore(ctx, before)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get stale applications")
+		return fmt.Errorf("failed to get stale applications: %w", err)
+	}
+
+	for _, app := range apps {
+		reason := fmt.Sprintf("not finished within %d hours", s.config.ApplicationExpiryHours)
+		if _, err := s.stateMachine.Transition(ctx, app.ID, dto.ApplicationStatus(app.Status), dto.StatusExpired, dto.EventActorSubmissionProcessor, reason); err != nil {
+			logger.WithError(err).WithField("application_id", app.ID).Error("Failed to expire application")
 		}
-		logger.Info("Application processing completed")
 	}
 
 	return nil
 }
 
-func (s *submissionService) processSubmission(ctx context.Context, applicationID uuid.UUID, submission *models.BankSubmission) error {
+func (s *submissionService) processSubmission(ctx context.Context, submission *models.BankSubmission, deadline time.Time) error {
+	previousStatus := submission.Status
 	logger := s.logger.WithFields(logrus.Fields{
-		"application_id": applicationID,
+		"application_id": submission.ApplicationID,
 		"bank":           submission.BankName,
 		"submission_id":  submission.ID,
 		"bank_id":        submission.BankID,
 	})
 
-	var bankService BankService
-	for _, service := range s.bankServices {
-		if service.GetBankName() == submission.BankName {
-			bankService = service
-			break
-		}
-	}
-
+	bankService := s.findBankService(submission.BankName)
 	if bankService == nil {
 		logger.Error("Bank service not found")
-		return fmt.Errorf("bank service not found for %s", submission.BankName)
+		s.scheduleNextPoll(submission)
+		return s.releaseSubmission(ctx, submission, previousStatus, fmt.Errorf("bank service not found for %s", submission.BankName))
 	}
 
 	if submission.BankID == nil {
 		logger.Error("Bank ID is nil, cannot get offer")
-		return fmt.Errorf("bank ID is nil for submission %s", submission.ID)
+		s.scheduleNextPoll(submission)
+		return s.releaseSubmission(ctx, submission, previousStatus, fmt.Errorf("bank ID is nil for submission %s", submission.ID))
 	}
 
-	offer, err := bankService.GetOffer(ctx, *submission.BankID)
+	pollCtx, cancel := context.WithDeadline(ctx, deadline)
+	offer, err := bankService.GetOffer(pollCtx, *submission.BankID)
+	cancel()
+	if errors.Is(err, ErrCircuitOpen) {
+		logger.WithError(err).Warn("Bank circuit breaker open, polling postponed")
+		s.scheduleNextPoll(submission)
+		return s.releaseSubmission(ctx, submission, previousStatus, nil)
+	}
+	if pollCutOff(ctx, pollCtx, err) {
+		logger.WithError(err).Warn("Bank poll cut off before the lease ran out, polling postponed")
+		return s.releaseSubmission(ctx, submission, previousStatus, nil)
+	}
+
+	submission.PollAttempts++
+	now := time.Now()
+
 	if err != nil {
 		logger.WithError(err).Error("Failed to get offer from bank")
 
 		submission.Status = string(dto.SubmissionStatusFailed)
 		errorMsg := err.Error()
 		submission.ErrorMessage = &errorMsg
-		now := time.Now()
 		submission.CompletedAt = &now
+		submission.NextPollAt = nil
 
-		if updateErr := s.bankSubmissionsRepo.Update(ctx, submission); updateErr != nil {
-			logger.WithError(updateErr).Error("Failed to update failed submission")
-		}
-
-		return fmt.Errorf("failed to get offer: %w", err)
+		return s.releaseSubmission(ctx, submission, previousStatus, fmt.Errorf("failed to get offer: %w", err))
 	}
 
 	if offer == nil {
-		logger.Debug("Application not yet processed by bank")
-		return nil
+		s.scheduleNextPoll(submission)
+		logger.WithFields(logrus.Fields{
+			"poll_attempts": submission.PollAttempts,
+			"next_poll_at":  submission.NextPollAt,
+		}).Debug("Application not yet processed by bank")
+		return s.releaseSubmission(ctx, submission, previousStatus, nil)
 	}
 
 	logger.Info("Successfully retrieved offer from bank")
 
-	if err := s.saveOffer(ctx, applicationID, offer); err != nil {
-		logger.WithError(err).Error("Failed to save offer")
-		return fmt.Errorf("failed to save offer: %w", err)
+	submission.Status = string(dto.SubmissionStatusSuccess)
+	submission.CompletedAt = &now
+	submission.NextPollAt = nil
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.bankSubmissionsRepo.Release(ctx, submission, s.workerID); err != nil {
+			return err
+		}
+		if err := s.saveOffer(ctx, submission.ApplicationID, offer); err != nil {
+			return fmt.Errorf("failed to save offer: %w", err)
+		}
+		return s.events.SubmissionStatusChanged(ctx, submission, previousStatus, dto.EventActorSubmissionProcessor)
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to save successful submission")
+		return err
 	}
 
-	submission.Status = string(dto.SubmissionStatusSuccess)
+	logger.Info("Submission processed successfully")
+	return nil
+}
+
+// scheduleNextPoll pushes the submission's next poll back according to its
+// bank's poll schedule, but never past its decision deadline so the timeout
+// is noticed on time.
+func (s *submissionService) scheduleNextPoll(submission *models.BankSubmission) {
+	schedule := s.pollSchedules.For(submission.BankName)
+	nextPollAt := schedule.Next(time.Now(), submission.PollAttempts)
+	if deadline, ok := schedule.Deadline(submission.SubmittedAt); ok && nextPollAt.After(deadline) {
+		nextPollAt = deadline
+	}
+	submission.NextPollAt = &nextPollAt
+}
+
+func (s *submissionService) decisionDeadlinePassed(submission *models.BankSubmission) bool {
+	deadline, ok := s.pollSchedules.For(submission.BankName).Deadline(submission.SubmittedAt)
+	return ok && !time.Now().Before(deadline)
+}
+
+func (s *submissionService) timeOutSubmission(ctx context.Context, submission *models.BankSubmission) error {
+	previousStatus := submission.Status
+	deadline := s.pollSchedules.For(submission.BankName).DecisionDeadline
+	s.logger.WithFields(logrus.Fields{
+		"application_id": submission.ApplicationID,
+		"bank":           submission.BankName,
+		"submission_id":  submission.ID,
+		"poll_attempts":  submission.PollAttempts,
+		"deadline":       deadline,
+	}).Warn("Bank did not decide before the deadline, submission timed out")
+
 	now := time.Now()
+	reason := decisionDeadlineExceeded
+	errorMsg := fmt.Sprintf("%s did not decide within %s", submission.BankName, deadline)
+
+	submission.Status = string(dto.SubmissionStatusTimedOut)
+	submission.Error = &reason
+	submission.ErrorMessage = &errorMsg
 	submission.CompletedAt = &now
+	submission.NextPollAt = nil
 
-	if err := s.bankSubmissionsRepo.Update(ctx, submission); err != nil {
-		logger.WithError(err).Error("Failed to update successful submission")
+	return s.releaseSubmission(ctx, submission, previousStatus, nil)
+}
+
+// releaseSubmission saves the submission, records a status change and gives
+// up its lease. processErr is passed through so callers can log a single
+// outcome.
+func (s *submissionService) releaseSubmission(ctx context.Context, submission *models.BankSubmission, previousStatus string, processErr error) error {
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.bankSubmissionsRepo.Release(ctx, submission, s.workerID); err != nil {
+			return err
+		}
+		return s.events.SubmissionStatusChanged(ctx, submission, previousStatus, dto.EventActorSubmissionProcessor)
+	})
+	if err != nil {
 		return fmt.Errorf("failed to update submission: %w", err)
 	}
+	return processErr
+}
 
-	logger.Info("Submission processed successfully")
+func (s *submissionService) resubmitApplication(ctx context.Context, submission *models.BankSubmission, deadline time.Time) error {
+	previousStatus := submission.Status
+	logger := s.logger.WithFields(logrus.Fields{
+		"application_id": submission.ApplicationID,
+		"bank":           submission.BankName,
+		"submission_id":  submission.ID,
+	})
+
+	bankService := s.findBankService(submission.BankName)
+	if bankService == nil {
+		logger.Error("Bank service not found")
+		s.scheduleNextPoll(submission)
+		return s.releaseSubmission(ctx, submission, previousStatus, fmt.Errorf("bank service not found for %s", submission.BankName))
+	}
+
+	app, err := s.applicationsRepo.GetByID(ctx, submission.ApplicationID)
+	if err != nil {
+		s.scheduleNextPoll(submission)
+		return s.releaseSubmission(ctx, submission, previousStatus, fmt.Errorf("failed to get application: %w", err))
+	}
+
+	submitCtx, cancel := context.WithDeadline(ctx, deadline)
+	response, err := bankService.SubmitApplication(submitCtx, mappers.ToApplicationRequestFromModel(app), submission.ID.String())
+	cancel()
+	if errors.Is(err, ErrCircuitOpen) {
+		logger.WithError(err).Warn("Bank circuit breaker still open, submission stays scheduled for retry")
+		s.scheduleNextPoll(submission)
+		return s.releaseSubmission(ctx, submission, previousStatus, nil)
+	}
+	if pollCutOff(ctx, submitCtx, err) {
+		logger.WithError(err).Warn("Bank resubmission cut off before the lease ran out, submission stays scheduled for retry")
+		return s.releaseSubmission(ctx, submission, previousStatus, nil)
+	}
+
+	now := time.Now()
+	if err != nil {
+		logger.WithError(err).Error("Bank resubmission failed")
+
+		submission.Status = string(dto.SubmissionStatusFailed)
+		errorMsg := err.Error()
+		submission.ErrorMessage = &errorMsg
+		submission.CompletedAt = &now
+		submission.NextPollAt = nil
+	} else {
+		logger.WithField("bank_id", response.ID).Info("Bank resubmission successful")
+
+		submission.Status = string(dto.SubmissionStatusDraft)
+		submission.BankID = &response.ID
+		submission.SubmittedAt = &now
+		submission.ErrorMessage = nil
+		submission.PollAttempts = 0
+		s.scheduleNextPoll(submission)
+	}
+
+	if err := s.releaseSubmission(ctx, submission, previousStatus, nil); err != nil {
+		logger.WithError(err).Error("Failed to update resubmitted submission")
+		return err
+	}
+
+	return nil
+}
+
+// CancelSubmissions stops polling and resubmitting the application's DRAFT
+// and RETRY submissions by moving them to CANCELLED. It is meant to run in
+// the transaction that cancels the application.
+func (s *submissionService) CancelSubmissions(ctx context.Context, applicationID uuid.UUID, actor dto.EventActor) error {
+	submissions, err := s.bankSubmissionsRepo.GetByApplicationID(ctx, applicationID)
+	if err != nil {
+		return fmt.Errorf("failed to get bank submissions: %w", err)
+	}
+
+	now := time.Now()
+	for _, submission := range submissions {
+		previousStatus := submission.Status
+		switch dto.BankSubmissionStatus(previousStatus) {
+		case dto.SubmissionStatusDraft, dto.SubmissionStatusRetry:
+		default:
+			continue
+		}
+
+		submission.Status = string(dto.SubmissionStatusCancelled)
+		submission.CompletedAt = &now
+		submission.NextPollAt = nil
+
+		updated, err := s.bankSubmissionsRepo.Cancel(ctx, &submission, previousStatus)
+		if err != nil {
+			return fmt.Errorf("failed to cancel bank submission: %w", err)
+		}
+		if !updated {
+			continue
+		}
+		if err := s.events.SubmissionStatusChanged(ctx, &submission, previousStatus, actor); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WithdrawSubmissions asks every bank holding the application to withdraw
+// it. Banks without a cancellation API are skipped. The outcome is recorded
+// on each submission; a failed withdrawal is not retried.
+func (s *submissionService) WithdrawSubmissions(ctx context.Context, applicationID uuid.UUID) error {
+	submissions, err := s.bankSubmissionsRepo.GetByApplicationID(ctx, applicationID)
+	if err != nil {
+		return fmt.Errorf("failed to get bank submissions: %w", err)
+	}
+
+	for _, submission := range submissions {
+		if submission.BankID == nil || submission.WithdrawnAt != nil {
+			continue
+		}
+
+		logger := s.logger.WithFields(logrus.Fields{
+			"application_id": applicationID,
+			"bank":           submission.BankName,
+			"submission_id":  submission.ID,
+			"bank_id":        *submission.BankID,
+		})
+
+		var withdrawErr error
+		if bankService := s.findBankService(submission.BankName); bankService == nil {
+			withdrawErr = fmt.Errorf("bank service not found for %s", submission.BankName)
+		} else {
+			withdrawErr = cancelApplication(ctx, bankService, *submission.BankID)
+		}
+		if errors.Is(withdrawErr, ErrApplicationCancellationNotSupported) {
+			logger.Debug("Bank has no cancellation API, application not withdrawn")
+			continue
+		}
+
+		var withdrawnAt *time.Time
+		var withdrawalError *string
+		if withdrawErr != nil {
+			logger.WithError(withdrawErr).Warn("Failed to withdraw application from bank")
+			errorMsg := withdrawErr.Error()
+			withdrawalError = &errorMsg
+		} else {
+			logger.Info("Application withdrawn from bank")
+			now := time.Now()
+			withdrawnAt = &now
+		}
+
+		if err := s.bankSubmissionsRepo.UpdateWithdrawal(ctx, submission.ID, withdrawnAt, withdrawalError); err != nil {
+			logger.WithError(err).Error("Failed to record bank withdrawal")
+			return fmt.Errorf("failed to record bank withdrawal: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *submissionService) findBankService(bankName string) BankService {
+	for _, service := range s.bankServices {
+		if service.GetBankName() == bankName {
+			return service
+		}
+	}
 	return nil
 }
 
@@ -181,17 +527,50 @@ func (s *submissionService) saveOffer(ctx context.Context, applicationID uuid.UU
 	}
 
 	offer.ApplicationID = applicationID
-	return s.offersRepo.Create(ctx, offer)
+	offer.CreatedAt = time.Now()
+	if offer.Status == string(dto.OfferStatusApproved) {
+		s.checkOffer(ctx, offer)
+	}
+
+	if err := s.offersRepo.Create(ctx, offer); err != nil {
+		return err
+	}
+	return s.events.OfferReceived(ctx, offer)
 }
 
-func (s *submissionService) updateApplicationStatus(ctx context.Context, applicationID uuid.UUID, status dto.ApplicationStatus) error {
-	app, err := s.applicationsRepo.GetByID(ctx, applicationID)
+// checkOffer flags stated figures that do not match the offer's payments
+// and payments the applicant cannot afford. Offers that cannot be checked
+// are saved as they are.
+func (s *submissionService) checkOffer(ctx context.Context, offer *models.Offer) {
+	logger := s.logger.WithFields(logrus.Fields{
+		"application_id": offer.ApplicationID,
+		"bank":           offer.BankName,
+	})
+
+	app, err := s.applicationsRepo.GetByID(ctx, offer.ApplicationID)
 	if err != nil {
-		return fmt.Errorf("failed to get application: %w", err)
+		logger.WithError(err).Warn("Failed to load application, offer not checked")
+		return
 	}
 
-	app.Status = string(status)
-	app.UpdatedAt = time.Now()
+	if err := verifyOffer(offer, app.Amount, s.verification); err != nil {
+		logger.WithError(err).Warn("Offer could not be verified")
+	} else if offer.Discrepancies != "" {
+		logger.WithFields(logrus.Fields{
+			"discrepancies": offer.Discrepancies,
+			"stated_apr":    offer.AnnualPercentageRate,
+			"verified_apr":  offer.VerifiedAnnualPercentageRate,
+			"stated_total":  offer.TotalRepaymentAmount,
+		}).Warn("Offer figures deviate from the recomputed ones")
+	}
 
-	return s.applicationsRepo.Update(ctx, app)
+	if err := assessOffer(offer, app, s.affordability); err != nil {
+		logger.WithError(err).Warn("Offer affordability could not be assessed")
+	} else if offer.AffordabilityIssues != "" {
+		logger.WithFields(logrus.Fields{
+			"affordability_issues": offer.AffordabilityIssues,
+			"debt_service_ratio":   offer.DebtServiceRatio,
+			"monthly_payment":      offer.MonthlyPaymentAmount,
+		}).Warn("Offer is not affordable for the applicant")
+	}
 }
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/money"
+	"github.com/lielamurs/aggregator/internal/repository"
+	"github.com/lielamurs/aggregator/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type countingBankService struct {
+	name  string
+	polls atomic.Int32
+}
+
+func (b *countingBankService) GetBankName() string {
+	return b.name
+}
+
+func (b *countingBankService) SubmitApplication(ctx context.Context, req dto.ApplicationRequest, idempotencyKey string) (*dto.BankSubmissionResponse, error) {
+	return &dto.BankSubmissionResponse{ID: "bank-id", Status: "RECEIVED"}, nil
+}
+
+func (b *countingBankService) GetOffer(ctx context.Context, bankID string) (*dto.Offer, error) {
+	b.polls.Add(1)
+	payment := money.New(10000, money.EUR)
+	return &dto.Offer{BankName: b.name, MonthlyPaymentAmount: &payment, Status: "PROCESSED"}, nil
+}
+
+func createProcessingApplication(t *testing.T, db *gorm.DB) uuid.UUID {
+	t.Helper()
+
+	app := &models.Application{
+		ID:              uuid.New(),
+		Phone:           "+37120000000",
+		Email:           "john@example.com",
+		MonthlyIncome:   eur("2000"),
+		MonthlyExpenses: eur("500"),
+		MaritalStatus:   "SINGLE",
+		AgreeToBeScored: true,
+		Amount:          eur("5000"),
+		Status:          string(dto.StatusProcessing),
+	}
+	require.NoError(t, db.Create(app).Error)
+	return app.ID
+}
+
+func createDraftSubmission(t *testing.T, db *gorm.DB, applicationID uuid.UUID, bankName string, submittedAt time.Time) *models.BankSubmission {
+	t.Helper()
+
+	bankID := "bank-" + applicationID.String()
+	submission := &models.BankSubmission{
+		ID:            uuid.New(),
+		ApplicationID: applicationID,
+		BankName:      bankName,
+		Status:        string(dto.SubmissionStatusDraft),
+		BankID:        &bankID,
+		SubmittedAt:   &submittedAt,
+	}
+	require.NoError(t, db.Create(submission).Error)
+	return submission
+}
+
+func newTestSubmissionService(db *gorm.DB, bank BankService, pollSchedules PollSchedules) SubmissionService {
+	return newTestSubmissionServiceWithConfig(db, bank, pollSchedules,
+		config.SubmissionProcessorConfig{BatchSize: 3, LeaseSeconds: 60, ApplicationExpiryHours: 24})
+}
+
+func newTestSubmissionServiceWithConfig(db *gorm.DB, bank BankService, pollSchedules PollSchedules, processorConfig config.SubmissionProcessorConfig) SubmissionService {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	applicationsRepo := repository.NewApplicationsRepository(db)
+	transactor := repository.NewTransactor(db)
+	events := NewApplicationEventRecorder(repository.NewApplicationEventsRepository(db), NewWebhookPublisher(applicationsRepo, repository.NewWebhooksRepository(db)))
+	return NewSubmissionService(
+		applicationsRepo,
+		repository.NewOffersRepository(db),
+		repository.NewBankSubmissionsRepository(db),
+		transactor,
+		NewApplicationStateMachine(applicationsRepo, transactor, events, logger),
+		events,
+		[]BankService{bank},
+		pollSchedules,
+		processorConfig,
+		config.OfferVerificationConfig{APRTolerance: 0.1, AmountTolerance: 1},
+		config.AffordabilityConfig{SubsistenceMinimum: 500, DependentAllowance: 250, MaxDebtServiceRatio: 0.4},
+		logger,
+	)
+}
+
+func TestSubmissionService_ConcurrentReplicas(t *testing.T) {
+	db := testutil.OpenPostgres(t)
+	ctx := context.Background()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	bank := &countingBankService{name: "FastBank"}
+	applicationIDs := make([]uuid.UUID, 20)
+	for i := range applicationIDs {
+		app := &models.Application{
+			ID:              uuid.New(),
+			Phone:           "+37120000000",
+			Email:           "john@example.com",
+			MonthlyIncome:   eur("2000"),
+			MonthlyExpenses: eur("500"),
+			MaritalStatus:   "SINGLE",
+			AgreeToBeScored: true,
+			Amount:          eur("5000"),
+			Status:          string(dto.StatusProcessing),
+		}
+		require.NoError(t, db.Create(app).Error)
+		applicationIDs[i] = app.ID
+
+		bankID := "bank-" + app.ID.String()
+		require.NoError(t, db.Create(&models.BankSubmission{
+			ID:            uuid.New(),
+			ApplicationID: app.ID,
+			BankName:      bank.name,
+			Status:        string(dto.SubmissionStatusDraft),
+			BankID:        &bankID,
+		}).Error)
+	}
+
+	newReplica := func() SubmissionService {
+		applicationsRepo := repository.NewApplicationsRepository(db)
+		transactor := repository.NewTransactor(db)
+		events := NewApplicationEventRecorder(repository.NewApplicationEventsRepository(db), NewWebhookPublisher(applicationsRepo, repository.NewWebhooksRepository(db)))
+		return NewSubmissionService(
+			applicationsRepo,
+			repository.NewOffersRepository(db),
+			repository.NewBankSubmissionsRepository(db),
+			transactor,
+			NewApplicationStateMachine(applicationsRepo, transactor, events, logger),
+			events,
+			[]BankService{bank},
+			PollSchedules{},
+			config.SubmissionProcessorConfig{BatchSize: 3, LeaseSeconds: 60},
+			config.OfferVerificationConfig{APRTolerance: 0.1, AmountTolerance: 1},
+			config.AffordabilityConfig{SubsistenceMinimum: 500, DependentAllowance: 250, MaxDebtServiceRatio: 0.4},
+			logger,
+		)
+	}
+
+	var wg sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		replica := newReplica()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				assert.NoError(t, replica.ProcessSubmissions(ctx))
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(20), bank.polls.Load())
+
+	var offers int64
+	require.NoError(t, db.Model(&models.Offer{}).Count(&offers).Error)
+	assert.Equal(t, int64(20), offers)
+
+	var completed int64
+	require.NoError(t, db.Model(&models.Application{}).
+		Where("id IN ? AND status = ?", applicationIDs, dto.StatusCompleted).
+		Count(&completed).Error)
+	assert.Equal(t, int64(20), completed)
+}
+
+// hangingBankService never answers a poll before the caller gives up.
+type hangingBankService struct {
+	countingBankService
+}
+
+func (b *hangingBankService) GetOffer(ctx context.Context, bankID string) (*dto.Offer, error) {
+	b.polls.Add(1)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestSubmissionService_LeaseDeadline(t *testing.T) {
+	db := testutil.OpenPostgres(t)
+	ctx := context.Background()
+
+	bank := &hangingBankService{countingBankService: countingBankService{name: "FastBank"}}
+	service := newTestSubmissionServiceWithConfig(db, bank, PollSchedules{},
+		config.SubmissionProcessorConfig{BatchSize: 2, LeaseSeconds: 1, ApplicationExpiryHours: 24})
+
+	first := createDraftSubmission(t, db, createProcessingApplication(t, db), bank.name, time.Now())
+	second := createDraftSubmission(t, db, createProcessingApplication(t, db), bank.name, time.Now())
+
+	started := time.Now()
+	require.NoError(t, service.ProcessSubmissions(ctx))
+	assert.Less(t, time.Since(started), time.Second, "the batch should finish within its lease")
+	assert.Equal(t, int32(1), bank.polls.Load())
+
+	for _, id := range []uuid.UUID{first.ID, second.ID} {
+		var stored models.BankSubmission
+		require.NoError(t, db.First(&stored, "id = ?", id).Error)
+		assert.Equal(t, string(dto.SubmissionStatusDraft), stored.Status, "a cut-off poll should not fail the submission")
+		assert.Nil(t, stored.LockedBy, "the lease should be given up")
+	}
+}
+
+func TestSubmissionService_DecisionDeadline(t *testing.T) {
+	db := testutil.OpenPostgres(t)
+	ctx := context.Background()
+
+	bank := &countingBankService{name: "FastBank"}
+	schedules := NewPollSchedules([]config.BankConfig{
+		{Name: bank.name, Poll: config.PollConfig{DecisionDeadlineSeconds: 3600}},
+	})
+
+	applicationID := createProcessingApplication(t, db)
+	submission := createDraftSubmission(t, db, applicationID, bank.name, time.Now().Add(-2*time.Hour))
+
+	require.NoError(t, newTestSubmissionService(db, bank, schedules).ProcessSubmissions(ctx))
+
+	var stored models.BankSubmission
+	require.NoError(t, db.First(&stored, "id = ?", submission.ID).Error)
+	assert.Equal(t, string(dto.SubmissionStatusTimedOut), stored.Status)
+	require.NotNil(t, stored.Error)
+	assert.Equal(t, decisionDeadlineExceeded, *stored.Error)
+	require.NotNil(t, stored.ErrorMessage)
+	assert.Contains(t, *stored.ErrorMessage, "FastBank did not decide within 1h0m0s")
+	assert.Nil(t, stored.NextPollAt)
+	assert.Equal(t, int32(0), bank.polls.Load())
+
+	var app models.Application
+	require.NoError(t, db.First(&app, "id = ?", applicationID).Error)
+	assert.Equal(t, string(dto.StatusFailed), app.Status)
+
+	events, err := repository.NewApplicationEventsRepository(db).GetByApplicationID(ctx, applicationID)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	assert.Equal(t, string(dto.EventEntityBankSubmission), events[0].EntityType)
+	assert.Equal(t, string(dto.SubmissionStatusDraft), *events[0].OldStatus)
+	assert.Equal(t, string(dto.SubmissionStatusTimedOut), events[0].NewStatus)
+	assert.Equal(t, "FastBank did not decide within 1h0m0s", *events[0].Reason)
+
+	assert.Equal(t, string(dto.EventEntityApplication), events[1].EntityType)
+	assert.Equal(t, string(dto.StatusProcessing), *events[1].OldStatus)
+	assert.Equal(t, string(dto.StatusFailed), events[1].NewStatus)
+	assert.Equal(t, string(dto.EventActorSubmissionProcessor), events[1].Actor)
+}
+
+func TestSubmissionService_DecisionDeadlineCountsFromSubmission(t *testing.T) {
+	db := testutil.OpenPostgres(t)
+	ctx := context.Background()
+
+	bank := &countingBankService{name: "FastBank"}
+	schedules := NewPollSchedules([]config.BankConfig{
+		{Name: bank.name, Poll: config.PollConfig{DecisionDeadlineSeconds: 3600}},
+	})
+
+	applicationID := createProcessingApplication(t, db)
+	submission := createDraftSubmission(t, db, applicationID, bank.name, time.Now())
+	require.NoError(t, db.Model(submission).Update("created_at", time.Now().Add(-2*time.Hour)).Error)
+
+	require.NoError(t, newTestSubmissionService(db, bank, schedules).ProcessSubmissions(ctx))
+
+	var stored models.BankSubmission
+	require.NoError(t, db.First(&stored, "id = ?", submission.ID).Error)
+	assert.Equal(t, string(dto.SubmissionStatusSuccess), stored.Status, "a resubmission should get the full deadline")
+	assert.Equal(t, int32(1), bank.polls.Load())
+}
+
+func TestSubmissionService_FinishesApplications(t *testing.T) {
+	db := testutil.OpenPostgres(t)
+	ctx := context.Background()
+	bank := &countingBankService{name: "FastBank"}
+
+	failedID := createProcessingApplication(t, db)
+	for _, bankName := range []string{"FastBank", "SolidBank"} {
+		require.NoError(t, db.Create(&models.BankSubmission{
+			ID:            uuid.New(),
+			ApplicationID: failedID,
+			BankName:      bankName,
+			Status:        string(dto.SubmissionStatusFailed),
+		}).Error)
+	}
+
+	neverSubmittedID := createProcessingApplication(t, db)
+	require.NoError(t, db.Model(&models.Application{}).Where("id = ?", neverSubmittedID).
+		Update("status", dto.StatusPending).Error)
+	require.NoError(t, db.Create(&models.SubmissionJob{
+		ID:            uuid.New(),
+		ApplicationID: neverSubmittedID,
+		BankName:      bank.name,
+		Status:        string(dto.JobStatusFailed),
+		Attempts:      5,
+		RunAt:         time.Now(),
+	}).Error)
+
+	staleID := createProcessingApplication(t, db)
+	require.NoError(t, db.Model(&models.Application{}).Where("id = ?", staleID).
+		Update("created_at", time.Now().Add(-48*time.Hour)).Error)
+	pending := createDraftSubmission(t, db, staleID, bank.name, time.Now())
+	require.NoError(t, db.Model(pending).Update("next_poll_at", time.Now().Add(time.Hour)).Error)
+
+	require.NoError(t, newTestSubmissionService(db, bank, PollSchedules{}).ProcessSubmissions(ctx))
+
+	var failed, neverSubmitted, stale models.Application
+	require.NoError(t, db.First(&failed, "id = ?", failedID).Error)
+	require.NoError(t, db.First(&neverSubmitted, "id = ?", neverSubmittedID).Error)
+	require.NoError(t, db.First(&stale, "id = ?", staleID).Error)
+	assert.Equal(t, string(dto.StatusFailed), failed.Status)
+	assert.Equal(t, string(dto.StatusFailed), neverSubmitted.Status)
+	assert.Equal(t, string(dto.StatusExpired), stale.Status)
+	assert.Equal(t, int32(0), bank.polls.Load())
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+type SubmissionJobNotifier interface {
+	Notify()
+}
+
+type SubmissionWorkerPool struct {
+	jobService SubmissionJobService
+	config     config.SubmissionWorkersConfig
+	logger     *logrus.Logger
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	jobs       chan models.SubmissionJob
+	idle       chan struct{}
+	wake       chan struct{}
+	running    bool
+	mu         sync.RWMutex
+}
+
+func NewSubmissionWorkerPool(
+	jobService SubmissionJobService,
+	config config.SubmissionWorkersConfig,
+	logger *logrus.Logger,
+) *SubmissionWorkerPool {
+	return &SubmissionWorkerPool{
+		jobService: jobService,
+		config:     config,
+		logger:     logger,
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+func (p *SubmissionWorkerPool) Start() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.running {
+		return nil
+	}
+
+	workers := max(p.config.Workers, 1)
+	p.logger.WithField("workers", workers).Info("Starting submission worker pool")
+
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	p.jobs = make(chan models.SubmissionJob, workers)
+	p.idle = make(chan struct{}, workers)
+	for i := 0; i < workers; i++ {
+		p.idle <- struct{}{}
+	}
+	p.running = true
+
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+
+	p.wg.Add(1)
+	go p.dispatch(workers)
+
+	return nil
+}
+
+func (p *SubmissionWorkerPool) Stop() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.running {
+		return nil
+	}
+
+	p.logger.Info("Stopping submission worker pool")
+
+	p.cancel()
+	p.wg.Wait()
+	p.running = false
+
+	p.logger.Info("Submission worker pool stopped")
+	return nil
+}
+
+// Notify wakes the dispatcher so newly created jobs are picked up without
+// waiting for the next poll.
+func (p *SubmissionWorkerPool) Notify() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (p *SubmissionWorkerPool) dispatch(workers int) {
+	defer p.wg.Done()
+	defer close(p.jobs)
+
+	logger := p.logger.WithField("component", "submission_worker_pool")
+	ticker := time.NewTicker(time.Duration(max(p.config.PollIntervalSeconds, 1)) * time.Second)
+	defer ticker.Stop()
+
+	logger.Info("Submission worker pool started")
+
+	for {
+		idle := p.waitForIdleWorkers()
+		if idle == 0 {
+			logger.Info("Submission worker pool context cancelled")
+			return
+		}
+
+		claimed := p.dispatchDueJobs(logger, idle)
+		for i := claimed; i < idle; i++ {
+			p.idle <- struct{}{}
+		}
+
+		if claimed == idle {
+			continue
+		}
+
+		select {
+		case <-p.ctx.Done():
+			logger.Info("Submission worker pool context cancelled")
+			return
+		case <-ticker.C:
+		case <-p.wake:
+		}
+	}
+}
+
+// waitForIdleWorkers blocks until at least one worker is idle and reserves
+// every idle worker, so no more jobs are claimed than can start right away
+// and no lease runs down while its job waits for a worker. It returns 0 once
+// the pool is stopping.
+func (p *SubmissionWorkerPool) waitForIdleWorkers() int {
+	select {
+	case <-p.idle:
+	case <-p.ctx.Done():
+		return 0
+	}
+
+	idle := 1
+	for {
+		select {
+		case <-p.idle:
+			idle++
+		default:
+			return idle
+		}
+	}
+}
+
+func (p *SubmissionWorkerPool) dispatchDueJobs(logger *logrus.Entry, limit int) int {
+	jobs, err := p.jobService.ClaimJobs(p.ctx, limit)
+	if err != nil {
+		if p.ctx.Err() == nil {
+			logger.WithError(err).Error("Failed to claim submission jobs")
+		}
+		return 0
+	}
+
+	if len(jobs) > 0 {
+		logger.WithField("count", len(jobs)).Debug("Claimed submission jobs")
+	}
+
+	for _, job := range jobs {
+		p.jobs <- job
+	}
+
+	return len(jobs)
+}
+
+// work processes dispatched jobs. Jobs still queued when the pool stops are
+// released instead, so another worker can pick them up without waiting for
+// their lease to expire.
+func (p *SubmissionWorkerPool) work() {
+	defer p.wg.Done()
+
+	for job := range p.jobs {
+		ctx := context.WithoutCancel(p.ctx)
+		if p.ctx.Err() != nil {
+			p.jobService.ReleaseJob(ctx, &job)
+		} else {
+			p.jobService.ProcessJob(ctx, &job)
+		}
+		p.idle <- struct{}{}
+	}
+}
+
+func newWorkerID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), uuid.NewString()[:8])
+}
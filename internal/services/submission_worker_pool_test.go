@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSubmissionJobService struct {
+	mu        sync.Mutex
+	pending   []models.SubmissionJob
+	processed []uuid.UUID
+	released  []uuid.UUID
+	claims    []int
+	block     chan struct{}
+	done      chan struct{}
+}
+
+func (f *fakeSubmissionJobService) ClaimJobs(ctx context.Context, limit int) ([]models.SubmissionJob, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.claims = append(f.claims, limit)
+	n := min(limit, len(f.pending))
+	claimed := f.pending[:n]
+	f.pending = f.pending[n:]
+	return claimed, nil
+}
+
+func (f *fakeSubmissionJobService) ProcessJob(ctx context.Context, job *models.SubmissionJob) {
+	if f.block != nil {
+		<-f.block
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.processed = append(f.processed, job.ID)
+	f.done <- struct{}{}
+}
+
+func (f *fakeSubmissionJobService) ReleaseJob(ctx context.Context, job *models.SubmissionJob) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.released = append(f.released, job.ID)
+}
+
+func (f *fakeSubmissionJobService) add(count int) []uuid.UUID {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ids := make([]uuid.UUID, count)
+	for i := range ids {
+		ids[i] = uuid.New()
+		f.pending = append(f.pending, models.SubmissionJob{ID: ids[i]})
+	}
+	return ids
+}
+
+func waitForJobs(t *testing.T, done chan struct{}, count int) {
+	t.Helper()
+
+	for i := 0; i < count; i++ {
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for job %d of %d", i+1, count)
+		}
+	}
+}
+
+func TestSubmissionWorkerPool(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	jobService := &fakeSubmissionJobService{done: make(chan struct{}, 100)}
+	resumed := jobService.add(5)
+
+	pool := NewSubmissionWorkerPool(jobService, config.SubmissionWorkersConfig{
+		Workers:             2,
+		PollIntervalSeconds: 60,
+	}, logger)
+
+	require.NoError(t, pool.Start())
+	require.NoError(t, pool.Start())
+
+	waitForJobs(t, jobService.done, len(resumed))
+
+	created := jobService.add(3)
+	pool.Notify()
+	waitForJobs(t, jobService.done, len(created))
+
+	require.NoError(t, pool.Stop())
+	require.NoError(t, pool.Stop())
+
+	assert.ElementsMatch(t, append(resumed, created...), jobService.processed)
+}
+
+func TestSubmissionWorkerPool_ClaimsOnlyForIdleWorkers(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	jobService := &fakeSubmissionJobService{done: make(chan struct{}, 100), block: make(chan struct{})}
+	ids := jobService.add(5)
+
+	pool := NewSubmissionWorkerPool(jobService, config.SubmissionWorkersConfig{
+		Workers:             2,
+		PollIntervalSeconds: 60,
+	}, logger)
+	require.NoError(t, pool.Start())
+
+	require.Eventually(t, func() bool {
+		jobService.mu.Lock()
+		defer jobService.mu.Unlock()
+		return len(jobService.pending) == 3
+	}, 2*time.Second, 10*time.Millisecond)
+
+	pool.Notify()
+	time.Sleep(50 * time.Millisecond)
+
+	jobService.mu.Lock()
+	assert.Len(t, jobService.pending, 3, "jobs must not be claimed while every worker is busy")
+	jobService.mu.Unlock()
+
+	close(jobService.block)
+	waitForJobs(t, jobService.done, len(ids))
+	require.NoError(t, pool.Stop())
+
+	assert.ElementsMatch(t, ids, jobService.processed)
+	for _, limit := range jobService.claims {
+		assert.LessOrEqual(t, limit, 2)
+	}
+}
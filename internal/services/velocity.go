@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/repository"
+)
+
+var (
+	ErrDuplicateApplication  = errors.New("duplicate of an application in progress")
+	ErrVelocityLimitExceeded = errors.New("too many applications")
+)
+
+const (
+	VelocityRulePhone = "PHONE"
+	VelocityRuleEmail = "EMAIL"
+	VelocityRuleIP    = "IP"
+)
+
+// DuplicateApplicationError names the application in progress that a new
+// application duplicates.
+type DuplicateApplicationError struct {
+	ApplicationID uuid.UUID
+}
+
+func (e *DuplicateApplicationError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrDuplicateApplication, e.ApplicationID)
+}
+
+func (e *DuplicateApplicationError) Unwrap() error {
+	return ErrDuplicateApplication
+}
+
+// VelocityError names the velocity limit a new application is over.
+type VelocityError struct {
+	Rule   string
+	Limit  int
+	Window time.Duration
+}
+
+func (e *VelocityError) Error() string {
+	return fmt.Sprintf("%v: %s allows %d per %s", ErrVelocityLimitExceeded, e.Rule, e.Limit, e.Window)
+}
+
+func (e *VelocityError) Unwrap() error {
+	return ErrVelocityLimitExceeded
+}
+
+type velocityRule struct {
+	name   string
+	limit  int
+	filter repository.ApplicationFilter
+}
+
+func velocityRules(cfg config.VelocityConfig, application *models.Application) []velocityRule {
+	rules := []velocityRule{
+		{name: VelocityRulePhone, limit: cfg.MaxPerPhone, filter: repository.ApplicationFilter{Phone: application.Phone}},
+		{name: VelocityRuleEmail, limit: cfg.MaxPerEmail, filter: repository.ApplicationFilter{Email: application.Email}},
+	}
+	if application.ClientIP != nil {
+		rules = append(rules, velocityRule{name: VelocityRuleIP, limit: cfg.MaxPerIP, filter: repository.ApplicationFilter{ClientIP: *application.ClientIP}})
+	}
+	return rules
+}
+
+// checkVelocity applies the duplicate and velocity rules to a new
+// application before it is stored. It returns the response of an existing
+// application when the request duplicates it, and an error when the
+// application is rejected. Applications over a limit are always rejected:
+// the applications they are counted against may belong to other customers
+// sharing an IP address.
+func (s *applicationService) checkVelocity(ctx context.Context, application *models.Application) (*dto.ApplicationResponse, error) {
+	if s.velocity.DuplicateAction != config.VelocityActionOff {
+		duplicate, err := s.applicationsRepo.FindInProgressDuplicate(ctx, application)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up duplicate applications: %w", err)
+		}
+		if duplicate != nil {
+			if s.velocity.DuplicateAction == config.VelocityActionReject {
+				return nil, &DuplicateApplicationError{ApplicationID: duplicate.ID}
+			}
+			return linkedApplicationResponse(duplicate, dto.LinkReasonDuplicate), nil
+		}
+	}
+
+	if s.velocity.LimitAction != config.VelocityActionReject || s.velocity.WindowMinutes <= 0 {
+		return nil, nil
+	}
+
+	window := time.Duration(s.velocity.WindowMinutes) * time.Minute
+	since := time.Now().Add(-window)
+	for _, rule := range velocityRules(s.velocity, application) {
+		if rule.limit <= 0 {
+			continue
+		}
+
+		filter := rule.filter
+		filter.CreatedFrom = &since
+		count, err := s.applicationsRepo.Count(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count recent applications: %w", err)
+		}
+		if count >= int64(rule.limit) {
+			return nil, &VelocityError{Rule: rule.name, Limit: rule.limit, Window: window}
+		}
+	}
+
+	return nil, nil
+}
+
+func linkedApplicationResponse(application *models.Application, reason dto.ApplicationLinkReason) *dto.ApplicationResponse {
+	return &dto.ApplicationResponse{
+		ID:           application.ID,
+		Status:       dto.ApplicationStatus(application.Status),
+		LinkedReason: reason,
+	}
+}
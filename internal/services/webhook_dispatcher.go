@@ -0,0 +1,286 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/repository"
+	"github.com/lielamurs/aggregator/internal/safeurl"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	HeaderWebhookEvent     = "X-Webhook-Event"
+	HeaderWebhookID        = "X-Webhook-ID"
+	HeaderWebhookDelivery  = "X-Webhook-Delivery"
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp"
+	HeaderWebhookSignature = "X-Webhook-Signature"
+
+	maxWebhookErrorBody = 1024
+)
+
+// SignWebhookPayload returns the X-Webhook-Signature value for a body sent
+// at the given Unix timestamp: the hex HMAC-SHA256 of "<timestamp>.<body>"
+// keyed with the endpoint's secret.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookDispatcher sends webhook deliveries from the outbox. Deliveries are
+// leased like bank submissions, so several replicas can dispatch at once, and
+// failed deliveries are retried with exponential backoff.
+type WebhookDispatcher struct {
+	webhooksRepo *repository.WebhooksRepository
+	client       *http.Client
+	urls         safeurl.Policy
+	config       config.WebhooksConfig
+	retryPolicy  RetryPolicy
+	workerID     string
+	logger       *logrus.Logger
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	running      bool
+	mu           sync.RWMutex
+}
+
+func NewWebhookDispatcher(
+	webhooksRepo *repository.WebhooksRepository,
+	config config.WebhooksConfig,
+	logger *logrus.Logger,
+) *WebhookDispatcher {
+	timeout := time.Duration(config.TimeoutSeconds) * time.Second
+	urls := safeurl.Policy{AllowInsecure: config.AllowInsecureURLs}
+
+	// Endpoints are dialed directly, never through a proxy, so the dialer
+	// sees and can refuse the address a webhook URL resolves to.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = urls.Dialer(timeout).DialContext
+
+	return &WebhookDispatcher{
+		webhooksRepo: webhooksRepo,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		urls:   urls,
+		config: config,
+		retryPolicy: RetryPolicy{
+			BaseDelay: time.Duration(config.BaseDelaySeconds) * time.Second,
+			MaxDelay:  time.Duration(config.MaxDelaySeconds) * time.Second,
+			Jitter:    0.2,
+		},
+		workerID: newWorkerID(),
+		logger:   logger,
+	}
+}
+
+func (d *WebhookDispatcher) Start() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.running {
+		return nil
+	}
+
+	d.logger.WithField("interval_seconds", d.config.PollIntervalSeconds).Info("Starting webhook dispatcher")
+
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	d.running = true
+
+	d.wg.Add(1)
+	go d.run()
+
+	return nil
+}
+
+func (d *WebhookDispatcher) Stop() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.running {
+		return nil
+	}
+
+	d.logger.Info("Stopping webhook dispatcher")
+
+	d.cancel()
+	d.wg.Wait()
+	d.running = false
+
+	d.logger.Info("Webhook dispatcher stopped")
+	return nil
+}
+
+func (d *WebhookDispatcher) run() {
+	defer d.wg.Done()
+
+	logger := d.logger.WithField("component", "webhook_dispatcher")
+	ticker := time.NewTicker(time.Duration(max(d.config.PollIntervalSeconds, 1)) * time.Second)
+	defer ticker.Stop()
+
+	logger.Info("Webhook dispatcher started")
+
+	for {
+		// In-flight deliveries are finished on shutdown rather than cut off.
+		if _, err := d.DispatchDue(context.WithoutCancel(d.ctx)); err != nil {
+			logger.WithError(err).Error("Failed to dispatch webhooks")
+		}
+
+		select {
+		case <-d.ctx.Done():
+			logger.Info("Webhook dispatcher context cancelled")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue sends one batch of due deliveries and returns how many were
+// attempted.
+func (d *WebhookDispatcher) DispatchDue(ctx context.Context) (int, error) {
+	lease := time.Duration(d.config.LeaseSeconds) * time.Second
+	deliveries, err := d.webhooksRepo.ClaimDueDeliveries(ctx, d.workerID, max(d.config.BatchSize, 1), lease)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+
+	eventIDs := make([]uuid.UUID, len(deliveries))
+	for i, delivery := range deliveries {
+		eventIDs[i] = delivery.EventID
+	}
+
+	events, err := d.webhooksRepo.GetEventsByIDs(ctx, eventIDs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get webhook events: %w", err)
+	}
+
+	eventsByID := make(map[uuid.UUID]*models.WebhookEvent, len(events))
+	for i := range events {
+		eventsByID[events[i].ID] = &events[i]
+	}
+
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		wg.Add(1)
+		go func(delivery *models.WebhookDelivery) {
+			defer wg.Done()
+			d.deliver(ctx, delivery, eventsByID[delivery.EventID])
+		}(&deliveries[i])
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery *models.WebhookDelivery, event *models.WebhookEvent) {
+	logger := d.logger.WithFields(logrus.Fields{
+		"application_id": delivery.ApplicationID,
+		"delivery_id":    delivery.ID,
+		"event_type":     delivery.EventType,
+		"url":            delivery.URL,
+	})
+
+	delivery.Attempts++
+	started := time.Now()
+	statusCode, sendErr := d.send(ctx, delivery, event)
+	now := time.Now()
+
+	attempt := &models.WebhookDeliveryAttempt{
+		ID:         uuid.New(),
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts,
+		StatusCode: statusCode,
+		DurationMs: now.Sub(started).Milliseconds(),
+		CreatedAt:  now,
+	}
+
+	switch {
+	case sendErr == nil:
+		delivery.Status = string(dto.WebhookDeliveryDelivered)
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		logger.Info("Webhook delivered")
+	case delivery.Attempts >= max(d.config.MaxAttempts, 1):
+		errorMsg := sendErr.Error()
+		attempt.Error = &errorMsg
+		delivery.Status = string(dto.WebhookDeliveryFailed)
+		delivery.NextAttemptAt = nil
+		logger.WithError(sendErr).WithField("attempts", delivery.Attempts).Error("Webhook delivery failed permanently")
+	default:
+		errorMsg := sendErr.Error()
+		attempt.Error = &errorMsg
+		nextAttemptAt := now.Add(d.retryPolicy.Backoff(delivery.Attempts))
+		delivery.NextAttemptAt = &nextAttemptAt
+		logger.WithError(sendErr).WithField("next_attempt_at", nextAttemptAt).Warn("Webhook delivery failed, rescheduling")
+	}
+
+	err := d.webhooksRepo.ReleaseDelivery(ctx, delivery, attempt, d.workerID)
+	if errors.Is(err, repository.ErrLeaseLost) {
+		logger.WithError(err).Warn("Webhook delivery lease lost, another dispatcher owns it")
+	} else if err != nil {
+		logger.WithError(err).Error("Failed to save webhook delivery")
+	}
+}
+
+func (d *WebhookDispatcher) send(ctx context.Context, delivery *models.WebhookDelivery, event *models.WebhookEvent) (*int, error) {
+	if event == nil {
+		return nil, fmt.Errorf("webhook event %s not found", delivery.EventID)
+	}
+
+	if err := d.urls.CheckScheme(delivery.URL); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(event.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderWebhookEvent, event.EventType)
+	req.Header.Set(HeaderWebhookID, event.ID.String())
+	req.Header.Set(HeaderWebhookDelivery, delivery.ID.String())
+	req.Header.Set(HeaderWebhookTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderWebhookSignature, SignWebhookPayload(delivery.Secret, timestamp, event.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	statusCode := resp.StatusCode
+	if statusCode < 200 || statusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookErrorBody))
+		return &statusCode, fmt.Errorf("endpoint responded with HTTP %d: %s", statusCode, strings.TrimSpace(string(body)))
+	}
+
+	return &statusCode, nil
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/config"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/repository"
+	"github.com/lielamurs/aggregator/internal/safeurl"
+	"github.com/lielamurs/aggregator/internal/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	signature := SignWebhookPayload("whsec_test", 1700000000, []byte(`{"id":1}`))
+
+	assert.Equal(t, "sha256=2f441ba4b3b2d50d28a9ab9d9fd8880376ecd1eb5d0435401553f5d8d0a5dcf8", signature)
+	assert.NotEqual(t, signature, SignWebhookPayload("whsec_test", 1700000001, []byte(`{"id":1}`)))
+	assert.NotEqual(t, signature, SignWebhookPayload("whsec_other", 1700000000, []byte(`{"id":1}`)))
+}
+
+func createWebhookApplication(t *testing.T, db *gorm.DB, callbackURL, clientID string) *models.Application {
+	t.Helper()
+
+	secret := "callback-secret-0123"
+	app := &models.Application{
+		ID:              uuid.New(),
+		Phone:           "+37120000000",
+		Email:           "john@example.com",
+		MonthlyIncome:   eur("2000"),
+		MonthlyExpenses: eur("500"),
+		MaritalStatus:   "SINGLE",
+		AgreeToBeScored: true,
+		Amount:          eur("5000"),
+		Status:          string(dto.StatusProcessing),
+	}
+	if callbackURL != "" {
+		app.CallbackURL = &callbackURL
+		app.CallbackSecret = &secret
+	}
+	if clientID != "" {
+		app.ClientID = &clientID
+	}
+	require.NoError(t, db.Create(app).Error)
+	return app
+}
+
+func TestWebhookPublisher_Targets(t *testing.T) {
+	db := testutil.OpenPostgres(t)
+	ctx := context.Background()
+	webhooksRepo := repository.NewWebhooksRepository(db)
+	publisher := NewWebhookPublisher(repository.NewApplicationsRepository(db), webhooksRepo)
+
+	for _, subscription := range []models.WebhookSubscription{
+		{ID: uuid.New(), ClientID: "portal", URL: "https://portal.example.com/all", Secret: "portal-secret-0123"},
+		{ID: uuid.New(), ClientID: "portal", URL: "https://portal.example.com/offers", Secret: "portal-secret-0123", EventTypes: "offer.received"},
+		{ID: uuid.New(), ClientID: "other", URL: "https://other.example.com/all", Secret: "other-secret-01234"},
+	} {
+		require.NoError(t, webhooksRepo.CreateSubscription(ctx, &subscription))
+	}
+
+	app := createWebhookApplication(t, db, "https://callback.example.com/hook", "portal")
+
+	require.NoError(t, db.Model(app).Update("status", dto.StatusCompleted).Error)
+	require.NoError(t, publisher.ApplicationStatusChanged(ctx, app.ID, dto.StatusCompleted))
+	require.NoError(t, publisher.ApplicationStatusChanged(ctx, app.ID, dto.StatusProcessing))
+
+	deliveries, err := webhooksRepo.GetDeliveriesByApplicationID(ctx, app.ID)
+	require.NoError(t, err)
+
+	urls := make([]string, len(deliveries))
+	for i, delivery := range deliveries {
+		urls[i] = delivery.URL
+		assert.Equal(t, string(dto.WebhookEventApplicationCompleted), delivery.EventType)
+	}
+	assert.ElementsMatch(t, []string{"https://callback.example.com/hook", "https://portal.example.com/all"}, urls)
+
+	var event models.WebhookEvent
+	require.NoError(t, db.First(&event, "id = ?", deliveries[0].EventID).Error)
+
+	var payload struct {
+		Type string `json:"type"`
+		Data struct {
+			ID     uuid.UUID `json:"id"`
+			Status string    `json:"status"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(event.Payload, &payload))
+	assert.Equal(t, "application.completed", payload.Type)
+	assert.Equal(t, app.ID, payload.Data.ID)
+	assert.Equal(t, "COMPLETED", payload.Data.Status)
+}
+
+func TestWebhookDispatcher_DispatchDue(t *testing.T) {
+	db := testutil.OpenPostgres(t)
+	ctx := context.Background()
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	applicationsRepo := repository.NewApplicationsRepository(db)
+	webhooksRepo := repository.NewWebhooksRepository(db)
+	publisher := NewWebhookPublisher(applicationsRepo, webhooksRepo)
+	cfg := config.WebhooksConfig{BatchSize: 10, LeaseSeconds: 60, TimeoutSeconds: 5, MaxAttempts: 2, AllowInsecureURLs: true}
+
+	t.Run("delivery should be signed and marked delivered", func(t *testing.T) {
+		var received atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			timestamp, err := strconv.ParseInt(r.Header.Get(HeaderWebhookTimestamp), 10, 64)
+			if err != nil || r.Header.Get(HeaderWebhookSignature) != SignWebhookPayload("callback-secret-0123", timestamp, body) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Equal(t, "submission.failed", r.Header.Get(HeaderWebhookEvent))
+			received.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		app := createWebhookApplication(t, db, server.URL, "")
+		errorMsg := "HTTP 500"
+		require.NoError(t, publisher.SubmissionStatusChanged(ctx, &models.BankSubmission{
+			ID:            uuid.New(),
+			ApplicationID: app.ID,
+			BankName:      "FastBank",
+			Status:        string(dto.SubmissionStatusFailed),
+			ErrorMessage:  &errorMsg,
+		}))
+
+		dispatched, err := NewWebhookDispatcher(webhooksRepo, cfg, logger).DispatchDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, dispatched)
+		assert.Equal(t, int32(1), received.Load())
+
+		deliveries, err := webhooksRepo.GetDeliveriesByApplicationID(ctx, app.ID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, string(dto.WebhookDeliveryDelivered), deliveries[0].Status)
+		assert.NotNil(t, deliveries[0].DeliveredAt)
+		require.Len(t, deliveries[0].AttemptLog, 1)
+		assert.Equal(t, http.StatusNoContent, *deliveries[0].AttemptLog[0].StatusCode)
+	})
+
+	t.Run("failing endpoint should be retried and then given up", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		app := createWebhookApplication(t, db, server.URL, "")
+		require.NoError(t, db.Model(app).Update("status", dto.StatusFailed).Error)
+		require.NoError(t, publisher.ApplicationStatusChanged(ctx, app.ID, dto.StatusFailed))
+
+		dispatcher := NewWebhookDispatcher(webhooksRepo, cfg, logger)
+
+		_, err := dispatcher.DispatchDue(ctx)
+		require.NoError(t, err)
+
+		deliveries, err := webhooksRepo.GetDeliveriesByApplicationID(ctx, app.ID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, string(dto.WebhookDeliveryPending), deliveries[0].Status)
+		assert.Equal(t, 1, deliveries[0].Attempts)
+		require.NotNil(t, deliveries[0].NextAttemptAt)
+
+		require.NoError(t, db.Model(&models.WebhookDelivery{}).Where("id = ?", deliveries[0].ID).
+			Update("next_attempt_at", gorm.Expr("NOW() - INTERVAL '1 second'")).Error)
+
+		_, err = dispatcher.DispatchDue(ctx)
+		require.NoError(t, err)
+
+		deliveries, err = webhooksRepo.GetDeliveriesByApplicationID(ctx, app.ID)
+		require.NoError(t, err)
+		assert.Equal(t, string(dto.WebhookDeliveryFailed), deliveries[0].Status)
+		assert.Equal(t, 2, deliveries[0].Attempts)
+		require.Len(t, deliveries[0].AttemptLog, 2)
+		assert.Contains(t, *deliveries[0].AttemptLog[1].Error, "HTTP 503")
+	})
+
+	t.Run("private endpoint should not be called", func(t *testing.T) {
+		var received atomic.Int32
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		app := createWebhookApplication(t, db, server.URL, "")
+		require.NoError(t, db.Model(app).Update("status", dto.StatusFailed).Error)
+		require.NoError(t, publisher.ApplicationStatusChanged(ctx, app.ID, dto.StatusFailed))
+
+		secure := cfg
+		secure.AllowInsecureURLs = false
+		_, err := NewWebhookDispatcher(webhooksRepo, secure, logger).DispatchDue(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(0), received.Load())
+
+		deliveries, err := webhooksRepo.GetDeliveriesByApplicationID(ctx, app.ID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Len(t, deliveries[0].AttemptLog, 1)
+		assert.Contains(t, *deliveries[0].AttemptLog[0].Error, safeurl.ErrPrivateAddress.Error())
+	})
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/mappers"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/repository"
+)
+
+var applicationWebhookEvents = map[dto.ApplicationStatus]dto.WebhookEventType{
+	dto.StatusCompleted: dto.WebhookEventApplicationCompleted,
+	dto.StatusFailed:    dto.WebhookEventApplicationFailed,
+	dto.StatusCancelled: dto.WebhookEventApplicationCancelled,
+	dto.StatusExpired:   dto.WebhookEventApplicationExpired,
+	dto.StatusAccepted:  dto.WebhookEventApplicationAccepted,
+}
+
+// WebhookPublisher writes webhook events to the outbox: one event row and a
+// delivery per endpoint that should receive it, i.e. the application's own
+// callback and the subscriptions of the client that submitted it. It must be
+// called in the transaction that makes the change the event describes; the
+// WebhookDispatcher sends the deliveries once it commits.
+type WebhookPublisher struct {
+	applicationsRepo *repository.ApplicationsRepository
+	webhooksRepo     *repository.WebhooksRepository
+}
+
+func NewWebhookPublisher(applicationsRepo *repository.ApplicationsRepository, webhooksRepo *repository.WebhooksRepository) *WebhookPublisher {
+	return &WebhookPublisher{
+		applicationsRepo: applicationsRepo,
+		webhooksRepo:     webhooksRepo,
+	}
+}
+
+func (p *WebhookPublisher) ApplicationStatusChanged(ctx context.Context, applicationID uuid.UUID, to dto.ApplicationStatus) error {
+	eventType, ok := applicationWebhookEvents[to]
+	if !ok {
+		return nil
+	}
+
+	application, err := p.applicationsRepo.GetByID(ctx, applicationID)
+	if err != nil {
+		return fmt.Errorf("failed to get application: %w", err)
+	}
+
+	return p.publish(ctx, application, eventType, mappers.ToApplicationStatusResponseFromModel(application))
+}
+
+func (p *WebhookPublisher) SubmissionStatusChanged(ctx context.Context, submission *models.BankSubmission) error {
+	switch dto.BankSubmissionStatus(submission.Status) {
+	case dto.SubmissionStatusFailed, dto.SubmissionStatusTimedOut:
+	default:
+		return nil
+	}
+
+	application, err := p.applicationsRepo.GetByID(ctx, submission.ApplicationID)
+	if err != nil {
+		return fmt.Errorf("failed to get application: %w", err)
+	}
+
+	return p.publish(ctx, application, dto.WebhookEventSubmissionFailed, mappers.ToBankSubmissionFromModel(submission))
+}
+
+func (p *WebhookPublisher) OfferReceived(ctx context.Context, offer *models.Offer) error {
+	application, err := p.applicationsRepo.GetByID(ctx, offer.ApplicationID)
+	if err != nil {
+		return fmt.Errorf("failed to get application: %w", err)
+	}
+
+	return p.publish(ctx, application, dto.WebhookEventOfferReceived, mappers.ToOfferFromModel(offer))
+}
+
+func (p *WebhookPublisher) publish(ctx context.Context, application *models.Application, eventType dto.WebhookEventType, data any) error {
+	now := time.Now()
+	var deliveries []models.WebhookDelivery
+
+	if application.CallbackURL != nil && application.CallbackSecret != nil {
+		deliveries = append(deliveries, models.WebhookDelivery{
+			URL:    *application.CallbackURL,
+			Secret: *application.CallbackSecret,
+		})
+	}
+
+	if application.ClientID != nil {
+		subscriptions, err := p.webhooksRepo.GetSubscriptionsByClientID(ctx, *application.ClientID)
+		if err != nil {
+			return fmt.Errorf("failed to get webhook subscriptions: %w", err)
+		}
+
+		for _, subscription := range subscriptions {
+			if !subscribedTo(&subscription, eventType) {
+				continue
+			}
+			deliveries = append(deliveries, models.WebhookDelivery{
+				SubscriptionID: &subscription.ID,
+				URL:            subscription.URL,
+				Secret:         subscription.Secret,
+			})
+		}
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	event := dto.WebhookEvent{
+		ID:            uuid.New(),
+		Type:          eventType,
+		ApplicationID: application.ID,
+		CreatedAt:     now,
+		Data:          data,
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	for i := range deliveries {
+		deliveries[i].ID = uuid.New()
+		deliveries[i].EventID = event.ID
+		deliveries[i].ApplicationID = application.ID
+		deliveries[i].EventType = string(eventType)
+		deliveries[i].Status = string(dto.WebhookDeliveryPending)
+		deliveries[i].NextAttemptAt = &now
+		deliveries[i].CreatedAt = now
+		deliveries[i].UpdatedAt = now
+	}
+
+	err = p.webhooksRepo.CreateEvent(ctx, &models.WebhookEvent{
+		ID:            event.ID,
+		ApplicationID: application.ID,
+		EventType:     string(eventType),
+		Payload:       payload,
+		CreatedAt:     now,
+	}, deliveries)
+	if err != nil {
+		return fmt.Errorf("failed to store webhook event: %w", err)
+	}
+	return nil
+}
+
+func subscribedTo(subscription *models.WebhookSubscription, eventType dto.WebhookEventType) bool {
+	eventTypes := mappers.SplitWebhookEventTypes(subscription.EventTypes)
+	return len(eventTypes) == 0 || slices.Contains(eventTypes, eventType)
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lielamurs/aggregator/internal/dto"
+	"github.com/lielamurs/aggregator/internal/mappers"
+	"github.com/lielamurs/aggregator/internal/models"
+	"github.com/lielamurs/aggregator/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+var ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+
+type WebhookService interface {
+	CreateSubscription(ctx context.Context, clientID string, req *dto.WebhookSubscriptionRequest) (*models.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context, clientID string) ([]models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, clientID string, id uuid.UUID) error
+	GetDeliveries(ctx context.Context, applicationID uuid.UUID) ([]models.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	applicationsRepo *repository.ApplicationsRepository
+	webhooksRepo     *repository.WebhooksRepository
+	logger           *logrus.Logger
+}
+
+func NewWebhookService(
+	applicationsRepo *repository.ApplicationsRepository,
+	webhooksRepo *repository.WebhooksRepository,
+	logger *logrus.Logger,
+) WebhookService {
+	return &webhookService{
+		applicationsRepo: applicationsRepo,
+		webhooksRepo:     webhooksRepo,
+		logger:           logger,
+	}
+}
+
+// CreateSubscription registers a webhook endpoint for every application the
+// client submits. A signing secret is generated when none is given.
+func (s *webhookService) CreateSubscription(ctx context.Context, clientID string, req *dto.WebhookSubscriptionRequest) (*models.WebhookSubscription, error) {
+	secret := req.Secret
+	if secret == "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	subscription := &models.WebhookSubscription{
+		ID:         uuid.New(),
+		ClientID:   clientID,
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: mappers.JoinWebhookEventTypes(req.Events),
+		CreatedAt:  time.Now(),
+	}
+
+	if err := s.webhooksRepo.CreateSubscription(ctx, subscription); err != nil {
+		s.logger.WithError(err).WithField("client_id", clientID).Error("Failed to create webhook subscription")
+		return nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"client_id":       clientID,
+		"subscription_id": subscription.ID,
+		"url":             subscription.URL,
+	}).Info("Webhook subscription created")
+
+	return subscription, nil
+}
+
+func (s *webhookService) ListSubscriptions(ctx context.Context, clientID string) ([]models.WebhookSubscription, error) {
+	subscriptions, err := s.webhooksRepo.GetSubscriptionsByClientID(ctx, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
+
+func (s *webhookService) DeleteSubscription(ctx context.Context, clientID string, id uuid.UUID) error {
+	deleted, err := s.webhooksRepo.DeleteSubscription(ctx, clientID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	if !deleted {
+		return ErrWebhookSubscriptionNotFound
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"client_id":       clientID,
+		"subscription_id": id,
+	}).Info("Webhook subscription deleted")
+	return nil
+}
+
+func (s *webhookService) GetDeliveries(ctx context.Context, applicationID uuid.UUID) ([]models.WebhookDelivery, error) {
+	exists, err := s.applicationsRepo.Exists(ctx, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("application with ID %s not found", applicationID)
+	}
+
+	deliveries, err := s.webhooksRepo.GetDeliveriesByApplicationID(ctx, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
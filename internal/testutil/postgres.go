@@ -0,0 +1,43 @@
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// OpenPostgres connects to the database in TEST_DATABASE_URL, applies
+// init.sql and empties all tables. Tests calling it are skipped when the
+// variable is not set.
+func OpenPostgres(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set, skipping database test")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	_, file, _, _ := runtime.Caller(0)
+	schema, err := os.ReadFile(filepath.Join(filepath.Dir(file), "..", "..", "init.sql"))
+	require.NoError(t, err)
+
+	require.NoError(t, db.Exec(string(schema)).Error)
+	require.NoError(t, db.Exec("TRUNCATE applications, idempotency_keys, webhook_subscriptions CASCADE").Error)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return db
+}